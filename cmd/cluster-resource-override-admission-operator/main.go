@@ -35,6 +35,11 @@ func main() {
 	}
 
 	command.AddCommand(operator.NewStartCommand())
+	command.AddCommand(operator.NewMigrateCommand())
+	command.AddCommand(operator.NewSimulateCommand())
+	command.AddCommand(operator.NewRenderCommand())
+	command.AddCommand(operator.NewLintCommand())
+	command.AddCommand(operator.NewDiagnoseCommand())
 
 	if err := command.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
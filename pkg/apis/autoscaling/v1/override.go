@@ -45,7 +45,8 @@ func (in *PodResourceOverrideSpec) Hash() string {
 }
 
 func (in *ResourceOverrideSpec) Hash() string {
-	value := fmt.Sprintf("PodResourceOverride=%s, PodSelector=%s", in.PodResourceOverride.Hash(), hashLabelSelector(in.PodSelector))
+	value := fmt.Sprintf("PodResourceOverride=%s, PodSelector=%s",
+		in.PodResourceOverride.Hash(), hashLabelSelector(in.PodSelector))
 
 	writer := sha256.New()
 	writer.Write([]byte(value))
@@ -14,11 +14,17 @@ type ResourceOverrideConditionType string
 const (
 	ValidationFailure ResourceOverrideConditionType = "ValidationFailure"
 	Ignored           ResourceOverrideConditionType = "Ignored"
+	Conflict          ResourceOverrideConditionType = "Conflict"
+	NoMatchingPods    ResourceOverrideConditionType = "NoMatchingPods"
+	PolicyViolation   ResourceOverrideConditionType = "PolicyViolation"
 )
 
 const (
-	InvalidParameters   = "InvalidParameters"
-	NamespaceNotOptedIn = "NamespaceNotOptedIn"
+	InvalidParameters      = "InvalidParameters"
+	NamespaceNotOptedIn    = "NamespaceNotOptedIn"
+	OverlappingPodSelector = "OverlappingPodSelector"
+	PodSelectorMatchesNone = "PodSelectorMatchesNone"
+	OutsidePolicyBounds    = "OutsidePolicyBounds"
 )
 
 type ResourceOverrideCondition struct {
@@ -63,6 +69,72 @@ type ResourceOverrideSpec struct {
 
 type ResourceOverrideStatus struct {
 	Conditions []ResourceOverrideCondition `json:"conditions,omitempty" hash:"set"`
+
+	// Effective is the policy applied to the pods selected by this ResourceOverride.
+	// Fields the ResourceOverride leaves at zero fall back to the cluster-wide
+	// ClusterResourceOverride; each field records which of the two it came
+	// from, or that neither of them set it.
+	// It is not set if the ResourceOverride is invalid or not in effect.
+	// +optional
+	Effective *EffectivePodResourceOverride `json:"effective,omitempty"`
+
+	// MatchedPods is the number of pods in the namespace currently selected
+	// by the podSelector, pods that have succeeded or failed are not counted.
+	// It is refreshed up to 30 seconds after a pod change
+	// and is 0 while the namespace is not opted in.
+	MatchedPods int32 `json:"matchedPods"`
+
+	// LastMatchedTime is the last time matchedPods or matchedPodSamples
+	// changed while the podSelector selected at least one pod. It is not
+	// refreshed while the matched pods stay the same.
+	// +optional
+	LastMatchedTime *metav1.Time `json:"lastMatchedTime,omitempty"`
+
+	// MatchedPodSamples holds the names of up to five pods selected by the
+	// podSelector, in lexicographical order.
+	// +optional
+	MatchedPodSamples []string `json:"matchedPodSamples,omitempty"`
+}
+
+// EffectiveValueSource identifies the object an effective override value was taken from.
+type EffectiveValueSource string
+
+const (
+	// SourceResourceOverride means the value is set by the ResourceOverride itself.
+	SourceResourceOverride EffectiveValueSource = "ResourceOverride"
+
+	// SourceClusterResourceOverride means the value is inherited from the ClusterResourceOverride.
+	SourceClusterResourceOverride EffectiveValueSource = "ClusterResourceOverride"
+
+	// SourceUnset means neither the ResourceOverride nor the ClusterResourceOverride set the value.
+	SourceUnset EffectiveValueSource = "Unset"
+)
+
+// EffectivePodResourceOverride is the merged ResourceOverride and ClusterResourceOverride
+// configuration that the admission webhook applies to a pod.
+type EffectivePodResourceOverride struct {
+	ForceSelinuxRelabel         EffectiveBoolValue  `json:"forceSelinuxRelabel"`
+	LimitCPUToMemoryPercent     EffectiveInt64Value `json:"limitCPUToMemoryPercent"`
+	CPURequestToLimitPercent    EffectiveInt64Value `json:"cpuRequestToLimitPercent"`
+	MemoryRequestToLimitPercent EffectiveInt64Value `json:"memoryRequestToLimitPercent"`
+	CPURequestToRequestPercent  EffectiveInt64Value `json:"cpuRequestToRequestPercent"`
+
+	// ClusterResourceOverrideHash is the hash of the ClusterResourceOverride
+	// configuration the effective policy was computed from.
+	// +optional
+	ClusterResourceOverrideHash string `json:"clusterResourceOverrideHash,omitempty"`
+}
+
+// EffectiveBoolValue is an effective boolean override value along with its source.
+type EffectiveBoolValue struct {
+	Value  bool                 `json:"value"`
+	Source EffectiveValueSource `json:"source"`
+}
+
+// EffectiveInt64Value is an effective integer override value along with its source.
+type EffectiveInt64Value struct {
+	Value  int64                `json:"value"`
+	Source EffectiveValueSource `json:"source"`
 }
 
 // PodResourceOverrideSpec is the configuration for the ResourceOverride
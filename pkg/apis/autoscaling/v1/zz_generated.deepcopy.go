@@ -26,6 +26,59 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EffectiveBoolValue) DeepCopyInto(out *EffectiveBoolValue) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EffectiveBoolValue.
+func (in *EffectiveBoolValue) DeepCopy() *EffectiveBoolValue {
+	if in == nil {
+		return nil
+	}
+	out := new(EffectiveBoolValue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EffectiveInt64Value) DeepCopyInto(out *EffectiveInt64Value) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EffectiveInt64Value.
+func (in *EffectiveInt64Value) DeepCopy() *EffectiveInt64Value {
+	if in == nil {
+		return nil
+	}
+	out := new(EffectiveInt64Value)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EffectivePodResourceOverride) DeepCopyInto(out *EffectivePodResourceOverride) {
+	*out = *in
+	out.ForceSelinuxRelabel = in.ForceSelinuxRelabel
+	out.LimitCPUToMemoryPercent = in.LimitCPUToMemoryPercent
+	out.CPURequestToLimitPercent = in.CPURequestToLimitPercent
+	out.MemoryRequestToLimitPercent = in.MemoryRequestToLimitPercent
+	out.CPURequestToRequestPercent = in.CPURequestToRequestPercent
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EffectivePodResourceOverride.
+func (in *EffectivePodResourceOverride) DeepCopy() *EffectivePodResourceOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(EffectivePodResourceOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodResourceOverrideSpec) DeepCopyInto(out *PodResourceOverrideSpec) {
 	*out = *in
@@ -152,6 +205,20 @@ func (in *ResourceOverrideStatus) DeepCopyInto(out *ResourceOverrideStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Effective != nil {
+		in, out := &in.Effective, &out.Effective
+		*out = new(EffectivePodResourceOverride)
+		**out = **in
+	}
+	if in.LastMatchedTime != nil {
+		in, out := &in.LastMatchedTime, &out.LastMatchedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.MatchedPodSamples != nil {
+		in, out := &in.MatchedPodSamples, &out.MatchedPodSamples
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -7,8 +7,10 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func (in *PodResourceOverrideSpec) String() string {
@@ -76,6 +78,213 @@ func (in *ClusterResourceOverrideSpec) Hash() string {
 	return hex.EncodeToString(writer.Sum(nil))
 }
 
+// GetManagementState returns the management state, Managed if none is specified.
+func (in *ClusterResourceOverrideSpec) GetManagementState() ManagementState {
+	if in.ManagementState == "" {
+		return Managed
+	}
+
+	return in.ManagementState
+}
+
+// GetCertificateProvider returns the certificate provider, ServiceCA if none
+// is specified.
+func (in *ClusterResourceOverrideSpec) GetCertificateProvider() CertificateProvider {
+	if in.CertificateProvider == "" {
+		return CertificateProviderServiceCA
+	}
+
+	return in.CertificateProvider
+}
+
+// GetWebhookTransport returns the webhook transport, Aggregated if none is
+// specified.
+func (in *ClusterResourceOverrideSpec) GetWebhookTransport() WebhookTransport {
+	if in.WebhookTransport == "" {
+		return WebhookTransportAggregated
+	}
+
+	return in.WebhookTransport
+}
+
+// IsStandalone returns true if the control plane runs in the cluster, so that
+// the admission webhook may be scheduled on control-plane nodes.
+func (in *PlatformStatus) IsStandalone() bool {
+	return in.Type != PlatformHyperShift
+}
+
+// HasOpenShiftConfig returns true if the cluster serves the config.openshift.io
+// APIs, the cluster TLS profile among them.
+func (in *PlatformStatus) HasOpenShiftConfig() bool {
+	switch in.Type {
+	case PlatformOpenShift, PlatformSingleReplica, PlatformHyperShift:
+		return true
+	}
+
+	return false
+}
+
+func (in *ResourceOverridePolicy) GetAction() ResourceOverridePolicyAction {
+	if in.Action == "" {
+		return PolicyActionDeny
+	}
+
+	return in.Action
+}
+
+func (in *ResourceOverridePolicy) String() string {
+	return fmt.Sprintf("Action=%s, LimitCPUToMemoryPercent=%s, CPURequestToLimitPercent=%s, MemoryRequestToLimitPercent=%s, CPURequestToRequestPercent=%s",
+		in.GetAction(), in.LimitCPUToMemoryPercent, in.CPURequestToLimitPercent, in.MemoryRequestToLimitPercent, in.CPURequestToRequestPercent)
+}
+
+func (in *ResourceOverridePolicy) Validate() error {
+	switch in.GetAction() {
+	case PolicyActionDeny, PolicyActionWarn, PolicyActionAudit:
+	default:
+		return fmt.Errorf("invalid value for ResourceOverridePolicy Action %q, must be one of Deny, Warn or Audit", in.Action)
+	}
+
+	if err := in.LimitCPUToMemoryPercent.validate("LimitCPUToMemoryPercent", 0); err != nil {
+		return err
+	}
+
+	if err := in.CPURequestToLimitPercent.validate("CPURequestToLimitPercent", 100); err != nil {
+		return err
+	}
+
+	if err := in.MemoryRequestToLimitPercent.validate("MemoryRequestToLimitPercent", 100); err != nil {
+		return err
+	}
+
+	if err := in.CPURequestToRequestPercent.validate("CPURequestToRequestPercent", 100); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (in *ResourceOverridePolicy) Hash() string {
+	value := in.String()
+
+	writer := sha256.New()
+	writer.Write([]byte(value))
+	return hex.EncodeToString(writer.Sum(nil))
+}
+
+func (in *AutoOptInPolicy) String() string {
+	return fmt.Sprintf("NamespaceSelector=%s", metav1.FormatLabelSelector(&in.NamespaceSelector))
+}
+
+func (in *AutoOptInPolicy) Validate() error {
+	if _, err := metav1.LabelSelectorAsSelector(&in.NamespaceSelector); err != nil {
+		return fmt.Errorf("invalid value for AutoOptIn NamespaceSelector - %s", err.Error())
+	}
+
+	return nil
+}
+
+func (in *AutoOptInPolicy) Hash() string {
+	value := in.String()
+
+	writer := sha256.New()
+	writer.Write([]byte(value))
+	return hex.EncodeToString(writer.Sum(nil))
+}
+
+// DefaultFailOpenGracePeriod is the grace period of a FailOpenPolicy that
+// does not specify one.
+const DefaultFailOpenGracePeriod = 2 * time.Minute
+
+// GetGracePeriod returns the grace period, DefaultFailOpenGracePeriod if none
+// is specified.
+func (in *FailOpenPolicy) GetGracePeriod() time.Duration {
+	if in.GracePeriod == nil {
+		return DefaultFailOpenGracePeriod
+	}
+
+	return in.GracePeriod.Duration
+}
+
+func (in *FailOpenPolicy) Validate() error {
+	if in.GracePeriod != nil && in.GracePeriod.Duration < 0 {
+		return errors.New("invalid value for FailOpen GracePeriod, must not be negative")
+	}
+
+	return nil
+}
+
+func (in CertificateProvider) Validate() error {
+	switch in {
+	case CertificateProviderServiceCA, CertificateProviderSelfManaged, CertificateProviderCertManager:
+		return nil
+	}
+
+	return fmt.Errorf("invalid value for CertificateProvider %q, must be one of %s, %s or %s", in,
+		CertificateProviderServiceCA, CertificateProviderSelfManaged, CertificateProviderCertManager)
+}
+
+func (in WebhookTransport) Validate() error {
+	switch in {
+	case WebhookTransportAggregated, WebhookTransportService:
+		return nil
+	}
+
+	return fmt.Errorf("invalid value for WebhookTransport %q, must be one of %s or %s", in,
+		WebhookTransportAggregated, WebhookTransportService)
+}
+
+func (in *PercentRange) String() string {
+	if in == nil {
+		return "nil"
+	}
+
+	if in.Max == 0 {
+		return fmt.Sprintf("[%d, unbounded]", in.Min)
+	}
+
+	return fmt.Sprintf("[%d, %d]", in.Min, in.Max)
+}
+
+// Contains returns true if the given value is within the range. A zero value
+// means the ratio is not set and is always contained.
+func (in *PercentRange) Contains(value int64) bool {
+	if in == nil || value == 0 {
+		return true
+	}
+
+	if in.Min != 0 && value < in.Min {
+		return false
+	}
+
+	if in.Max != 0 && value > in.Max {
+		return false
+	}
+
+	return true
+}
+
+// validate checks the bounds of the range; limit is the largest value the
+// ratio accepts, zero if it is unbounded.
+func (in *PercentRange) validate(field string, limit int64) error {
+	if in == nil {
+		return nil
+	}
+
+	if in.Min < 0 || in.Max < 0 {
+		return fmt.Errorf("invalid range for %s, bounds must be positive values", field)
+	}
+
+	if limit != 0 && (in.Min > limit || in.Max > limit) {
+		return fmt.Errorf("invalid range for %s, bounds must be [0...%d]", field, limit)
+	}
+
+	if in.Max != 0 && in.Min > in.Max {
+		return fmt.Errorf("invalid range for %s, min must not be greater than max", field)
+	}
+
+	return nil
+}
+
 func mapToString(m map[string]string) string {
 	keys := make([]string, 0, len(m))
 	for k := range m {
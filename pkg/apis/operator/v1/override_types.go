@@ -1,6 +1,7 @@
 package v1
 
 import (
+	configv1 "github.com/openshift/api/config/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -14,6 +15,18 @@ type ClusterResourceOverrideConditionType string
 const (
 	InstallReadinessFailure ClusterResourceOverrideConditionType = "InstallReadinessFailure"
 	Available               ClusterResourceOverrideConditionType = "Available"
+
+	// Uninstalled is True once the admission webhook has been torn down, and
+	// False while the teardown is in progress.
+	Uninstalled ClusterResourceOverrideConditionType = "Uninstalled"
+
+	// Degraded is True while the failurePolicy of the admission webhook is
+	// switched to Ignore by the failOpen policy.
+	Degraded ClusterResourceOverrideConditionType = "Degraded"
+
+	// ServingCertExpiring is True when the serving certificate of the admission
+	// webhook is close to its expiry and has not been rotated.
+	ServingCertExpiring ClusterResourceOverrideConditionType = "ServingCertExpiring"
 )
 
 const (
@@ -24,6 +37,75 @@ const (
 	InternalError                = "InternalError"
 	AdmissionWebhookNotAvailable = "AdmissionWebhookNotAvailable"
 	DeploymentNotReady           = "DeploymentNotReady"
+	WebhookSuspended             = "WebhookSuspended"
+	TeardownInProgress           = "TeardownInProgress"
+	TeardownComplete             = "TeardownComplete"
+	FailOpen                     = "FailOpen"
+	ServingCertNearExpiry        = "ServingCertNearExpiry"
+	ServingCertExpired           = "ServingCertExpired"
+	PlatformDetectionFailed      = "PlatformDetectionFailed"
+
+	// reasons of a failed admission probe.
+	AdmissionWebhookCallFailed         = "AdmissionWebhookCallFailed"
+	AdmissionWebhookTimeout            = "AdmissionWebhookTimeout"
+	AdmissionWebhookCertificateInvalid = "AdmissionWebhookCertificateInvalid"
+	AdmissionAPIServiceNotAvailable    = "AdmissionAPIServiceNotAvailable"
+	AdmissionWebhookNotMutating        = "AdmissionWebhookNotMutating"
+	AdmissionWebhookMutationMismatch   = "AdmissionWebhookMutationMismatch"
+	AdmissionProbeError                = "AdmissionProbeError"
+	AdmissionProbeNamespaceNotReady    = "AdmissionProbeNamespaceNotReady"
+)
+
+// ManagementState defines how the operator manages the admission webhook.
+type ManagementState string
+
+const (
+	// Managed reconciles the admission webhook.
+	Managed ManagementState = "Managed"
+
+	// Unmanaged stops reconciling, the admission webhook is left as is.
+	Unmanaged ManagementState = "Unmanaged"
+
+	// Suspended removes the MutatingWebhookConfiguration only, pods are
+	// admitted without overrides until the admission webhook is Managed again.
+	Suspended ManagementState = "Suspended"
+
+	// Removed tears the admission webhook down, the MutatingWebhookConfiguration
+	// first and the RBAC last.
+	Removed ManagementState = "Removed"
+)
+
+// CertificateProvider defines who issues the serving certificate of the
+// admission webhook, and injects the CA bundle into the APIService and the
+// MutatingWebhookConfiguration.
+type CertificateProvider string
+
+const (
+	// CertificateProviderServiceCA relies on the OpenShift service-ca operator.
+	CertificateProviderServiceCA CertificateProvider = "ServiceCA"
+
+	// CertificateProviderSelfManaged has the operator generate and rotate a CA
+	// and the serving certificate itself.
+	CertificateProviderSelfManaged CertificateProvider = "SelfManaged"
+
+	// CertificateProviderCertManager creates cert-manager Issuer and
+	// Certificate objects, cert-manager issues the serving certificate.
+	CertificateProviderCertManager CertificateProvider = "CertManager"
+)
+
+// WebhookTransport defines how the kube-apiserver reaches the admission
+// webhook.
+type WebhookTransport string
+
+const (
+	// WebhookTransportAggregated has the MutatingWebhookConfiguration call the
+	// admission webhook through the aggregated API, registered with an
+	// APIService.
+	WebhookTransportAggregated WebhookTransport = "Aggregated"
+
+	// WebhookTransportService has the MutatingWebhookConfiguration call the
+	// Service of the admission webhook directly, without an APIService.
+	WebhookTransportService WebhookTransport = "Service"
 )
 
 type ClusterResourceOverrideCondition struct {
@@ -62,6 +144,48 @@ type ClusterResourceOverrideSpec struct {
 	PodResourceOverride PodResourceOverride `json:"podResourceOverride"`
 	// +optional
 	DeploymentOverrides DeploymentOverrides `json:"deploymentOverrides,omitempty"`
+
+	// ResourceOverridePolicy bounds the values namespace administrators may set
+	// in ResourceOverride objects.
+	// +optional
+	ResourceOverridePolicy *ResourceOverridePolicy `json:"resourceOverridePolicy,omitempty"`
+
+	// AutoOptIn allows namespaces to opt in to overrides by creating a
+	// ResourceOverride, without the help of a cluster administrator.
+	// +optional
+	AutoOptIn *AutoOptInPolicy `json:"autoOptIn,omitempty"`
+
+	// ManagementState is one of Managed, Unmanaged, Suspended or Removed.
+	// Defaults to Managed.
+	// +optional
+	ManagementState ManagementState `json:"managementState,omitempty"`
+
+	// FailOpen switches the failurePolicy of the admission webhook to Ignore
+	// while the admission webhook is unavailable, so that an outage does not
+	// block pod creation in opted-in namespaces. Disabled if not specified.
+	// +optional
+	FailOpen *FailOpenPolicy `json:"failOpen,omitempty"`
+
+	// CertificateProvider is one of ServiceCA, SelfManaged or CertManager.
+	// Defaults to ServiceCA.
+	// +optional
+	CertificateProvider CertificateProvider `json:"certificateProvider,omitempty"`
+
+	// WebhookTransport is one of Aggregated or Service. Defaults to Aggregated.
+	// The kube-apiserver calls a webhook Service without credentials, so with
+	// Service the admission path is served without authentication or
+	// authorization: any client that reaches the Service may send it admission
+	// reviews. The admission webhook changes nothing, it only answers with the
+	// overrides of the pod in the review. Use Aggregated if that is not
+	// acceptable, the admission webhook then authorizes the kube-apiserver.
+	// +optional
+	WebhookTransport WebhookTransport `json:"webhookTransport,omitempty"`
+
+	// TLSSecurityProfile is the TLS profile the admission webhook serves
+	// with, one of Old, Intermediate, Modern or Custom as in the cluster
+	// APIServer config. It takes precedence over the cluster TLS profile.
+	// +optional
+	TLSSecurityProfile *configv1.TLSSecurityProfile `json:"tlsSecurityProfile,omitempty"`
 }
 
 type ClusterResourceOverrideStatus struct {
@@ -71,10 +195,162 @@ type ClusterResourceOverrideStatus struct {
 	Conditions []ClusterResourceOverrideCondition  `json:"conditions,omitempty" hash:"set"`
 	Version    string                              `json:"version,omitempty"`
 	Image      string                              `json:"image,omitempty"`
+
+	// ManagementState is the management state the admission webhook was last
+	// reconciled in.
+	ManagementState ManagementState `json:"managementState,omitempty"`
+
+	// FailOpen reports the state of the failOpen policy.
+	FailOpen *FailOpenStatus `json:"failOpen,omitempty"`
+
+	// AdmissionProbe reports the last synthetic admission request sent
+	// through the admission webhook.
+	AdmissionProbe *AdmissionProbeStatus `json:"admissionProbe,omitempty"`
+
+	// ServingCert reports the validity of the serving certificate of the
+	// admission webhook.
+	ServingCert *ServingCertStatus `json:"servingCert,omitempty"`
+
+	// Platform reports the platform the operator detected the cluster as.
+	Platform *PlatformStatus `json:"platform,omitempty"`
+
+	// TLSProfile reports the TLS profile the admission webhook serves with,
+	// and where it comes from.
+	TLSProfile *TLSProfileStatus `json:"tlsProfile,omitempty"`
+}
+
+// TLSProfileSource is where the TLS profile of the admission webhook comes
+// from.
+type TLSProfileSource string
+
+const (
+	// TLSProfileSourceClusterResourceOverride is the tlsSecurityProfile of
+	// the ClusterResourceOverride.
+	TLSProfileSourceClusterResourceOverride TLSProfileSource = "ClusterResourceOverride"
+
+	// TLSProfileSourceAPIServer is the TLS profile of the cluster APIServer
+	// config.
+	TLSProfileSourceAPIServer TLSProfileSource = "APIServer"
+
+	// TLSProfileSourceDefault is the default of the admission webhook, there
+	// is no TLS profile to honor.
+	TLSProfileSourceDefault TLSProfileSource = "Default"
+)
+
+// TLSProfileStatus reports the TLS profile the admission webhook was last
+// rolled out with.
+type TLSProfileStatus struct {
+	// Source is one of ClusterResourceOverride, APIServer or Default.
+	Source TLSProfileSource `json:"source"`
+
+	// MinTLSVersion and Ciphers are the arguments the admission webhook is
+	// passed, empty for its defaults.
+	// +optional
+	MinTLSVersion string `json:"minTLSVersion,omitempty"`
+	// +optional
+	Ciphers string `json:"ciphers,omitempty"`
+}
+
+// PlatformType is the kind of cluster the operator runs on.
+type PlatformType string
+
+const (
+	// PlatformOpenShift is an OpenShift cluster running its own control plane.
+	PlatformOpenShift PlatformType = "OpenShift"
+
+	// PlatformSingleReplica is a single node OpenShift cluster.
+	PlatformSingleReplica PlatformType = "SingleReplica"
+
+	// PlatformHyperShift is an OpenShift cluster whose control plane is hosted
+	// outside of it, on a HyperShift management cluster.
+	PlatformHyperShift PlatformType = "HyperShift"
+
+	// PlatformMicroShift is a MicroShift cluster, it serves some OpenShift APIs
+	// but not the config.openshift.io ones.
+	PlatformMicroShift PlatformType = "MicroShift"
+
+	// PlatformKubernetes is a cluster that serves no OpenShift API.
+	PlatformKubernetes PlatformType = "Kubernetes"
+)
+
+// PlatformStatus reports the platform the operator detected the cluster as.
+type PlatformStatus struct {
+	// Type is one of OpenShift, SingleReplica, HyperShift, MicroShift or
+	// Kubernetes.
+	Type PlatformType `json:"type"`
+
+	// ControlPlaneTopology and InfrastructureTopology are the topologies the
+	// Infrastructure object reports, empty without config.openshift.io.
+	// +optional
+	ControlPlaneTopology string `json:"controlPlaneTopology,omitempty"`
+	// +optional
+	InfrastructureTopology string `json:"infrastructureTopology,omitempty"`
+}
+
+// ServingCertStatus reports the validity of the serving certificate issued
+// into the serving Secret of the admission webhook.
+type ServingCertStatus struct {
+	// NotBefore is when the serving certificate becomes valid.
+	NotBefore metav1.Time `json:"notBefore"`
+
+	// NotAfter is when the serving certificate expires.
+	NotAfter metav1.Time `json:"notAfter"`
+}
+
+// AdmissionProbeResult is the outcome of an admission probe.
+type AdmissionProbeResult string
+
+const (
+	// AdmissionProbeSucceeded is a probe pod mutated according to the
+	// current configuration.
+	AdmissionProbeSucceeded AdmissionProbeResult = "Succeeded"
+
+	// AdmissionProbeFailed is a probe pod that the admission webhook failed to
+	// admit, or did not mutate as expected.
+	AdmissionProbeFailed AdmissionProbeResult = "Failed"
+
+	// AdmissionProbeInconclusive is a probe pod rejected before it reached the
+	// admission webhook, the admission path could not be checked.
+	AdmissionProbeInconclusive AdmissionProbeResult = "Inconclusive"
+)
+
+// AdmissionProbeStatus is the outcome of the last admission probe, a
+// server-side dry-run pod create in the admission probe namespace.
+type AdmissionProbeStatus struct {
+	// LastProbeTime is when the last probe was sent.
+	LastProbeTime metav1.Time `json:"lastProbeTime"`
+
+	// Result is one of Succeeded, Failed or Inconclusive.
+	Result AdmissionProbeResult `json:"result"`
+
+	// Reason is a CamelCase reason for a probe that did not succeed.
+	Reason string `json:"reason,omitempty"`
+
+	// Message explains a probe that did not succeed.
+	Message string `json:"message,omitempty"`
+
+	// Latency is how long the dry-run pod create took.
+	Latency metav1.Duration `json:"latency"`
+}
+
+// FailOpenStatus reports when the admission webhook became unavailable, and
+// when its failurePolicy was switched to Ignore.
+type FailOpenStatus struct {
+	// UnavailableSince is when the admission webhook was first seen unavailable.
+	// Unset while it is available.
+	UnavailableSince *metav1.Time `json:"unavailableSince,omitempty"`
+
+	// Since is when the failurePolicy was switched to Ignore. Unset while the
+	// failurePolicy is Fail.
+	Since *metav1.Time `json:"since,omitempty"`
 }
 
 type ClusterResourceOverrideResourceHash struct {
 	Configuration string `json:"configuration,omitempty"`
+
+	// ServingCert is the hash of the serving Secret the admission webhook pods
+	// were rolled out with.
+	ServingCert string `json:"servingCert,omitempty"`
 }
 
 type ClusterResourceOverrideResources struct {
@@ -107,6 +383,14 @@ type ClusterResourceOverrideResources struct {
 	// ValidatingAdmissionPolicyBindingRef points to the ValidatingAdmissionPolicyBinding
 	// that activates the exempt namespace policy.
 	ValidatingAdmissionPolicyBindingRef *corev1.ObjectReference `json:"validatingAdmissionPolicyBindingRef,omitempty"`
+
+	// ResourceOverridePolicyRef points to the ValidatingAdmissionPolicy that
+	// enforces the resourceOverridePolicy on ResourceOverride objects.
+	ResourceOverridePolicyRef *corev1.ObjectReference `json:"resourceOverridePolicyRef,omitempty"`
+
+	// ResourceOverridePolicyBindingRef points to the ValidatingAdmissionPolicyBinding
+	// that activates the resourceOverridePolicy.
+	ResourceOverridePolicyBindingRef *corev1.ObjectReference `json:"resourceOverridePolicyBindingRef,omitempty"`
 }
 
 // PodResourceOverride is the configuration for the admission controller which
@@ -133,6 +417,78 @@ type DeploymentOverrides struct {
 	Replicas *int32 `json:"replicas,omitempty"`
 }
 
+// AutoOptInPolicy defines the namespaces that are opted in automatically.
+type AutoOptInPolicy struct {
+	// NamespaceSelector selects the namespaces allowed to self-enable. A
+	// namespace it selects is labeled when a valid ResourceOverride is created
+	// in it, and the label is removed again when its last ResourceOverride is
+	// deleted. An empty selector selects all namespaces.
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+}
+
+// FailOpenPolicy defines when the failurePolicy of the admission webhook is
+// switched from Fail to Ignore. Fail is restored once the admission webhook is
+// available again.
+type FailOpenPolicy struct {
+	// GracePeriod is how long the admission webhook may be unavailable before
+	// its failurePolicy is switched to Ignore. Defaults to 2m.
+	// +optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+
+	// DuringRollout switches the failurePolicy to Ignore as soon as the
+	// admission webhook has no available replica, without waiting for the grace
+	// period. A rollout that keeps a replica available does not switch it.
+	// +optional
+	DuringRollout bool `json:"duringRollout,omitempty"`
+}
+
+// ResourceOverridePolicyAction is the action taken when a ResourceOverride
+// violates the resourceOverridePolicy.
+type ResourceOverridePolicyAction string
+
+const (
+	// PolicyActionDeny rejects the ResourceOverride.
+	PolicyActionDeny ResourceOverridePolicyAction = "Deny"
+
+	// PolicyActionWarn admits the ResourceOverride and returns a warning to the client.
+	PolicyActionWarn ResourceOverridePolicyAction = "Warn"
+
+	// PolicyActionAudit admits the ResourceOverride and records the violation in the audit log.
+	PolicyActionAudit ResourceOverridePolicyAction = "Audit"
+)
+
+// ResourceOverridePolicy defines the allowed range of each ratio a ResourceOverride
+// may set. A ratio left unset (zero) in a ResourceOverride falls back to the
+// ClusterResourceOverride and is always allowed.
+type ResourceOverridePolicy struct {
+	// Action is taken when a ResourceOverride sets a ratio outside its allowed range.
+	// Defaults to Deny.
+	// +optional
+	Action ResourceOverridePolicyAction `json:"action,omitempty"`
+
+	// +optional
+	LimitCPUToMemoryPercent *PercentRange `json:"limitCPUToMemoryPercent,omitempty"`
+
+	// +optional
+	CPURequestToLimitPercent *PercentRange `json:"cpuRequestToLimitPercent,omitempty"`
+
+	// +optional
+	MemoryRequestToLimitPercent *PercentRange `json:"memoryRequestToLimitPercent,omitempty"`
+
+	// +optional
+	CPURequestToRequestPercent *PercentRange `json:"cpuRequestToRequestPercent,omitempty"`
+}
+
+// PercentRange is an inclusive range of percentage values. A zero bound is
+// not enforced.
+type PercentRange struct {
+	// +optional
+	Min int64 `json:"min,omitempty"`
+
+	// +optional
+	Max int64 `json:"max,omitempty"`
+}
+
 // PodResourceOverrideSpec is the configuration for the ClusterResourceOverride
 // admission controller which overrides user-provided container request/limit values.
 type PodResourceOverrideSpec struct {
@@ -22,10 +22,47 @@ limitations under the License.
 package v1
 
 import (
+	configv1 "github.com/openshift/api/config/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdmissionProbeStatus) DeepCopyInto(out *AdmissionProbeStatus) {
+	*out = *in
+	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
+	out.Latency = in.Latency
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdmissionProbeStatus.
+func (in *AdmissionProbeStatus) DeepCopy() *AdmissionProbeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AdmissionProbeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoOptInPolicy) DeepCopyInto(out *AutoOptInPolicy) {
+	*out = *in
+	in.NamespaceSelector.DeepCopyInto(&out.NamespaceSelector)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoOptInPolicy.
+func (in *AutoOptInPolicy) DeepCopy() *AutoOptInPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoOptInPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterResourceOverride) DeepCopyInto(out *ClusterResourceOverride) {
 	*out = *in
@@ -158,6 +195,16 @@ func (in *ClusterResourceOverrideResources) DeepCopyInto(out *ClusterResourceOve
 		*out = new(corev1.ObjectReference)
 		**out = **in
 	}
+	if in.ResourceOverridePolicyRef != nil {
+		in, out := &in.ResourceOverridePolicyRef, &out.ResourceOverridePolicyRef
+		*out = new(corev1.ObjectReference)
+		**out = **in
+	}
+	if in.ResourceOverridePolicyBindingRef != nil {
+		in, out := &in.ResourceOverridePolicyBindingRef, &out.ResourceOverridePolicyBindingRef
+		*out = new(corev1.ObjectReference)
+		**out = **in
+	}
 	return
 }
 
@@ -176,6 +223,26 @@ func (in *ClusterResourceOverrideSpec) DeepCopyInto(out *ClusterResourceOverride
 	*out = *in
 	out.PodResourceOverride = in.PodResourceOverride
 	in.DeploymentOverrides.DeepCopyInto(&out.DeploymentOverrides)
+	if in.ResourceOverridePolicy != nil {
+		in, out := &in.ResourceOverridePolicy, &out.ResourceOverridePolicy
+		*out = new(ResourceOverridePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AutoOptIn != nil {
+		in, out := &in.AutoOptIn, &out.AutoOptIn
+		*out = new(AutoOptInPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailOpen != nil {
+		in, out := &in.FailOpen, &out.FailOpen
+		*out = new(FailOpenPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLSSecurityProfile != nil {
+		in, out := &in.TLSSecurityProfile, &out.TLSSecurityProfile
+		*out = new(configv1.TLSSecurityProfile)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -201,6 +268,31 @@ func (in *ClusterResourceOverrideStatus) DeepCopyInto(out *ClusterResourceOverri
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.FailOpen != nil {
+		in, out := &in.FailOpen, &out.FailOpen
+		*out = new(FailOpenStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdmissionProbe != nil {
+		in, out := &in.AdmissionProbe, &out.AdmissionProbe
+		*out = new(AdmissionProbeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServingCert != nil {
+		in, out := &in.ServingCert, &out.ServingCert
+		*out = new(ServingCertStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Platform != nil {
+		in, out := &in.Platform, &out.Platform
+		*out = new(PlatformStatus)
+		**out = **in
+	}
+	if in.TLSProfile != nil {
+		in, out := &in.TLSProfile, &out.TLSProfile
+		*out = new(TLSProfileStatus)
+		**out = **in
+	}
 	return
 }
 
@@ -249,6 +341,83 @@ func (in *DeploymentOverrides) DeepCopy() *DeploymentOverrides {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailOpenPolicy) DeepCopyInto(out *FailOpenPolicy) {
+	*out = *in
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailOpenPolicy.
+func (in *FailOpenPolicy) DeepCopy() *FailOpenPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(FailOpenPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailOpenStatus) DeepCopyInto(out *FailOpenStatus) {
+	*out = *in
+	if in.UnavailableSince != nil {
+		in, out := &in.UnavailableSince, &out.UnavailableSince
+		*out = (*in).DeepCopy()
+	}
+	if in.Since != nil {
+		in, out := &in.Since, &out.Since
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailOpenStatus.
+func (in *FailOpenStatus) DeepCopy() *FailOpenStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FailOpenStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PercentRange) DeepCopyInto(out *PercentRange) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PercentRange.
+func (in *PercentRange) DeepCopy() *PercentRange {
+	if in == nil {
+		return nil
+	}
+	out := new(PercentRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlatformStatus) DeepCopyInto(out *PlatformStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlatformStatus.
+func (in *PlatformStatus) DeepCopy() *PlatformStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PlatformStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodResourceOverride) DeepCopyInto(out *PodResourceOverride) {
 	*out = *in
@@ -282,3 +451,73 @@ func (in *PodResourceOverrideSpec) DeepCopy() *PodResourceOverrideSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceOverridePolicy) DeepCopyInto(out *ResourceOverridePolicy) {
+	*out = *in
+	if in.LimitCPUToMemoryPercent != nil {
+		in, out := &in.LimitCPUToMemoryPercent, &out.LimitCPUToMemoryPercent
+		*out = new(PercentRange)
+		**out = **in
+	}
+	if in.CPURequestToLimitPercent != nil {
+		in, out := &in.CPURequestToLimitPercent, &out.CPURequestToLimitPercent
+		*out = new(PercentRange)
+		**out = **in
+	}
+	if in.MemoryRequestToLimitPercent != nil {
+		in, out := &in.MemoryRequestToLimitPercent, &out.MemoryRequestToLimitPercent
+		*out = new(PercentRange)
+		**out = **in
+	}
+	if in.CPURequestToRequestPercent != nil {
+		in, out := &in.CPURequestToRequestPercent, &out.CPURequestToRequestPercent
+		*out = new(PercentRange)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceOverridePolicy.
+func (in *ResourceOverridePolicy) DeepCopy() *ResourceOverridePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceOverridePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServingCertStatus) DeepCopyInto(out *ServingCertStatus) {
+	*out = *in
+	in.NotBefore.DeepCopyInto(&out.NotBefore)
+	in.NotAfter.DeepCopyInto(&out.NotAfter)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServingCertStatus.
+func (in *ServingCertStatus) DeepCopy() *ServingCertStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ServingCertStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSProfileStatus) DeepCopyInto(out *TLSProfileStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSProfileStatus.
+func (in *TLSProfileStatus) DeepCopy() *TLSProfileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSProfileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
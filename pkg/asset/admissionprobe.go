@@ -0,0 +1,117 @@
+package asset
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AdmissionProbe returns the objects of the admission probe: the RoleBinding
+// that lets the operator create pods in a dedicated opted-in namespace only,
+// and the pod created in it with a server-side dry-run to check the admission
+// path end to end. The namespace is shipped with the operator manifests.
+func (a *Asset) AdmissionProbe() *admissionProbe {
+	return &admissionProbe{
+		values: a.values,
+	}
+}
+
+type admissionProbe struct {
+	values *Values
+}
+
+// Namespace is named after the admission webhook rather than the operator
+// namespace, an openshift-* or kube-* namespace is exempt from it and the
+// probe pod would never be mutated. It must match the namespace shipped in
+// artifacts/deploy.
+func (p *admissionProbe) Namespace() string {
+	return fmt.Sprintf("%s-admission-probe", p.values.Name)
+}
+
+// ClusterRoleName is the ClusterRole installed with the operator that allows
+// the probe pod to be created. It is bound in the probe namespace only.
+func (p *admissionProbe) ClusterRoleName() string {
+	return fmt.Sprintf("%s-admission-probe", p.values.Name)
+}
+
+// NewRoleBinding returns the RoleBinding of the ClusterRole of the probe to
+// the given user, the operator itself.
+func (p *admissionProbe) NewRoleBinding(user string) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "RoleBinding",
+			APIVersion: "rbac.authorization.k8s.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      p.ClusterRoleName(),
+			Namespace: p.Namespace(),
+			Labels: map[string]string{
+				p.values.OwnerLabelKey: p.values.OwnerLabelValue,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     p.ClusterRoleName(),
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				APIGroup: "rbac.authorization.k8s.io",
+				Kind:     "User",
+				Name:     user,
+			},
+		},
+	}
+}
+
+// NewPod returns the probe pod. It sets limits only, so that every override
+// of the configuration applies to it. It is never persisted.
+func (p *admissionProbe) NewPod() *corev1.Pod {
+	allowPrivilegeEscalation := false
+	runAsNonRoot := true
+	automount := false
+
+	return &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Pod",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-admission-probe", p.values.Name),
+			Namespace: p.Namespace(),
+			Labels: map[string]string{
+				p.values.OwnerLabelKey: p.values.OwnerLabelValue,
+			},
+		},
+		Spec: corev1.PodSpec{
+			AutomountServiceAccountToken: &automount,
+			SecurityContext: &corev1.PodSecurityContext{
+				RunAsNonRoot: &runAsNonRoot,
+				SeccompProfile: &corev1.SeccompProfile{
+					Type: corev1.SeccompProfileTypeRuntimeDefault,
+				},
+			},
+			Containers: []corev1.Container{
+				{
+					Name:  "probe",
+					Image: p.values.OperandImage,
+					Resources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("1"),
+							corev1.ResourceMemory: resource.MustParse("1Gi"),
+						},
+					},
+					SecurityContext: &corev1.SecurityContext{
+						AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+						Capabilities: &corev1.Capabilities{
+							Drop: []corev1.Capability{"ALL"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
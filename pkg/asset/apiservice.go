@@ -33,7 +33,7 @@ func (a *apiservice) New() *apiregistrationv1.APIService {
 				a.values.OwnerLabelKey: a.values.OwnerLabelValue,
 			},
 			Annotations: map[string]string{
-				"service.alpha.openshift.io/inject-cabundle": "true",
+				AlphaInjectCABundleAnnotationName: "true",
 			},
 		},
 		Spec: apiregistrationv1.APIServiceSpec{
@@ -9,6 +9,28 @@ import (
 
 const (
 	NamespaceOptInLabelKey = "clusterresourceoverrides.admission.autoscaling.openshift.io/enabled"
+
+	// NamespaceAutoOptInAnnotationKey marks a namespace that was opted in by the
+	// operator, rather than by an administrator, so that it may opt it out again.
+	NamespaceAutoOptInAnnotationKey = "clusterresourceoverrides.admission.autoscaling.openshift.io/auto-opt-in"
+
+	// NamespaceLegacyOptInAnnotationKey marks a namespace whose opt-in label was
+	// set by the operator from LegacyOverrideEnabledAnnotationKey, so that the
+	// label can be removed when the admission webhook is torn down.
+	NamespaceLegacyOptInAnnotationKey = "clusterresourceoverrides.admission.autoscaling.openshift.io/legacy-opt-in"
+
+	// NamespacePolicyConfigMapName is the name of the ConfigMap the operator
+	// publishes the effective cluster override policy in, in every opted-in
+	// namespace. It is labeled with NamespacePolicyLabelKey=true. A ConfigMap
+	// of that name without the label is not the operator's, the namespace is
+	// then left unpublished.
+	NamespacePolicyConfigMapName = "clusterresourceoverride-policy"
+	NamespacePolicyLabelKey      = "clusterresourceoverrides.admission.autoscaling.openshift.io/policy"
+
+	// LegacyOverrideEnabledAnnotationKey is the project annotation of the
+	// OpenShift 3 ClusterResourceOverride admission plugin. It is translated
+	// into the opt-in label.
+	LegacyOverrideEnabledAnnotationKey = "quota.openshift.io/cluster-resource-override-enabled"
 )
 
 func New(context runtime.OperandContext) *Asset {
@@ -31,6 +53,13 @@ func New(context runtime.OperandContext) *Asset {
 		ServingCertHashAnnotationKey:   fmt.Sprintf("%s.%s/servingcert.hash", context.WebhookName(), operatorv1.GroupName),
 		OwnerAnnotationKey:             fmt.Sprintf("%s.%s/owner", context.WebhookName(), operatorv1.GroupName),
 		TLSProfileHashAnnotationKey:    fmt.Sprintf("%s.%s/tls-profile.hash", context.WebhookName(), operatorv1.GroupName),
+		PolicyHashAnnotationKey:        fmt.Sprintf("%s.%s/resourceoverride-policy.hash", context.WebhookName(), operatorv1.GroupName),
+		FailOpenAnnotationKey:          fmt.Sprintf("%s.%s/fail-open", context.WebhookName(), operatorv1.GroupName),
+		WebhookTransportAnnotationKey:  fmt.Sprintf("%s.%s/webhook-transport", context.WebhookName(), operatorv1.GroupName),
+		PlatformAnnotationKey:          fmt.Sprintf("%s.%s/platform", context.WebhookName(), operatorv1.GroupName),
+		TopologyAnnotationKey:          fmt.Sprintf("%s.%s/topology", context.WebhookName(), operatorv1.GroupName),
+		Finalizer:                      fmt.Sprintf("%s.%s/teardown", context.WebhookName(), operatorv1.GroupName),
+		HostedKubeconfigSecret:         context.HostedKubeconfigSecret(),
 	}
 
 	return &Asset{
@@ -66,4 +95,31 @@ type Values struct {
 	ServingCertHashAnnotationKey   string
 	OwnerAnnotationKey             string
 	TLSProfileHashAnnotationKey    string
+	PolicyHashAnnotationKey        string
+
+	// FailOpenAnnotationKey marks a MutatingWebhookConfiguration whose
+	// failurePolicy was switched to Ignore by the operator, with the time it
+	// was switched, so that Fail is restored only where the operator changed it.
+	FailOpenAnnotationKey string
+
+	// WebhookTransportAnnotationKey records the webhook transport the
+	// Deployment was rolled out for, a change of transport rolls it out again.
+	WebhookTransportAnnotationKey string
+
+	// PlatformAnnotationKey records the platform the Deployment was rolled
+	// out for, the admission webhook is scheduled according to it.
+	PlatformAnnotationKey string
+
+	// TopologyAnnotationKey records the replica count and anti-affinity the
+	// Deployment was rolled out with, they follow the schedulable nodes.
+	TopologyAnnotationKey string
+
+	// Finalizer holds the deletion of the ClusterResourceOverride until the
+	// admission webhook has been torn down.
+	Finalizer string
+
+	// HostedKubeconfigSecret is the Secret with the kubeconfig of the hosted
+	// cluster, set if the admission webhook runs in the control plane
+	// namespace of a management cluster.
+	HostedKubeconfigSecret string
 }
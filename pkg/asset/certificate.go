@@ -0,0 +1,190 @@
+package asset
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+)
+
+const (
+	// InjectCABundleAnnotationName asks the service-ca operator to inject its
+	// CA bundle into the MutatingWebhookConfiguration.
+	InjectCABundleAnnotationName = "service.beta.openshift.io/inject-cabundle"
+
+	// AlphaInjectCABundleAnnotationName asks the service-ca operator to inject
+	// its CA bundle into the APIService.
+	AlphaInjectCABundleAnnotationName = "service.alpha.openshift.io/inject-cabundle"
+
+	// KubeRootCAConfigMapName is the ConfigMap published in every namespace
+	// with the CA bundle verifying the kube-apiserver, which the
+	// MutatingWebhookConfiguration calls.
+	KubeRootCAConfigMapName = "kube-root-ca.crt"
+
+	// CABundleKey is the key of the CA bundle in the serving Secret and in the
+	// kube-root-ca.crt ConfigMap.
+	CABundleKey = "ca.crt"
+
+	// CertManagerAPIVersion is the version of the cert-manager objects.
+	CertManagerAPIVersion = "cert-manager.io/v1"
+)
+
+// serviceCAAnnotations are the annotations the service-ca operator acts on.
+var serviceCAAnnotations = []string{
+	ServingCertSecretAnnotationName,
+	InjectCABundleAnnotationName,
+	AlphaInjectCABundleAnnotationName,
+}
+
+// ApplyCertificateProvider removes the service-ca annotations from the given
+// Service, APIService or MutatingWebhookConfiguration, unless the certificate
+// provider is ServiceCA. With any other provider the operator injects the CA
+// bundle itself.
+func ApplyCertificateProvider(object metav1.Object, provider operatorv1.CertificateProvider) {
+	if provider == operatorv1.CertificateProviderServiceCA {
+		return
+	}
+
+	annotations := object.GetAnnotations()
+	for _, key := range serviceCAAnnotations {
+		delete(annotations, key)
+	}
+	object.SetAnnotations(annotations)
+}
+
+// ServingCertDNSNames returns the DNS names the serving certificate is issued
+// for, the names of the Service the APIService refers to.
+func (a *Asset) ServingCertDNSNames() []string {
+	return []string{
+		fmt.Sprintf("%s.%s.svc", a.values.Name, a.values.Namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", a.values.Name, a.values.Namespace),
+	}
+}
+
+// CertificateAuthoritySecret is the Secret holding the CA that signs the
+// serving certificate with the SelfManaged and CertManager providers.
+func (a *Asset) CertificateAuthoritySecret() *certificateAuthoritySecret {
+	return &certificateAuthoritySecret{
+		values: a.values,
+	}
+}
+
+type certificateAuthoritySecret struct {
+	values *Values
+}
+
+func (c *certificateAuthoritySecret) Name() string {
+	return fmt.Sprintf("%s-ca", c.values.Name)
+}
+
+func (c *certificateAuthoritySecret) New() *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: c.values.Namespace,
+			Name:      c.Name(),
+			Labels: map[string]string{
+				c.values.OwnerLabelKey: c.values.OwnerLabelValue,
+			},
+		},
+		Type: corev1.SecretTypeTLS,
+	}
+}
+
+// CertManager returns the cert-manager objects issuing the serving certificate
+// with the CertManager provider: a self-signed Issuer bootstraps a CA
+// Certificate, and a CA Issuer signs the serving Certificate with it.
+func (a *Asset) CertManager() *certManager {
+	return &certManager{
+		asset: a,
+	}
+}
+
+type certManager struct {
+	asset *Asset
+}
+
+func (c *certManager) SelfSignedIssuerName() string {
+	return fmt.Sprintf("%s-selfsigned", c.asset.values.Name)
+}
+
+func (c *certManager) CAIssuerName() string {
+	return fmt.Sprintf("%s-ca", c.asset.values.Name)
+}
+
+func (c *certManager) CACertificateName() string {
+	return fmt.Sprintf("%s-ca", c.asset.values.Name)
+}
+
+func (c *certManager) ServingCertificateName() string {
+	return fmt.Sprintf("%s-serving", c.asset.values.Name)
+}
+
+// New returns the cert-manager objects, in the order they are created.
+func (c *certManager) New() []*unstructured.Unstructured {
+	caSecret := c.asset.CertificateAuthoritySecret().Name()
+
+	dnsNames := make([]interface{}, 0)
+	for _, name := range c.asset.ServingCertDNSNames() {
+		dnsNames = append(dnsNames, name)
+	}
+
+	return []*unstructured.Unstructured{
+		c.newObject("Issuer", c.SelfSignedIssuerName(), map[string]interface{}{
+			"selfSigned": map[string]interface{}{},
+		}),
+		c.newObject("Certificate", c.CACertificateName(), map[string]interface{}{
+			"isCA":       true,
+			"commonName": caSecret,
+			"secretName": caSecret,
+			"duration":   "17520h",
+			"privateKey": map[string]interface{}{
+				"algorithm": "ECDSA",
+				"size":      int64(256),
+			},
+			"issuerRef": c.issuerRef(c.SelfSignedIssuerName()),
+		}),
+		c.newObject("Issuer", c.CAIssuerName(), map[string]interface{}{
+			"ca": map[string]interface{}{
+				"secretName": caSecret,
+			},
+		}),
+		c.newObject("Certificate", c.ServingCertificateName(), map[string]interface{}{
+			"secretName": c.asset.ServiceServingSecret().Name(),
+			"dnsNames":   dnsNames,
+			"duration":   "8760h",
+			"issuerRef":  c.issuerRef(c.CAIssuerName()),
+		}),
+	}
+}
+
+func (c *certManager) issuerRef(name string) map[string]interface{} {
+	return map[string]interface{}{
+		"group": "cert-manager.io",
+		"kind":  "Issuer",
+		"name":  name,
+	}
+}
+
+func (c *certManager) newObject(kind, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	object := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": spec,
+		},
+	}
+	object.SetAPIVersion(CertManagerAPIVersion)
+	object.SetKind(kind)
+	object.SetNamespace(c.asset.values.Namespace)
+	object.SetName(name)
+	object.SetLabels(map[string]string{
+		c.asset.values.OwnerLabelKey: c.asset.values.OwnerLabelValue,
+	})
+
+	return object
+}
@@ -48,7 +48,7 @@ func (d *deployment) New() *appsv1.Deployment {
 	maxSurge := intstr.FromInt32(0)
 	values := d.asset.Values()
 
-	return &appsv1.Deployment{
+	object := &appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Deployment",
 			APIVersion: "apps/v1",
@@ -167,28 +167,52 @@ func (d *deployment) New() *appsv1.Deployment {
 						},
 					},
 					Tolerations: DefaultTolerations,
-					Affinity: &corev1.Affinity{
-						PodAntiAffinity: &corev1.PodAntiAffinity{
-							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
-								{
-									LabelSelector: &metav1.LabelSelector{
-										MatchExpressions: []metav1.LabelSelectorRequirement{
-											{
-												Key:      values.SelectorLabelKey,
-												Operator: metav1.LabelSelectorOpIn,
-												Values: []string{
-													values.SelectorLabelValue,
-												},
-											},
-										},
-									},
-									TopologyKey: "kubernetes.io/hostname",
-								},
-							},
-						},
+					Affinity:    d.Affinity(true),
+				},
+			},
+		},
+	}
+
+	d.asset.applyHosting(&object.Spec.Template.Spec)
+	return object
+}
+
+// Affinity returns the pod anti-affinity that spreads the replicas across
+// nodes. If required is false, replicas may share a node, e.g. on a single
+// node cluster.
+func (d *deployment) Affinity(required bool) *corev1.Affinity {
+	values := d.asset.Values()
+	term := corev1.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{
+					Key:      values.SelectorLabelKey,
+					Operator: metav1.LabelSelectorOpIn,
+					Values: []string{
+						values.SelectorLabelValue,
 					},
 				},
 			},
 		},
+		TopologyKey: "kubernetes.io/hostname",
+	}
+
+	if required {
+		return &corev1.Affinity{
+			PodAntiAffinity: &corev1.PodAntiAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{term},
+			},
+		}
+	}
+
+	return &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				{
+					Weight:          100,
+					PodAffinityTerm: term,
+				},
+			},
+		},
 	}
 }
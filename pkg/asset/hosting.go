@@ -0,0 +1,108 @@
+package asset
+
+import (
+	"fmt"
+	"path"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+)
+
+const (
+	// HostedKubeconfigKey is the key of the kubeconfig in the Secret named by
+	// Values.HostedKubeconfigSecret.
+	HostedKubeconfigKey = "kubeconfig"
+
+	// HostedKubeconfigMountPath is where the kubeconfig of the hosted cluster
+	// is mounted in the admission webhook.
+	HostedKubeconfigMountPath = "/etc/kubernetes/hosted"
+
+	// ServiceCAConfigMapName is the ConfigMap the service-ca operator injects
+	// its CA bundle into in every namespace, under ServiceCABundleKey.
+	ServiceCAConfigMapName = "openshift-service-ca.crt"
+	ServiceCABundleKey     = "service-ca.crt"
+
+	hostedKubeconfigVolumeName = "hosted-kubeconfig"
+)
+
+// OnManagementCluster returns true if the admission webhook runs in the
+// control plane namespace of a management cluster, rather than in the
+// hosted cluster it serves.
+func (a *Asset) OnManagementCluster() bool {
+	return a.values.HostedKubeconfigSecret != ""
+}
+
+// WebhookTransport returns the webhook transport the admission webhook is
+// called with given the one of the ClusterResourceOverride. The kube-apiserver
+// of a hosted cluster has no aggregated API to reach a management cluster
+// with, it calls the Service of the admission webhook by URL.
+func (a *Asset) WebhookTransport(transport operatorv1.WebhookTransport) operatorv1.WebhookTransport {
+	if a.OnManagementCluster() {
+		return operatorv1.WebhookTransportService
+	}
+
+	return transport
+}
+
+// WebhookURL returns the URL the kube-apiserver of a hosted cluster calls
+// the admission webhook with. The kube-apiserver runs in the same control
+// plane namespace and resolves the Service of the admission webhook.
+func (a *Asset) WebhookURL() string {
+	return fmt.Sprintf("https://%s.%s.svc:443%s", a.values.Name, a.values.Namespace, a.AdmissionPath())
+}
+
+// HostedKubeconfigArgs returns the operand argument(s) that have the
+// admission webhook reach the hosted cluster, and delegate authentication
+// and authorization to it, with the mounted kubeconfig. Nil if the admission
+// webhook runs in the cluster it serves.
+func (a *Asset) HostedKubeconfigArgs() []string {
+	if !a.OnManagementCluster() {
+		return nil
+	}
+
+	kubeconfig := path.Join(HostedKubeconfigMountPath, HostedKubeconfigKey)
+	return []string{
+		"--kubeconfig=" + kubeconfig,
+		"--authentication-kubeconfig=" + kubeconfig,
+		"--authorization-kubeconfig=" + kubeconfig,
+	}
+}
+
+// applyHosting mounts the kubeconfig of the hosted cluster into the admission
+// webhook, if it runs in the control plane namespace of a management cluster.
+func (a *Asset) applyHosting(spec *corev1.PodSpec) {
+	if !a.OnManagementCluster() {
+		return
+	}
+
+	spec.Volumes = append(spec.Volumes, corev1.Volume{
+		Name: hostedKubeconfigVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: a.values.HostedKubeconfigSecret,
+			},
+		},
+	})
+
+	for i := range spec.Containers {
+		container := &spec.Containers[i]
+		container.Args = append(container.Args, a.HostedKubeconfigArgs()...)
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      hostedKubeconfigVolumeName,
+			MountPath: HostedKubeconfigMountPath,
+			ReadOnly:  true,
+		})
+	}
+}
+
+// applyHostedClientConfig points the webhooks of the given configuration at
+// the URL of the admission webhook in the management cluster.
+func (m *mutatingWebhookConfiguration) applyHostedClientConfig(object *admissionregistrationv1.MutatingWebhookConfiguration) {
+	url := m.asset.WebhookURL()
+	for i := range object.Webhooks {
+		object.Webhooks[i].ClientConfig.Service = nil
+		object.Webhooks[i].ClientConfig.URL = &url
+	}
+}
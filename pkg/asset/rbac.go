@@ -18,6 +18,10 @@ func (a *Asset) RBAC() *rbac {
 type RBACItem struct {
 	Resource string
 	Object   operatorruntime.Object
+
+	// Aggregated is set on the item(s) only needed when the admission webhook
+	// is called through the aggregated API.
+	Aggregated bool
 }
 
 type rbac struct {
@@ -59,7 +63,8 @@ func (s *rbac) New() []*RBACItem {
 
 		// to read the config for terminating authentication
 		{
-			Resource: "rolebindings",
+			Resource:   "rolebindings",
+			Aggregated: true,
 			Object: &rbacv1.RoleBinding{
 				TypeMeta: metav1.TypeMeta{
 					Kind:       "RoleBinding",
@@ -83,7 +88,8 @@ func (s *rbac) New() []*RBACItem {
 
 		// to let aggregated apiservers create admission reviews
 		{
-			Resource: "clusterroles",
+			Resource:   "clusterroles",
+			Aggregated: true,
 			Object: &rbacv1.ClusterRole{
 				TypeMeta: metav1.TypeMeta{
 					Kind:       "ClusterRole",
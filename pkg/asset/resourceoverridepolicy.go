@@ -0,0 +1,154 @@
+package asset
+
+import (
+	"fmt"
+	"strings"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+)
+
+const (
+	resourceOverridePolicyName = "resourceoverride-policy"
+)
+
+func (a *Asset) NewResourceOverridePolicy() *resourceOverridePolicy {
+	return &resourceOverridePolicy{
+		values: a.values,
+	}
+}
+
+type resourceOverridePolicy struct {
+	values *Values
+}
+
+func (r *resourceOverridePolicy) Name() string {
+	return resourceOverridePolicyName
+}
+
+func (r *resourceOverridePolicy) New(policy *operatorv1.ResourceOverridePolicy) *admissionregistrationv1.ValidatingAdmissionPolicy {
+	failurePolicy := admissionregistrationv1.Fail
+
+	validations := make([]admissionregistrationv1.Validation, 0)
+	for _, field := range []struct {
+		name   string
+		bounds *operatorv1.PercentRange
+	}{
+		{name: "limitCPUToMemoryPercent", bounds: policy.LimitCPUToMemoryPercent},
+		{name: "cpuRequestToLimitPercent", bounds: policy.CPURequestToLimitPercent},
+		{name: "memoryRequestToLimitPercent", bounds: policy.MemoryRequestToLimitPercent},
+		{name: "cpuRequestToRequestPercent", bounds: policy.CPURequestToRequestPercent},
+	} {
+		if validation := newPercentRangeValidation(field.name, field.bounds); validation != nil {
+			validations = append(validations, *validation)
+		}
+	}
+
+	return &admissionregistrationv1.ValidatingAdmissionPolicy{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ValidatingAdmissionPolicy",
+			APIVersion: "admissionregistration.k8s.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: r.Name(),
+			Labels: map[string]string{
+				r.values.OwnerLabelKey: r.values.OwnerLabelValue,
+			},
+			Annotations: map[string]string{
+				r.values.PolicyHashAnnotationKey: policy.Hash(),
+			},
+		},
+		Spec: admissionregistrationv1.ValidatingAdmissionPolicySpec{
+			FailurePolicy: &failurePolicy,
+			MatchConstraints: &admissionregistrationv1.MatchResources{
+				ResourceRules: []admissionregistrationv1.NamedRuleWithOperations{
+					{
+						RuleWithOperations: admissionregistrationv1.RuleWithOperations{
+							Operations: []admissionregistrationv1.OperationType{
+								admissionregistrationv1.Create,
+								admissionregistrationv1.Update,
+							},
+							Rule: admissionregistrationv1.Rule{
+								APIGroups:   []string{"autoscaling.openshift.io"},
+								APIVersions: []string{"v1"},
+								Resources:   []string{"resourceoverrides"},
+							},
+						},
+					},
+				},
+			},
+			Validations: validations,
+		},
+	}
+}
+
+// newPercentRangeValidation returns a validation that checks the given field
+// of a ResourceOverride against the bounds, nil if there is nothing to enforce.
+// An unset (zero) field falls back to the ClusterResourceOverride and is allowed.
+func newPercentRangeValidation(field string, bounds *operatorv1.PercentRange) *admissionregistrationv1.Validation {
+	if bounds == nil || (bounds.Min == 0 && bounds.Max == 0) {
+		return nil
+	}
+
+	value := fmt.Sprintf("object.spec.podResourceOverride.%s", field)
+	checks := make([]string, 0, 2)
+	if bounds.Min != 0 {
+		checks = append(checks, fmt.Sprintf("%s >= %d", value, bounds.Min))
+	}
+	if bounds.Max != 0 {
+		checks = append(checks, fmt.Sprintf("%s <= %d", value, bounds.Max))
+	}
+
+	return &admissionregistrationv1.Validation{
+		Expression: fmt.Sprintf("!has(%s) || %s == 0 || (%s)", value, value, strings.Join(checks, " && ")),
+		Message:    fmt.Sprintf("%s must be within %s as set by the resourceOverridePolicy of the ClusterResourceOverride", field, bounds.String()),
+	}
+}
+
+func (a *Asset) NewResourceOverridePolicyBinding() *resourceOverridePolicyBinding {
+	return &resourceOverridePolicyBinding{
+		values: a.values,
+	}
+}
+
+type resourceOverridePolicyBinding struct {
+	values *Values
+}
+
+func (r *resourceOverridePolicyBinding) Name() string {
+	return resourceOverridePolicyName
+}
+
+func (r *resourceOverridePolicyBinding) New(policy *operatorv1.ResourceOverridePolicy) *admissionregistrationv1.ValidatingAdmissionPolicyBinding {
+	action := admissionregistrationv1.Deny
+	switch policy.GetAction() {
+	case operatorv1.PolicyActionWarn:
+		action = admissionregistrationv1.Warn
+	case operatorv1.PolicyActionAudit:
+		action = admissionregistrationv1.Audit
+	}
+
+	return &admissionregistrationv1.ValidatingAdmissionPolicyBinding{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ValidatingAdmissionPolicyBinding",
+			APIVersion: "admissionregistration.k8s.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: r.Name(),
+			Labels: map[string]string{
+				r.values.OwnerLabelKey: r.values.OwnerLabelValue,
+			},
+			Annotations: map[string]string{
+				r.values.PolicyHashAnnotationKey: policy.Hash(),
+			},
+		},
+		Spec: admissionregistrationv1.ValidatingAdmissionPolicyBindingSpec{
+			PolicyName: resourceOverridePolicyName,
+			ValidationActions: []admissionregistrationv1.ValidationAction{
+				action,
+			},
+		},
+	}
+}
@@ -1,6 +1,8 @@
 package asset
 
 import (
+	"strings"
+
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -9,6 +11,17 @@ const (
 	validatingAdmissionPolicyName = "resourceoverride-exempt-namespace"
 )
 
+// IsExemptNamespace returns true if ResourceOverride objects cannot be created
+// in the given namespace, the same as the ValidatingAdmissionPolicy enforces.
+func IsExemptNamespace(namespace string) bool {
+	switch namespace {
+	case "openshift", "kube", "kubernetes":
+		return true
+	}
+
+	return strings.HasPrefix(namespace, "openshift-") || strings.HasPrefix(namespace, "kube-") || strings.HasPrefix(namespace, "kubernetes-")
+}
+
 func (a *Asset) NewValidatingAdmissionPolicy() *validatingAdmissionPolicy {
 	return &validatingAdmissionPolicy{
 		values: a.values,
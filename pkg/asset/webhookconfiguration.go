@@ -14,11 +14,13 @@ const (
 func (a *Asset) NewMutatingWebhookConfiguration() *mutatingWebhookConfiguration {
 	return &mutatingWebhookConfiguration{
 		values: a.values,
+		asset:  a,
 	}
 }
 
 type mutatingWebhookConfiguration struct {
 	values *Values
+	asset  *Asset
 }
 
 func (m *mutatingWebhookConfiguration) Name() string {
@@ -44,7 +46,7 @@ func (m *mutatingWebhookConfiguration) New() *admissionregistrationv1.MutatingWe
 				m.values.OwnerLabelKey: m.values.OwnerLabelValue,
 			},
 			Annotations: map[string]string{
-				"service.beta.openshift.io/inject-cabundle": "true",
+				InjectCABundleAnnotationName: "true",
 			},
 		},
 		Webhooks: []admissionregistrationv1.MutatingWebhook{
@@ -0,0 +1,72 @@
+package asset
+
+import (
+	"fmt"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+)
+
+const (
+	// AuthenticationSkipLookupArg has the operand skip reading the
+	// extension-apiserver-authentication ConfigMap in kube-system, which only
+	// the aggregated API needs to authenticate the front proxy.
+	AuthenticationSkipLookupArg = "--authentication-skip-lookup"
+
+	// AuthorizationAlwaysAllowPathsArg lists the path(s) the operand serves
+	// without delegated authorization.
+	AuthorizationAlwaysAllowPathsArg = "--authorization-always-allow-paths"
+)
+
+// AdmissionPath returns the path the admission webhook serves admission
+// reviews on.
+func (a *Asset) AdmissionPath() string {
+	return fmt.Sprintf("/apis/%s/%s/%s", a.values.AdmissionAPIGroup, a.values.AdmissionAPIVersion, a.values.AdmissionAPIResource)
+}
+
+// ApplyWebhookTransport points the webhooks of the given configuration at the
+// Service of the admission webhook with the Service transport, by URL if the
+// admission webhook runs in a management cluster. With the Aggregated
+// transport they call the kube-apiserver, which proxies to the APIService.
+func (m *mutatingWebhookConfiguration) ApplyWebhookTransport(object *admissionregistrationv1.MutatingWebhookConfiguration, transport operatorv1.WebhookTransport) {
+	if transport != operatorv1.WebhookTransportService {
+		return
+	}
+
+	if m.asset.OnManagementCluster() {
+		m.applyHostedClientConfig(object)
+		return
+	}
+
+	// the admission webhook serves the same path either way.
+	port := int32(443)
+	for i := range object.Webhooks {
+		service := object.Webhooks[i].ClientConfig.Service
+		if service == nil {
+			continue
+		}
+
+		service.Name = m.values.Name
+		service.Namespace = m.values.Namespace
+		service.Port = &port
+	}
+}
+
+// WebhookTransportArgs returns the operand argument(s) the given webhook
+// transport needs. The kube-apiserver calls a webhook Service anonymously,
+// so with the Service transport the admission path is served without
+// delegated authorization, and the front proxy configuration is not read.
+// Any client that reaches the Service can then call the admission path, which
+// is acceptable as it has no side effect; this is documented on the
+// webhookTransport field.
+func (a *Asset) WebhookTransportArgs(transport operatorv1.WebhookTransport) []string {
+	if transport != operatorv1.WebhookTransportService {
+		return nil
+	}
+
+	return []string{
+		AuthenticationSkipLookupArg,
+		fmt.Sprintf("%s=/healthz,/readyz,/livez,%s", AuthorizationAlwaysAllowPathsArg, a.AdmissionPath()),
+	}
+}
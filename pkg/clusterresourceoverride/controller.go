@@ -36,7 +36,11 @@ type Options struct {
 	Client         *operatorruntime.Client
 	RuntimeContext operatorruntime.OperandContext
 	Lister         *secondarywatch.Lister
-	IsStandalone   bool
+	Platform       handlers.PlatformDetector
+
+	// OperandClient is the client of the management cluster the admission
+	// webhook runs in, nil if it runs in the cluster of Client.
+	OperandClient *operatorruntime.Client
 }
 
 func New(options *Options) (c controller.Interface, e operatorruntime.Enqueuer, err error) {
@@ -74,18 +78,24 @@ func New(options *Options) (c controller.Interface, e operatorruntime.Enqueuer,
 	// setup operand asset
 	operandAsset := asset.New(options.RuntimeContext)
 
+	operandClient := options.OperandClient
+	if operandClient == nil {
+		operandClient = options.Client
+	}
+
 	// initialize install strategy, we use deployment
-	d := deploy.NewDeploymentInstall(options.Lister.AppsV1DeploymentLister(), options.RuntimeContext, operandAsset, ensurer.NewDeploymentEnsurer(options.Client.Dynamic))
+	d := deploy.NewDeploymentInstall(options.Lister.AppsV1DeploymentLister(), options.RuntimeContext, operandAsset, ensurer.NewDeploymentEnsurer(operandClient.Dynamic))
 
 	reconciler := reconciler.NewReconciler(&handlers.Options{
 		OperandContext:  options.RuntimeContext,
 		Client:          options.Client,
+		OperandClient:   operandClient,
 		PrimaryLister:   lister,
 		SecondaryLister: options.Lister,
 		Asset:           operandAsset,
 		Deploy:          d,
 		DynamicClient:   options.Client.RawDynamic,
-		IsStandalone:    options.IsStandalone,
+		Platform:        options.Platform,
 	})
 
 	c = &clusterResourceOverrideController{
@@ -95,6 +95,21 @@ func (b *Builder) WithAvailable(status corev1.ConditionStatus, message string) (
 	return b
 }
 
+// WithoutCondition removes the condition of the given type, if any.
+func (b *Builder) WithoutCondition(conditionType operatorv1.ClusterResourceOverrideConditionType) (builder *Builder) {
+	builder = b
+
+	conditions := b.status.Conditions[:0]
+	for _, c := range b.status.Conditions {
+		if c.Type != conditionType {
+			conditions = append(conditions, c)
+		}
+	}
+	b.status.Conditions = conditions
+
+	return
+}
+
 func (b *Builder) WithCondition(desired *operatorv1.ClusterResourceOverrideCondition) {
 	if desired == nil {
 		return
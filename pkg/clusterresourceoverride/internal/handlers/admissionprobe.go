@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+	controllerreconciler "sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/clusterresourceoverride/internal/condition"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/metrics"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/resourceoverride/simulate"
+)
+
+const (
+	// AdmissionProbePeriod is how often the admission path is probed.
+	AdmissionProbePeriod = time.Minute
+
+	// AdmissionProbeTimeout bounds the dry-run pod create, longer than the
+	// timeout of the admission webhook so that a slow webhook is told apart.
+	AdmissionProbeTimeout = 15 * time.Second
+)
+
+// NewAdmissionProbeHandler returns a handler that periodically creates a pod
+// with a server-side dry-run in the admission probe namespace, and checks it
+// is mutated according to the current configuration. The result is recorded in
+// status.admissionProbe and folded into the Available condition by the
+// availability handler. The probe namespace is shipped with the operator
+// manifests, the operator is not allowed to create namespaces. The probe is
+// inconclusive while it does not exist or is not opted in.
+func NewAdmissionProbeHandler(o *Options) *admissionProbeHandler {
+	return &admissionProbeHandler{
+		clock:  clock.RealClock{},
+		client: o.Client.Kubernetes,
+		asset:  o.Asset,
+	}
+}
+
+type admissionProbeHandler struct {
+	clock  clock.PassiveClock
+	client kubernetes.Interface
+	asset  *asset.Asset
+
+	// user is the name the operator authenticates as, the subject of the
+	// RoleBinding in the probe namespace. It is looked up once.
+	user string
+}
+
+func (a *admissionProbeHandler) Handle(context *ReconcileRequestContext, original *operatorv1.ClusterResourceOverride) (current *operatorv1.ClusterResourceOverride, result controllerreconciler.Result, handleErr error) {
+	current = original
+
+	if last := original.Status.AdmissionProbe; last != nil {
+		if remaining := AdmissionProbePeriod - a.clock.Since(last.LastProbeTime.Time); remaining > 0 {
+			context.RequeueAfter(remaining)
+			return
+		}
+	}
+
+	ns, notReady, err := a.getNamespace()
+	if err != nil {
+		handleErr = condition.NewInstallReadinessError(operatorv1.InternalError, err)
+		return
+	}
+
+	var probe *operatorv1.AdmissionProbeStatus
+	if notReady != "" {
+		probe = &operatorv1.AdmissionProbeStatus{
+			LastProbeTime: metav1.NewTime(a.clock.Now()),
+			Result:        operatorv1.AdmissionProbeInconclusive,
+			Reason:        operatorv1.AdmissionProbeNamespaceNotReady,
+			Message:       notReady,
+		}
+	} else {
+		if err := a.ensureRoleBinding(context, original); err != nil {
+			handleErr = condition.NewInstallReadinessError(operatorv1.InternalError, err)
+			return
+		}
+
+		probe = a.probe(original, ns)
+	}
+	current.Status.AdmissionProbe = probe
+	context.RequeueAfter(AdmissionProbePeriod)
+
+	metrics.AdmissionProbeDuration.Observe(probe.Latency.Seconds())
+	metrics.AdmissionProbeTotal.WithLabelValues(string(probe.Result), probe.Reason).Inc()
+	if probe.Result == operatorv1.AdmissionProbeSucceeded {
+		metrics.AdmissionProbeSuccess.Set(1)
+	} else {
+		metrics.AdmissionProbeSuccess.Set(0)
+	}
+
+	klog.V(2).Infof("key=%s admission probe result=%s reason=%s latency=%s %s", original.Name, probe.Result, probe.Reason, probe.Latency.Duration, probe.Message)
+	return
+}
+
+// getNamespace returns the probe namespace, or why it cannot be probed.
+func (a *admissionProbeHandler) getNamespace() (ns *corev1.Namespace, notReady string, err error) {
+	name := a.asset.AdmissionProbe().Namespace()
+	key := a.asset.Values().NamespaceOptInLabelKey
+
+	ns, err = a.client.CoreV1().Namespaces().Get(context.TODO(), name, metav1.GetOptions{})
+	switch {
+	case k8serrors.IsNotFound(err):
+		return nil, fmt.Sprintf("namespace %s does not exist, create it with the label %s=true to probe the admission path", name, key), nil
+	case err != nil:
+		return nil, "", fmt.Errorf("failed to get namespace %s - %s", name, err.Error())
+	case ns.DeletionTimestamp != nil:
+		return nil, fmt.Sprintf("namespace %s is being deleted", name), nil
+	case ns.Labels[key] != "true":
+		return nil, fmt.Sprintf("namespace %s is not labeled %s=true, the probe pod would not be mutated", name, key), nil
+	default:
+		return ns, "", nil
+	}
+}
+
+// ensureRoleBinding lets the operator create the probe pod, in the probe
+// namespace only. The operator is not allowed to create pods elsewhere.
+func (a *admissionProbeHandler) ensureRoleBinding(reconcileContext *ReconcileRequestContext, original *operatorv1.ClusterResourceOverride) error {
+	user, err := a.getUser()
+	if err != nil {
+		return err
+	}
+
+	desired := a.asset.AdmissionProbe().NewRoleBinding(user)
+
+	current, err := a.client.RbacV1().RoleBindings(desired.Namespace).Get(context.TODO(), desired.Name, metav1.GetOptions{})
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get rolebinding %s/%s - %s", desired.Namespace, desired.Name, err.Error())
+		}
+
+		reconcileContext.ControllerSetter().Set(desired, original)
+		if _, err := a.client.RbacV1().RoleBindings(desired.Namespace).Create(context.TODO(), desired, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create rolebinding %s/%s - %s", desired.Namespace, desired.Name, err.Error())
+		}
+
+		klog.V(2).Infof("key=%s resource=%T/%s successfully created", original.Name, desired, desired.Name)
+		return nil
+	}
+
+	if equality.Semantic.DeepEqual(current.Subjects, desired.Subjects) {
+		return nil
+	}
+
+	update := current.DeepCopy()
+	update.Subjects = desired.Subjects
+	if _, err := a.client.RbacV1().RoleBindings(update.Namespace).Update(context.TODO(), update, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update rolebinding %s/%s - %s", update.Namespace, update.Name, err.Error())
+	}
+
+	klog.V(2).Infof("key=%s resource=%T/%s successfully updated", original.Name, update, update.Name)
+	return nil
+}
+
+// getUser returns the name the operator authenticates as. It is not known in
+// advance, the operator may run outside of the cluster with a kubeconfig.
+func (a *admissionProbeHandler) getUser() (string, error) {
+	if a.user != "" {
+		return a.user, nil
+	}
+
+	review, err := a.client.AuthenticationV1().SelfSubjectReviews().Create(context.TODO(), &authenticationv1.SelfSubjectReview{}, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to review the user of the operator - %s", err.Error())
+	}
+	if review.Status.UserInfo.Username == "" {
+		return "", errors.New("failed to review the user of the operator - no username")
+	}
+
+	a.user = review.Status.UserInfo.Username
+	return a.user, nil
+}
+
+// probe sends the probe pod through the admission path, and compares the pod
+// as admitted with the pod simulated from the current configuration.
+func (a *admissionProbeHandler) probe(original *operatorv1.ClusterResourceOverride, ns *corev1.Namespace) *operatorv1.AdmissionProbeStatus {
+	pod := a.asset.AdmissionProbe().NewPod()
+	status := &operatorv1.AdmissionProbeStatus{
+		LastProbeTime: metav1.NewTime(a.clock.Now()),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), AdmissionProbeTimeout)
+	defer cancel()
+
+	start := a.clock.Now()
+	admitted, err := a.client.CoreV1().Pods(pod.Namespace).Create(ctx, pod, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+	status.Latency = metav1.Duration{Duration: a.clock.Since(start)}
+
+	if err != nil {
+		status.Result, status.Reason = a.classify(err)
+		status.Message = fmt.Sprintf("dry-run pod create failed - %s", err.Error())
+		return status
+	}
+
+	expected, err := simulate.Simulate(&simulate.Input{
+		Pod:                     pod,
+		ClusterResourceOverride: original,
+		Namespaces:              []*corev1.Namespace{ns},
+	})
+	if err != nil {
+		status.Result, status.Reason = operatorv1.AdmissionProbeInconclusive, operatorv1.AdmissionProbeError
+		status.Message = fmt.Sprintf("failed to simulate the probe pod - %s", err.Error())
+		return status
+	}
+
+	diffs := compareResources(admitted, expected.Pod)
+	if len(diffs) == 0 {
+		status.Result = operatorv1.AdmissionProbeSucceeded
+		return status
+	}
+
+	status.Result = operatorv1.AdmissionProbeFailed
+	status.Reason = operatorv1.AdmissionWebhookMutationMismatch
+	status.Message = fmt.Sprintf("the probe pod was not mutated according to the current configuration: %s", strings.Join(diffs, ", "))
+
+	// the pod as admitted by the API server alone, with the namespace opted out.
+	optedOut := ns.DeepCopy()
+	delete(optedOut.Labels, a.asset.Values().NamespaceOptInLabelKey)
+	if unmutated, err := simulate.Simulate(&simulate.Input{
+		Pod:                     pod,
+		ClusterResourceOverride: original,
+		Namespaces:              []*corev1.Namespace{optedOut},
+	}); err == nil && len(compareResources(admitted, unmutated.Pod)) == 0 {
+		status.Reason = operatorv1.AdmissionWebhookNotMutating
+		status.Message = "the probe pod was admitted without overrides, the admission webhook was not called"
+	}
+
+	return status
+}
+
+// classify returns the result and reason of a failed dry-run pod create. An
+// error that did not come from calling the admission webhook is inconclusive.
+func (a *admissionProbeHandler) classify(err error) (operatorv1.AdmissionProbeResult, string) {
+	message := err.Error()
+	webhook := a.asset.NewMutatingWebhookConfiguration().Name()
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return operatorv1.AdmissionProbeFailed, operatorv1.AdmissionWebhookTimeout
+	case !strings.Contains(message, fmt.Sprintf("failed calling webhook %q", webhook)):
+		return operatorv1.AdmissionProbeInconclusive, operatorv1.AdmissionProbeError
+	case strings.Contains(message, "x509:") || strings.Contains(message, "tls:"):
+		return operatorv1.AdmissionProbeFailed, operatorv1.AdmissionWebhookCertificateInvalid
+	case strings.Contains(message, "deadline exceeded") || strings.Contains(message, "Timeout"):
+		return operatorv1.AdmissionProbeFailed, operatorv1.AdmissionWebhookTimeout
+	case strings.Contains(message, "the server is currently unable to handle the request") || strings.Contains(message, "service unavailable"):
+		return operatorv1.AdmissionProbeFailed, operatorv1.AdmissionAPIServiceNotAvailable
+	default:
+		return operatorv1.AdmissionProbeFailed, operatorv1.AdmissionWebhookCallFailed
+	}
+}
+
+// compareResources returns how the container resources of the admitted pod
+// differ from the expected ones.
+func compareResources(admitted, expected *corev1.Pod) []string {
+	var diffs []string
+	for i := range expected.Spec.Containers {
+		if i >= len(admitted.Spec.Containers) {
+			break
+		}
+
+		name := expected.Spec.Containers[i].Name
+		for _, values := range []struct {
+			kind               string
+			admitted, expected corev1.ResourceList
+		}{
+			{"requests", admitted.Spec.Containers[i].Resources.Requests, expected.Spec.Containers[i].Resources.Requests},
+			{"limits", admitted.Spec.Containers[i].Resources.Limits, expected.Spec.Containers[i].Resources.Limits},
+		} {
+			keys := map[corev1.ResourceName]bool{}
+			for key := range values.admitted {
+				keys[key] = true
+			}
+			for key := range values.expected {
+				keys[key] = true
+			}
+
+			for key := range keys {
+				got, want := values.admitted[key], values.expected[key]
+				if got.Cmp(want) != 0 {
+					diffs = append(diffs, fmt.Sprintf("container %s %s.%s is %s, expected %s", name, values.kind, key, got.String(), want.String()))
+				}
+			}
+		}
+	}
+
+	sort.Strings(diffs)
+	return diffs
+}
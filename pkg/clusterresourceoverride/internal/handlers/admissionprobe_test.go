@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/clusterresourceoverride/internal/condition"
+	operatorruntime "github.com/openshift/cluster-resource-override-admission-operator/pkg/runtime"
+)
+
+// admit returns a reactor admitting the dry-run probe pod with the given
+// resources, or failing with the given error.
+func admit(requests, limits corev1.ResourceList, err error) clienttesting.ReactionFunc {
+	return func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if err != nil {
+			return true, nil, err
+		}
+
+		pod := action.(clienttesting.CreateAction).GetObject().(*corev1.Pod).DeepCopy()
+		pod.Spec.Containers[0].Resources = corev1.ResourceRequirements{Requests: requests, Limits: limits}
+		return true, pod, nil
+	}
+}
+
+// reviewSelf returns a reactor reviewing the operator as the given user.
+func reviewSelf(user string) clienttesting.ReactionFunc {
+	return func(action clienttesting.Action) (bool, runtime.Object, error) {
+		review := action.(clienttesting.CreateAction).GetObject().(*authenticationv1.SelfSubjectReview).DeepCopy()
+		review.Status.UserInfo.Username = user
+		return true, review, nil
+	}
+}
+
+// probeNamespace returns the opted-in probe namespace, as shipped with the
+// operator manifests.
+func probeNamespace() *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "clusterresourceoverride-admission-probe",
+			Labels: map[string]string{asset.NamespaceOptInLabelKey: "true"},
+		},
+	}
+}
+
+func resources(cpu, memory string) corev1.ResourceList {
+	return corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(cpu),
+		corev1.ResourceMemory: resource.MustParse(memory),
+	}
+}
+
+func TestAdmissionProbeHandler(t *testing.T) {
+	tests := []struct {
+		name    string
+		reactor clienttesting.ReactionFunc
+		result  operatorv1.AdmissionProbeResult
+		reason  string
+	}{
+		{
+			name:    "mutated",
+			reactor: admit(resources("500m", "512Mi"), resources("2", "1Gi"), nil),
+			result:  operatorv1.AdmissionProbeSucceeded,
+		},
+		{
+			name:    "not mutated",
+			reactor: admit(resources("1", "1Gi"), resources("1", "1Gi"), nil),
+			result:  operatorv1.AdmissionProbeFailed,
+			reason:  operatorv1.AdmissionWebhookNotMutating,
+		},
+		{
+			name:    "stale configuration",
+			reactor: admit(resources("250m", "512Mi"), resources("2", "1Gi"), nil),
+			result:  operatorv1.AdmissionProbeFailed,
+			reason:  operatorv1.AdmissionWebhookMutationMismatch,
+		},
+		{
+			name:    "bad CA bundle",
+			reactor: admit(nil, nil, errors.New(`Internal error occurred: failed calling webhook "clusterresourceoverrides.admission.autoscaling.openshift.io": failed to call webhook: Post "https://kubernetes.default.svc:443/apis/admission.autoscaling.openshift.io/v1/clusterresourceoverrides?timeout=5s": tls: failed to verify certificate: x509: certificate signed by unknown authority`)),
+			result:  operatorv1.AdmissionProbeFailed,
+			reason:  operatorv1.AdmissionWebhookCertificateInvalid,
+		},
+		{
+			name:    "unavailable APIService",
+			reactor: admit(nil, nil, errors.New(`Internal error occurred: failed calling webhook "clusterresourceoverrides.admission.autoscaling.openshift.io": failed to call webhook: the server is currently unable to handle the request`)),
+			result:  operatorv1.AdmissionProbeFailed,
+			reason:  operatorv1.AdmissionAPIServiceNotAvailable,
+		},
+		{
+			name:    "rejected before the admission webhook",
+			reactor: admit(nil, nil, errors.New(`pods "clusterresourceoverride-admission-probe" is forbidden: error looking up service account`)),
+			result:  operatorv1.AdmissionProbeInconclusive,
+			reason:  operatorv1.AdmissionProbeError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0")
+			client := kubefake.NewSimpleClientset(probeNamespace())
+			client.PrependReactor("create", "pods", test.reactor)
+			client.PrependReactor("create", "selfsubjectreviews", reviewSelf("system:serviceaccount:test-ns:clusterresourceoverride-operator"))
+
+			handler := &admissionProbeHandler{
+				clock:  &fakeClock{now: time.Date(2024, 7, 24, 17, 0, 0, 0, time.UTC)},
+				client: client,
+				asset:  asset.New(ctx),
+			}
+
+			cro := minimalCRO()
+			cro.Spec.PodResourceOverride.Spec = operatorv1.PodResourceOverrideSpec{
+				MemoryRequestToLimitPercent: 50,
+				CPURequestToLimitPercent:    25,
+				LimitCPUToMemoryPercent:     200,
+			}
+
+			reconcileContext := NewReconcileRequestContext(ctx)
+			current, result, err := handler.Handle(reconcileContext, cro)
+			require.NoError(t, err)
+			require.Zero(t, result.RequeueAfter)
+			require.Equal(t, AdmissionProbePeriod, reconcileContext.GetRequeueAfter())
+
+			probe := current.Status.AdmissionProbe
+			require.NotNil(t, probe)
+			require.Equal(t, test.result, probe.Result, probe.Message)
+			require.Equal(t, test.reason, probe.Reason)
+		})
+	}
+}
+
+func TestAdmissionProbeHandlerNamespaceNotReady(t *testing.T) {
+	notLabeled := probeNamespace()
+	notLabeled.Labels = nil
+
+	tests := []struct {
+		name    string
+		objects []runtime.Object
+		message string
+	}{
+		{
+			name:    "missing",
+			message: "does not exist",
+		},
+		{
+			name:    "not opted in",
+			objects: []runtime.Object{notLabeled},
+			message: "is not labeled",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0")
+			client := kubefake.NewSimpleClientset(test.objects...)
+			handler := &admissionProbeHandler{
+				clock:  &fakeClock{now: time.Date(2024, 7, 24, 17, 0, 0, 0, time.UTC)},
+				client: client,
+				asset:  asset.New(ctx),
+			}
+
+			reconcileContext := NewReconcileRequestContext(ctx)
+			current, _, err := handler.Handle(reconcileContext, minimalCRO())
+			require.NoError(t, err)
+			require.Equal(t, AdmissionProbePeriod, reconcileContext.GetRequeueAfter())
+
+			probe := current.Status.AdmissionProbe
+			require.Equal(t, operatorv1.AdmissionProbeInconclusive, probe.Result)
+			require.Equal(t, operatorv1.AdmissionProbeNamespaceNotReady, probe.Reason)
+			require.Contains(t, probe.Message, test.message)
+
+			// the operator neither creates the namespace nor probes it.
+			for _, action := range client.Actions() {
+				require.Equal(t, "get", action.GetVerb(), action.GetResource().Resource)
+			}
+		})
+	}
+}
+
+func TestAdmissionProbeNamespaceNotExempt(t *testing.T) {
+	for _, namespace := range []string{"openshift-cluster-resource-override", "kube-system", "test-ns"} {
+		ctx := operatorruntime.NewOperandContext("clusterresourceoverride", namespace, "cluster", "img", "1.0")
+		probe := asset.New(ctx).AdmissionProbe()
+		require.False(t, asset.IsExemptNamespace(probe.Namespace()), "operator namespace %s", namespace)
+	}
+}
+
+func TestAdmissionProbeHandlerRoleBinding(t *testing.T) {
+	ctx := operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0")
+	user := "system:serviceaccount:test-ns:clusterresourceoverride-operator"
+
+	stale := asset.New(ctx).AdmissionProbe().NewRoleBinding("system:serviceaccount:test-ns:old")
+	client := kubefake.NewSimpleClientset(probeNamespace(), stale)
+	client.PrependReactor("create", "pods", admit(resources("500m", "512Mi"), resources("2", "1Gi"), nil))
+	client.PrependReactor("create", "selfsubjectreviews", reviewSelf(user))
+
+	handler := &admissionProbeHandler{
+		clock:  &fakeClock{now: time.Date(2024, 7, 24, 17, 0, 0, 0, time.UTC)},
+		client: client,
+		asset:  asset.New(ctx),
+	}
+
+	_, _, err := handler.Handle(NewReconcileRequestContext(ctx), minimalCRO())
+	require.NoError(t, err)
+
+	binding, err := client.RbacV1().RoleBindings("clusterresourceoverride-admission-probe").Get(context.TODO(), "clusterresourceoverride-admission-probe", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: "clusterresourceoverride-admission-probe"}, binding.RoleRef)
+	require.Equal(t, []rbacv1.Subject{{APIGroup: "rbac.authorization.k8s.io", Kind: "User", Name: user}}, binding.Subjects)
+
+	// the user is reviewed once.
+	handler.clock = &fakeClock{now: time.Date(2024, 7, 24, 18, 0, 0, 0, time.UTC)}
+	_, _, err = handler.Handle(NewReconcileRequestContext(ctx), minimalCRO())
+	require.NoError(t, err)
+
+	reviews := 0
+	for _, action := range client.Actions() {
+		if action.GetResource().Resource == "selfsubjectreviews" {
+			reviews++
+		}
+	}
+	require.Equal(t, 1, reviews)
+}
+
+func TestAdmissionProbeHandlerPeriod(t *testing.T) {
+	ctx := operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0")
+	client := kubefake.NewSimpleClientset()
+	clock := &fakeClock{now: time.Date(2024, 7, 24, 17, 0, 0, 0, time.UTC)}
+	handler := &admissionProbeHandler{clock: clock, client: client, asset: asset.New(ctx)}
+
+	cro := minimalCRO()
+	cro.Status.AdmissionProbe = &operatorv1.AdmissionProbeStatus{
+		LastProbeTime: metav1.NewTime(clock.now.Add(-20 * time.Second)),
+		Result:        operatorv1.AdmissionProbeSucceeded,
+	}
+
+	context := NewReconcileRequestContext(ctx)
+	current, _, err := handler.Handle(context, cro)
+	require.NoError(t, err)
+	require.Equal(t, 40*time.Second, context.GetRequeueAfter())
+	require.Equal(t, cro.Status.AdmissionProbe, current.Status.AdmissionProbe)
+	require.Empty(t, client.Actions())
+}
+
+func TestAvailabilityHandlerAdmissionProbe(t *testing.T) {
+	handler, _ := newAvailabilityHandler(t)
+
+	cro := minimalCRO()
+	cro.Status.AdmissionProbe = &operatorv1.AdmissionProbeStatus{
+		Result:  operatorv1.AdmissionProbeFailed,
+		Reason:  operatorv1.AdmissionWebhookNotMutating,
+		Message: "the probe pod was admitted without overrides",
+	}
+
+	context := NewReconcileRequestContext(operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0"))
+	current, _, err := handler.Handle(context, cro)
+	require.NoError(t, err)
+
+	available := condition.Find(&current.Status, operatorv1.Available)
+	require.Equal(t, corev1.ConditionFalse, available.Status)
+	require.Equal(t, operatorv1.AdmissionWebhookNotMutating, available.Reason)
+
+	current.Status.AdmissionProbe.Result = operatorv1.AdmissionProbeInconclusive
+	current, _, err = handler.Handle(context, current)
+	require.NoError(t, err)
+	require.Equal(t, corev1.ConditionTrue, condition.Find(&current.Status, operatorv1.Available).Status)
+}
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 
 	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/reference"
@@ -10,14 +12,19 @@ import (
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/ensurer"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	listerscorev1 "k8s.io/client-go/listers/core/v1"
 	"k8s.io/klog/v2"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
 	apiregistrationclientset "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset"
+	listersapiregistrationv1 "k8s.io/kube-aggregator/pkg/client/listers/apiregistration/v1"
 	controllerreconciler "sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 func NewAPIServiceHandler(o *Options) *apiServiceHandler {
 	return &apiServiceHandler{
 		client:  o.Client.APIRegistration,
+		lister:  o.SecondaryLister.APIRegistrationV1APIServiceLister(),
+		secrets: o.SecondaryLister.CoreV1SecretLister(),
 		ensurer: ensurer.NewAPIServiceEnsurer(o.Client.Dynamic),
 		asset:   o.Asset,
 	}
@@ -25,6 +32,8 @@ func NewAPIServiceHandler(o *Options) *apiServiceHandler {
 
 type apiServiceHandler struct {
 	client  apiregistrationclientset.Interface
+	lister  listersapiregistrationv1.APIServiceLister
+	secrets listerscorev1.SecretLister
 	ensurer *ensurer.APIServiceEnsurer
 	asset   *asset.Asset
 }
@@ -32,9 +41,19 @@ type apiServiceHandler struct {
 func (a *apiServiceHandler) Handle(ctx *ReconcileRequestContext, original *operatorv1.ClusterResourceOverride) (current *operatorv1.ClusterResourceOverride, result controllerreconciler.Result, handleErr error) {
 	current = original
 
+	if a.asset.WebhookTransport(original.Spec.GetWebhookTransport()) == operatorv1.WebhookTransportService {
+		if err := a.remove(original); err != nil {
+			handleErr = condition.NewInstallReadinessError(operatorv1.AdmissionWebhookNotAvailable, err)
+			return
+		}
+
+		current.Status.Resources.APiServiceRef = nil
+		return
+	}
+
 	ensure := false
 	name := a.asset.APIService().Name()
-	object, err := a.client.ApiregistrationV1().APIServices().Get(context.TODO(), name, metav1.GetOptions{})
+	object, err := a.lister.Get(name)
 	if err != nil {
 		if !k8serrors.IsNotFound(err) {
 			handleErr = condition.NewInstallReadinessError(operatorv1.AdmissionWebhookNotAvailable, err)
@@ -55,6 +74,7 @@ func (a *apiServiceHandler) Handle(ctx *ReconcileRequestContext, original *opera
 
 	if ensure {
 		object = a.asset.APIService().New()
+		asset.ApplyCertificateProvider(object, original.Spec.GetCertificateProvider())
 		ctx.ControllerSetter().Set(object, current)
 		apiservice, err := a.ensurer.Ensure(object)
 		if err != nil {
@@ -66,6 +86,12 @@ func (a *apiServiceHandler) Handle(ctx *ReconcileRequestContext, original *opera
 		klog.V(2).Infof("key=%s resource=%T/%s successfully created/updated", original.Name, object, object.Name)
 	}
 
+	object, err = a.injectCABundle(ctx, original, object)
+	if err != nil {
+		handleErr = condition.NewInstallReadinessError(operatorv1.CertNotAvailable, err)
+		return
+	}
+
 	if ref := original.Status.Resources.APiServiceRef; ref != nil && ref.ResourceVersion == object.ResourceVersion {
 		klog.V(2).Infof("key=%s resource=%T/%s is in sync", original.Name, object, object.Name)
 		return
@@ -82,3 +108,66 @@ func (a *apiServiceHandler) Handle(ctx *ReconcileRequestContext, original *opera
 
 	return
 }
+
+// remove deletes the APIService, the MutatingWebhookConfiguration calls the
+// Service of the admission webhook directly with the Service transport.
+func (a *apiServiceHandler) remove(original *operatorv1.ClusterResourceOverride) error {
+	name := a.asset.APIService().Name()
+	if _, err := a.lister.Get(name); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if err := a.client.ApiregistrationV1().APIServices().Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete APIService %s - %s", name, err.Error())
+	}
+
+	klog.V(2).Infof("key=%s resource=APIService/%s deleted, not needed with the %s webhook transport", original.Name, name, operatorv1.WebhookTransportService)
+	return nil
+}
+
+// injectCABundle sets the CA bundle verifying the admission webhook on the
+// given APIService. With the ServiceCA provider, the service-ca operator is
+// asked to inject it.
+func (a *apiServiceHandler) injectCABundle(ctx *ReconcileRequestContext, original *operatorv1.ClusterResourceOverride, object *apiregistrationv1.APIService) (*apiregistrationv1.APIService, error) {
+	_, annotated := object.Annotations[asset.AlphaInjectCABundleAnnotationName]
+	apiservice := object.DeepCopy()
+
+	if original.Spec.GetCertificateProvider() == operatorv1.CertificateProviderServiceCA {
+		if annotated {
+			return object, nil
+		}
+
+		if apiservice.Annotations == nil {
+			apiservice.Annotations = map[string]string{}
+		}
+		apiservice.Annotations[asset.AlphaInjectCABundleAnnotationName] = "true"
+	} else {
+		name := a.asset.ServiceServingSecret().Name()
+		secret, err := a.secrets.Secrets(ctx.WebhookNamespace()).Get(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the CA bundle from Secret %s - %s", name, err.Error())
+		}
+
+		bundle := secret.Data[asset.CABundleKey]
+		if len(bundle) == 0 {
+			return nil, fmt.Errorf("no %s in Secret %s", asset.CABundleKey, name)
+		}
+		if !annotated && bytes.Equal(object.Spec.CABundle, bundle) {
+			return object, nil
+		}
+
+		delete(apiservice.Annotations, asset.AlphaInjectCABundleAnnotationName)
+		apiservice.Spec.CABundle = bundle
+	}
+
+	updated, err := a.client.ApiregistrationV1().APIServices().Update(context.TODO(), apiservice, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to inject the CA bundle into APIService %s - %s", apiservice.Name, err.Error())
+	}
+
+	klog.V(2).Infof("key=%s resource=%T/%s CA bundle injected for certificate provider %s", original.Name, updated, updated.Name, original.Spec.GetCertificateProvider())
+	return updated, nil
+}
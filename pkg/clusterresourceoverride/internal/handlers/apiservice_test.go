@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+	listersapiregistrationv1 "k8s.io/kube-aggregator/pkg/client/listers/apiregistration/v1"
+
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/clusterresourceoverride/internal/condition"
+	operatorruntime "github.com/openshift/cluster-resource-override-admission-operator/pkg/runtime"
+)
+
+// newAvailabilityHandler returns an availability handler with an available
+// deployment, and the indexer backing its APIService lister.
+func newAvailabilityHandler(t *testing.T) (*availabilityHandler, cache.Indexer) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	handler := &availabilityHandler{
+		asset:  asset.New(operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0")),
+		deploy: &fakeDeploy{available: true},
+		lister: listersapiregistrationv1.NewAPIServiceLister(indexer),
+	}
+
+	setAPIServiceAvailable(t, handler, indexer, apiregistrationv1.ConditionTrue, "")
+	return handler, indexer
+}
+
+// setAPIServiceAvailable adds or updates the APIService of the handler with
+// the given Available condition.
+func setAPIServiceAvailable(t *testing.T, handler *availabilityHandler, indexer cache.Indexer, status apiregistrationv1.ConditionStatus, message string) {
+	apiservice := handler.asset.APIService().New()
+	apiservice.Status.Conditions = []apiregistrationv1.APIServiceCondition{
+		{Type: apiregistrationv1.Available, Status: status, Reason: "FailedDiscoveryCheck", Message: message},
+	}
+
+	require.NoError(t, indexer.Update(apiservice))
+}
+
+func TestAvailabilityHandlerAPIService(t *testing.T) {
+	handler, indexer := newAvailabilityHandler(t)
+	context := NewReconcileRequestContext(operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0"))
+
+	current, _, err := handler.Handle(context, minimalCRO())
+	require.NoError(t, err)
+	require.Equal(t, corev1.ConditionTrue, condition.Find(&current.Status, operatorv1.Available).Status)
+
+	message := "failing or missing response from https://10.128.0.12:9400/apis/admission.autoscaling.openshift.io/v1: context deadline exceeded"
+	setAPIServiceAvailable(t, handler, indexer, apiregistrationv1.ConditionFalse, message)
+
+	current, _, err = handler.Handle(context, current)
+	require.NoError(t, err)
+
+	available := condition.Find(&current.Status, operatorv1.Available)
+	require.Equal(t, corev1.ConditionFalse, available.Status)
+	require.Equal(t, operatorv1.AdmissionAPIServiceNotAvailable, available.Reason)
+	require.Contains(t, available.Message, message)
+
+	require.NoError(t, indexer.Delete(handler.asset.APIService().New()))
+	current, _, err = handler.Handle(context, current)
+	require.NoError(t, err)
+	require.Equal(t, operatorv1.AdmissionAPIServiceNotAvailable, condition.Find(&current.Status, operatorv1.Available).Reason)
+}
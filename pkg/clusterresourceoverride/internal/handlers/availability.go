@@ -1,11 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/klog/v2"
+	listersapiregistrationv1 "k8s.io/kube-aggregator/pkg/client/listers/apiregistration/v1"
 	controllerreconciler "sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
@@ -18,12 +20,14 @@ func NewAvailabilityHandler(o *Options) *availabilityHandler {
 	return &availabilityHandler{
 		asset:  o.Asset,
 		deploy: o.Deploy,
+		lister: o.SecondaryLister.APIRegistrationV1APIServiceLister(),
 	}
 }
 
 type availabilityHandler struct {
 	asset  *asset.Asset
 	deploy deploy.Interface
+	lister listersapiregistrationv1.APIServiceLister
 }
 
 func (a *availabilityHandler) Handle(context *ReconcileRequestContext, original *operatorv1.ClusterResourceOverride) (current *operatorv1.ClusterResourceOverride, result controllerreconciler.Result, handleErr error) {
@@ -37,7 +41,23 @@ func (a *availabilityHandler) Handle(context *ReconcileRequestContext, original
 		klog.V(2).Infof("key=%s resource=%s deployment availability error=%s", original.Name, a.deploy.Name(), err)
 	}
 
+	// with the Aggregated transport, the aggregated API server proxies the
+	// admission requests to the APIService, which may be unavailable even
+	// though the deployment is, e.g. when the discovery check fails.
+	var apiServiceErr error
+	if available && a.asset.WebhookTransport(original.Spec.GetWebhookTransport()) == operatorv1.WebhookTransportAggregated {
+		apiServiceErr = a.isAPIServiceAvailable(original)
+	}
+
+	// a failed admission probe tells the admission path is broken even though
+	// the deployment is available.
+	probe := original.Status.AdmissionProbe
+
 	switch {
+	case apiServiceErr != nil:
+		builder.WithError(apiServiceErr)
+	case available && probe != nil && probe.Result == operatorv1.AdmissionProbeFailed:
+		builder.WithError(condition.NewAvailableError(probe.Reason, errors.New(probe.Message)))
 	case available:
 		builder.WithAvailable(corev1.ConditionTrue, "")
 	case err == nil:
@@ -50,3 +70,27 @@ func (a *availabilityHandler) Handle(context *ReconcileRequestContext, original
 
 	return
 }
+
+// isAPIServiceAvailable returns an Available error if the APIService of the
+// admission webhook is missing or does not report the Available condition.
+func (a *availabilityHandler) isAPIServiceAvailable(original *operatorv1.ClusterResourceOverride) error {
+	name := a.asset.APIService().Name()
+	apiservice, err := a.lister.Get(name)
+	switch {
+	case k8serrors.IsNotFound(err):
+		return condition.NewAvailableError(operatorv1.AdmissionAPIServiceNotAvailable, fmt.Errorf("APIService %s not found", name))
+	case err != nil:
+		return condition.NewAvailableError(operatorv1.InternalError, err)
+	}
+
+	status, message := deploy.IsAPIServiceAvailable(apiservice)
+	klog.V(2).Infof("key=%s resource=%T/%s availability=%s %s", original.Name, apiservice, name, status, message)
+	if status == corev1.ConditionTrue {
+		return nil
+	}
+
+	if message == "" {
+		message = "the Available condition is not reported yet"
+	}
+	return condition.NewAvailableError(operatorv1.AdmissionAPIServiceNotAvailable, fmt.Errorf("APIService %s is not available - %s", name, message))
+}
@@ -0,0 +1,356 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	"github.com/openshift/library-go/pkg/crypto"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	listerscorev1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+	controllerreconciler "sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/clusterresourceoverride/internal/condition"
+)
+
+const (
+	// SelfManagedCALifetime is the lifetime of the CA generated with the
+	// SelfManaged provider.
+	SelfManagedCALifetime = 2 * 365 * 24 * time.Hour
+
+	// SelfManagedServingCertLifetime is the lifetime of the serving
+	// certificate issued with the SelfManaged provider.
+	SelfManagedServingCertLifetime = 365 * 24 * time.Hour
+
+	// CertManagerCertificateNameAnnotation is set by cert-manager on the
+	// Secret(s) it issues.
+	CertManagerCertificateNameAnnotation = "cert-manager.io/certificate-name"
+)
+
+var (
+	issuerGVR      = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "issuers"}
+	certificateGVR = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+)
+
+// NewCertificateHandler returns a handler that issues the serving certificate
+// of the admission webhook according to the certificate provider. With
+// ServiceCA the service-ca operator issues it, there is nothing to do. With
+// SelfManaged the operator generates a CA and the serving certificate, and
+// rotates them once less than a fifth of their lifetime remains. With
+// CertManager the operator creates the cert-manager Issuer and Certificate
+// objects. On a switch, the objects of the previous provider are removed.
+func NewCertificateHandler(o *Options) *certificateHandler {
+	return &certificateHandler{
+		clock:   clock.RealClock{},
+		client:  o.OperandClient.Kubernetes,
+		dynamic: o.OperandClient.RawDynamic,
+		lister:  o.SecondaryLister.CoreV1SecretLister(),
+		asset:   o.Asset,
+	}
+}
+
+type certificateHandler struct {
+	clock   clock.PassiveClock
+	client  kubernetes.Interface
+	dynamic dynamic.Interface
+	lister  listerscorev1.SecretLister
+	asset   *asset.Asset
+}
+
+func (c *certificateHandler) Handle(ctx *ReconcileRequestContext, original *operatorv1.ClusterResourceOverride) (current *operatorv1.ClusterResourceOverride, result controllerreconciler.Result, handleErr error) {
+	current = original
+
+	provider := original.Spec.GetCertificateProvider()
+	err := c.removeOtherProviders(ctx, original, provider)
+	if err == nil {
+		switch provider {
+		case operatorv1.CertificateProviderSelfManaged:
+			err = c.ensureSelfManaged(ctx, original)
+		case operatorv1.CertificateProviderCertManager:
+			err = c.ensureCertManager(ctx, original)
+		}
+	}
+
+	if err != nil {
+		handleErr = condition.NewInstallReadinessError(operatorv1.CertNotAvailable, err)
+	}
+	return
+}
+
+// removeOtherProviders removes what another certificate provider left behind.
+// The cert-manager Certificate(s) would keep re-issuing the serving Secret,
+// and service-ca does not replace a serving Secret it has not issued.
+func (c *certificateHandler) removeOtherProviders(ctx *ReconcileRequestContext, original *operatorv1.ClusterResourceOverride, provider operatorv1.CertificateProvider) error {
+	var secrets []*corev1.Secret
+	for _, name := range []string{c.asset.ServiceServingSecret().Name(), c.asset.CertificateAuthoritySecret().Name()} {
+		secret, err := c.lister.Secrets(ctx.WebhookNamespace()).Get(name)
+		switch {
+		case k8serrors.IsNotFound(err):
+			continue
+		case err != nil:
+			return fmt.Errorf("failed to get Secret %s - %s", name, err.Error())
+		}
+
+		secrets = append(secrets, secret)
+	}
+
+	certManager := false
+	for _, secret := range secrets {
+		if c.issuer(secret) == operatorv1.CertificateProviderCertManager {
+			certManager = true
+		}
+	}
+
+	// the Certificate(s) go first, cert-manager would issue the Secret(s) again.
+	if certManager && provider != operatorv1.CertificateProviderCertManager {
+		objects := c.asset.CertManager().New()
+		for i := len(objects) - 1; i >= 0; i-- {
+			object := objects[i]
+			resource := certificateGVR
+			if object.GetKind() == "Issuer" {
+				resource = issuerGVR
+			}
+
+			err := c.dynamic.Resource(resource).Namespace(object.GetNamespace()).Delete(context.TODO(), object.GetName(), metav1.DeleteOptions{})
+			switch {
+			case k8serrors.IsNotFound(err):
+				continue
+			case err != nil:
+				return fmt.Errorf("failed to delete %s %s - %s", object.GetKind(), object.GetName(), err.Error())
+			}
+
+			klog.V(2).Infof("key=%s resource=%s/%s deleted, not used with the %s certificate provider", original.Name, object.GetKind(), object.GetName(), provider)
+		}
+	}
+
+	for _, secret := range secrets {
+		if issuer := c.issuer(secret); issuer == "" || issuer == provider {
+			continue
+		}
+
+		err := c.client.CoreV1().Secrets(secret.Namespace).Delete(context.TODO(), secret.Name, metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete Secret %s - %s", secret.Name, err.Error())
+		}
+
+		klog.V(2).Infof("key=%s resource=%T/%s deleted, issued with the %s certificate provider", original.Name, secret, secret.Name, c.issuer(secret))
+	}
+
+	return nil
+}
+
+// issuer returns the certificate provider that issued the given Secret, empty
+// if not known, e.g. the self-signed serving Secret prior to 4.17.
+func (c *certificateHandler) issuer(secret *corev1.Secret) operatorv1.CertificateProvider {
+	switch {
+	case secret.Annotations[CertManagerCertificateNameAnnotation] != "":
+		return operatorv1.CertificateProviderCertManager
+	case secret.Labels[c.asset.Values().OwnerLabelKey] == c.asset.Values().OwnerLabelValue:
+		return operatorv1.CertificateProviderSelfManaged
+	case secret.Annotations[AlphaServiceNameAnnotation] != "":
+		return operatorv1.CertificateProviderServiceCA
+	}
+
+	return ""
+}
+
+// getSecret returns the given Secret, nil if there is none or if it has just
+// been removed by removeOtherProviders.
+func (c *certificateHandler) getSecret(ctx *ReconcileRequestContext, name string, provider operatorv1.CertificateProvider) (*corev1.Secret, error) {
+	secret, err := c.lister.Secrets(ctx.WebhookNamespace()).Get(name)
+	switch {
+	case k8serrors.IsNotFound(err):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to get Secret %s - %s", name, err.Error())
+	}
+
+	if issuer := c.issuer(secret); issuer != "" && issuer != provider {
+		return nil, nil
+	}
+	return secret, nil
+}
+
+func (c *certificateHandler) ensureCertManager(ctx *ReconcileRequestContext, original *operatorv1.ClusterResourceOverride) error {
+	for _, desired := range c.asset.CertManager().New() {
+		resource := certificateGVR
+		if desired.GetKind() == "Issuer" {
+			resource = issuerGVR
+		}
+
+		client := c.dynamic.Resource(resource).Namespace(desired.GetNamespace())
+		_, err := client.Get(context.TODO(), desired.GetName(), metav1.GetOptions{})
+		if err == nil {
+			continue
+		}
+		if !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get %s %s - %s", desired.GetKind(), desired.GetName(), err.Error())
+		}
+
+		ctx.OperandControllerSetter(c.asset).Set(desired, original)
+		if _, err := client.Create(context.TODO(), desired, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create %s %s, check that cert-manager is installed - %s", desired.GetKind(), desired.GetName(), err.Error())
+		}
+
+		klog.V(2).Infof("key=%s resource=%s/%s successfully created", original.Name, desired.GetKind(), desired.GetName())
+	}
+
+	return nil
+}
+
+func (c *certificateHandler) ensureSelfManaged(ctx *ReconcileRequestContext, original *operatorv1.ClusterResourceOverride) error {
+	ca, bundle, err := c.ensureCA(ctx, original)
+	if err != nil {
+		return err
+	}
+
+	return c.ensureServingCert(ctx, original, ca, bundle)
+}
+
+// ensureCA returns the CA signing the serving certificate, and the CA bundle
+// the serving certificate is verified with. The bundle keeps the previous CA
+// until it expires, so that the serving certificate it signed is still
+// trusted until it is re-issued.
+func (c *certificateHandler) ensureCA(ctx *ReconcileRequestContext, original *operatorv1.ClusterResourceOverride) (*crypto.CA, []byte, error) {
+	name := c.asset.CertificateAuthoritySecret().Name()
+	secret, err := c.getSecret(ctx, name, operatorv1.CertificateProviderSelfManaged)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var previous []byte
+	if secret != nil {
+		ca, err := crypto.GetCAFromBytes(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+		if err == nil {
+			cert := ca.Config.Certs[0]
+			if rotation := c.rotationTime(cert); c.clock.Now().Before(rotation) {
+				ctx.RequeueAfter(rotation.Sub(c.clock.Now()))
+				return ca, secret.Data[asset.CABundleKey], nil
+			}
+
+			if c.clock.Now().Before(cert.NotAfter) {
+				previous = secret.Data[corev1.TLSCertKey]
+			}
+		}
+
+		klog.V(2).Infof("key=%s resource=%T/%s CA is invalid or due for rotation, generating a new one", original.Name, secret, name)
+	}
+
+	config, err := crypto.MakeSelfSignedCAConfigForDuration(fmt.Sprintf("%s@%d", name, c.clock.Now().Unix()), SelfManagedCALifetime)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate the CA - %s", err.Error())
+	}
+
+	certPEM, keyPEM, err := config.GetPEMBytes()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode the CA - %s", err.Error())
+	}
+	bundle := append(append([]byte{}, certPEM...), previous...)
+
+	desired := c.asset.CertificateAuthoritySecret().New()
+	desired.Data = map[string][]byte{
+		corev1.TLSCertKey:       certPEM,
+		corev1.TLSPrivateKeyKey: keyPEM,
+		asset.CABundleKey:       bundle,
+	}
+	if err := c.writeSecret(ctx, original, secret, desired); err != nil {
+		return nil, nil, err
+	}
+	ctx.RequeueAfter(c.rotationTime(config.Certs[0]).Sub(c.clock.Now()))
+
+	return &crypto.CA{Config: config, SerialGenerator: &crypto.RandomSerialGenerator{}}, bundle, nil
+}
+
+// ensureServingCert issues the serving certificate, unless the current one is
+// signed by the given CA, carries the given CA bundle and is not due for
+// rotation.
+func (c *certificateHandler) ensureServingCert(ctx *ReconcileRequestContext, original *operatorv1.ClusterResourceOverride, ca *crypto.CA, bundle []byte) error {
+	name := c.asset.ServiceServingSecret().Name()
+	secret, err := c.getSecret(ctx, name, operatorv1.CertificateProviderSelfManaged)
+	if err != nil {
+		return err
+	}
+
+	if secret != nil && bytes.Equal(secret.Data[asset.CABundleKey], bundle) {
+		if cert, err := parseServingCert(secret); err == nil && cert.CheckSignatureFrom(ca.Config.Certs[0]) == nil {
+			if rotation := c.rotationTime(cert); c.clock.Now().Before(rotation) {
+				ctx.RequeueAfter(rotation.Sub(c.clock.Now()))
+				return nil
+			}
+		}
+	}
+
+	config, err := ca.MakeServerCertForDuration(sets.New(c.asset.ServingCertDNSNames()...), SelfManagedServingCertLifetime)
+	if err != nil {
+		return fmt.Errorf("failed to issue the serving certificate - %s", err.Error())
+	}
+
+	certPEM, keyPEM, err := config.GetPEMBytes()
+	if err != nil {
+		return fmt.Errorf("failed to encode the serving certificate - %s", err.Error())
+	}
+
+	desired := c.asset.ServiceServingSecret().New()
+	desired.Labels = map[string]string{
+		c.asset.Values().OwnerLabelKey: c.asset.Values().OwnerLabelValue,
+	}
+	desired.Data = map[string][]byte{
+		corev1.TLSCertKey:       certPEM,
+		corev1.TLSPrivateKeyKey: keyPEM,
+		asset.CABundleKey:       bundle,
+	}
+
+	klog.V(2).Infof("key=%s resource=%T/%s issuing the serving certificate", original.Name, desired, name)
+	if err := c.writeSecret(ctx, original, secret, desired); err != nil {
+		return err
+	}
+	ctx.RequeueAfter(c.rotationTime(config.Certs[0]).Sub(c.clock.Now()))
+
+	return nil
+}
+
+// rotationTime returns when the given certificate is rotated, once less than
+// a fifth of its lifetime remains.
+func (c *certificateHandler) rotationTime(cert *x509.Certificate) time.Time {
+	return cert.NotAfter.Add(-cert.NotAfter.Sub(cert.NotBefore) / 5)
+}
+
+// writeSecret creates the desired Secret, or replaces the data of the
+// existing one.
+func (c *certificateHandler) writeSecret(ctx *ReconcileRequestContext, original *operatorv1.ClusterResourceOverride, existing, desired *corev1.Secret) error {
+	if existing == nil {
+		ctx.OperandControllerSetter(c.asset).Set(desired, original)
+		if _, err := c.client.CoreV1().Secrets(desired.Namespace).Create(context.TODO(), desired, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create Secret %s - %s", desired.Name, err.Error())
+		}
+
+		return nil
+	}
+
+	secret := existing.DeepCopy()
+	secret.Data = desired.Data
+	if secret.Labels == nil {
+		secret.Labels = map[string]string{}
+	}
+	for key, value := range desired.Labels {
+		secret.Labels[key] = value
+	}
+
+	if _, err := c.client.CoreV1().Secrets(secret.Namespace).Update(context.TODO(), secret, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update Secret %s - %s", secret.Name, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	listerscorev1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+	operatorruntime "github.com/openshift/cluster-resource-override-admission-operator/pkg/runtime"
+)
+
+type certificateFixture struct {
+	handler *certificateHandler
+	client  *kubefake.Clientset
+	indexer cache.Indexer
+	ctx     operatorruntime.OperandContext
+}
+
+func newCertificateFixture(objects ...runtime.Object) *certificateFixture {
+	ctx := operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0")
+	f := &certificateFixture{
+		client:  kubefake.NewSimpleClientset(objects...),
+		indexer: cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}),
+		ctx:     ctx,
+	}
+	for _, object := range objects {
+		f.indexer.Add(object)
+	}
+
+	f.handler = &certificateHandler{
+		clock:  clock.RealClock{},
+		client: f.client,
+		dynamic: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+			issuerGVR:      "IssuerList",
+			certificateGVR: "CertificateList",
+		}),
+		lister: listerscorev1.NewSecretLister(f.indexer),
+		asset:  asset.New(ctx),
+	}
+	return f
+}
+
+// handle invokes the handler and syncs the lister with the client.
+func (f *certificateFixture) handle(t *testing.T, cro *operatorv1.ClusterResourceOverride) *ReconcileRequestContext {
+	context := NewReconcileRequestContext(f.ctx)
+	_, _, err := f.handler.Handle(context, cro)
+	require.NoError(t, err)
+
+	for _, name := range []string{f.handler.asset.CertificateAuthoritySecret().Name(), f.handler.asset.ServiceServingSecret().Name()} {
+		if secret, err := f.secret(name); err == nil {
+			require.NoError(t, f.indexer.Update(secret))
+		} else if cached, ok, _ := f.indexer.GetByKey("test-ns/" + name); ok {
+			require.NoError(t, f.indexer.Delete(cached))
+		}
+	}
+	return context
+}
+
+// issue adds the given Secret as if issued by cert-manager.
+func (f *certificateFixture) issue(t *testing.T, secret *corev1.Secret) {
+	secret.Annotations = map[string]string{CertManagerCertificateNameAnnotation: secret.Name}
+	require.NoError(t, f.client.Tracker().Add(secret))
+	require.NoError(t, f.indexer.Add(secret))
+}
+
+// secret reads the given Secret from the tracker, the read is not recorded as
+// an action of the client.
+func (f *certificateFixture) secret(name string) (*corev1.Secret, error) {
+	object, err := f.client.Tracker().Get(corev1.SchemeGroupVersion.WithResource("secrets"), "test-ns", name)
+	if err != nil {
+		return nil, err
+	}
+	return object.(*corev1.Secret), nil
+}
+
+func TestCertificateHandlerSelfManaged(t *testing.T) {
+	f := newCertificateFixture()
+	cro := minimalCRO()
+	cro.Spec.CertificateProvider = operatorv1.CertificateProviderSelfManaged
+
+	reconcileContext := f.handle(t, cro)
+	require.NotZero(t, reconcileContext.GetRequeueAfter())
+
+	serving, err := f.secret(f.handler.asset.ServiceServingSecret().Name())
+	require.NoError(t, err)
+	require.Equal(t, f.handler.asset.Values().OwnerLabelValue, serving.Labels[f.handler.asset.Values().OwnerLabelKey])
+
+	cert, err := parseServingCert(serving)
+	require.NoError(t, err)
+	require.Equal(t, f.handler.asset.ServingCertDNSNames(), cert.DNSNames)
+
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(serving.Data[asset.CABundleKey]))
+	_, err = cert.Verify(x509.VerifyOptions{Roots: roots, DNSName: "clusterresourceoverride.test-ns.svc"})
+	require.NoError(t, err)
+
+	// in sync, nothing is re-issued.
+	actions := len(f.client.Actions())
+	f.handle(t, cro)
+	require.Len(t, f.client.Actions(), actions)
+}
+
+func TestCertificateHandlerSelfManagedReissues(t *testing.T) {
+	ctx := operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0")
+	a := asset.New(ctx)
+
+	// a serving certificate issued by another CA, such as service-ca, close to
+	// its expiry.
+	now := time.Now()
+	previous := newServingSecret(t, a, now.Add(-364*24*time.Hour), now.Add(24*time.Hour))
+	f := newCertificateFixture(previous)
+
+	cro := minimalCRO()
+	cro.Spec.CertificateProvider = operatorv1.CertificateProviderSelfManaged
+	f.handle(t, cro)
+
+	serving, err := f.secret(a.ServiceServingSecret().Name())
+	require.NoError(t, err)
+	require.NotEqual(t, previous.Data[corev1.TLSCertKey], serving.Data[corev1.TLSCertKey])
+
+	cert, err := parseServingCert(serving)
+	require.NoError(t, err)
+	require.True(t, cert.NotAfter.After(now.Add(SelfManagedServingCertLifetime-time.Hour)))
+}
+
+func TestCertificateHandlerCertManager(t *testing.T) {
+	f := newCertificateFixture()
+	cro := minimalCRO()
+	cro.Spec.CertificateProvider = operatorv1.CertificateProviderCertManager
+
+	f.handle(t, cro)
+
+	client := f.handler.dynamic.(*dynamicfake.FakeDynamicClient)
+	certManager := f.handler.asset.CertManager()
+	require.True(t, exists(t, client, issuerGVR, "test-ns", certManager.SelfSignedIssuerName()))
+	require.True(t, exists(t, client, issuerGVR, "test-ns", certManager.CAIssuerName()))
+	require.True(t, exists(t, client, certificateGVR, "test-ns", certManager.CACertificateName()))
+	require.True(t, exists(t, client, certificateGVR, "test-ns", certManager.ServingCertificateName()))
+
+	// cert-manager issues the serving certificate, the operator does not.
+	_, err := f.secret(f.handler.asset.ServiceServingSecret().Name())
+	require.Error(t, err)
+}
+
+func TestCertificateHandlerServiceCA(t *testing.T) {
+	f := newCertificateFixture()
+
+	f.handle(t, minimalCRO())
+	require.Empty(t, f.client.Actions())
+}
+
+func TestCertificateHandlerSwitchFromCertManager(t *testing.T) {
+	f := newCertificateFixture()
+	cro := minimalCRO()
+	cro.Spec.CertificateProvider = operatorv1.CertificateProviderCertManager
+	f.handle(t, cro)
+
+	now := time.Now()
+	f.issue(t, newServingSecret(t, f.handler.asset, now, now.Add(SelfManagedServingCertLifetime)))
+	ca := f.handler.asset.CertificateAuthoritySecret().New()
+	f.issue(t, ca)
+
+	cro.Spec.CertificateProvider = operatorv1.CertificateProviderSelfManaged
+	f.handle(t, cro)
+
+	// the cert-manager objects would issue the serving certificate again.
+	client := f.handler.dynamic.(*dynamicfake.FakeDynamicClient)
+	certManager := f.handler.asset.CertManager()
+	require.False(t, exists(t, client, issuerGVR, "test-ns", certManager.SelfSignedIssuerName()))
+	require.False(t, exists(t, client, issuerGVR, "test-ns", certManager.CAIssuerName()))
+	require.False(t, exists(t, client, certificateGVR, "test-ns", certManager.CACertificateName()))
+	require.False(t, exists(t, client, certificateGVR, "test-ns", certManager.ServingCertificateName()))
+
+	// the operator issues the CA and the serving certificate in their place.
+	for _, name := range []string{ca.Name, f.handler.asset.ServiceServingSecret().Name()} {
+		secret, err := f.secret(name)
+		require.NoError(t, err)
+		require.NotContains(t, secret.Annotations, CertManagerCertificateNameAnnotation)
+		require.Equal(t, operatorv1.CertificateProviderSelfManaged, f.handler.issuer(secret))
+	}
+}
+
+func TestCertificateHandlerSwitchToServiceCA(t *testing.T) {
+	f := newCertificateFixture()
+	cro := minimalCRO()
+	cro.Spec.CertificateProvider = operatorv1.CertificateProviderSelfManaged
+	f.handle(t, cro)
+
+	cro.Spec.CertificateProvider = operatorv1.CertificateProviderServiceCA
+	f.handle(t, cro)
+
+	// service-ca does not replace a serving Secret it has not issued.
+	_, err := f.secret(f.handler.asset.ServiceServingSecret().Name())
+	require.True(t, k8serrors.IsNotFound(err))
+	_, err = f.secret(f.handler.asset.CertificateAuthoritySecret().Name())
+	require.True(t, k8serrors.IsNotFound(err))
+
+	// the serving Secret issued by service-ca is left alone.
+	issued := f.handler.asset.ServiceServingSecret().New()
+	issued.Annotations = map[string]string{AlphaServiceNameAnnotation: f.handler.asset.Service().Name()}
+	require.NoError(t, f.client.Tracker().Add(issued))
+	require.NoError(t, f.indexer.Add(issued))
+
+	actions := len(f.client.Actions())
+	f.handle(t, cro)
+	require.Len(t, f.client.Actions(), actions)
+}
@@ -18,8 +18,8 @@ import (
 
 func NewConfigurationHandler(o *Options) *configurationHandler {
 	return &configurationHandler{
-		client:  o.Client.Kubernetes,
-		ensurer: ensurer.NewConfigMapEnsurer(o.Client.Dynamic),
+		client:  o.OperandClient.Kubernetes,
+		ensurer: ensurer.NewConfigMapEnsurer(o.OperandClient.Dynamic),
 		lister:  o.SecondaryLister,
 		asset:   o.Asset,
 	}
@@ -106,7 +106,7 @@ func (c *configurationHandler) NewConfiguration(context *ReconcileRequestContext
 	configuration = c.asset.Configuration().New()
 
 	// Set owner reference.
-	context.ControllerSetter().Set(configuration, override)
+	context.OperandControllerSetter(c.asset).Set(configuration, override)
 
 	if len(configuration.Data) == 0 {
 		configuration.Data = map[string]string{}
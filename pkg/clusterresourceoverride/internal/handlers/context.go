@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"time"
+
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/deploy"
 	operatorv1listers "github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/listers/operator/v1"
@@ -23,13 +25,49 @@ type Options struct {
 	Asset           *asset.Asset
 	Deploy          deploy.Interface
 	DynamicClient   dynamic.Interface
-	IsStandalone    bool
+	Platform        PlatformDetector
+
+	// OperandClient is the client of the cluster the admission webhook runs
+	// in, the management cluster of a hosted cluster. It is Client otherwise.
+	OperandClient *operatorruntime.Client
+
+	// IsStandalone schedules the admission webhook on control-plane nodes
+	// when no platform is detected, e.g. when rendering the manifests.
+	IsStandalone bool
 }
 
 type ReconcileRequestContext struct {
 	operatorruntime.OperandContext
+
+	requeueAfter time.Duration
+}
+
+// RequeueAfter asks for the request to be reconciled again within the given
+// duration, even if a later handler stops the chain on an error. The shortest
+// duration asked for wins.
+func (r *ReconcileRequestContext) RequeueAfter(after time.Duration) {
+	if r.requeueAfter == 0 || after < r.requeueAfter {
+		r.requeueAfter = after
+	}
+}
+
+// GetRequeueAfter returns the duration asked for with RequeueAfter, zero if none.
+func (r *ReconcileRequestContext) GetRequeueAfter() time.Duration {
+	return r.requeueAfter
 }
 
 func (r *ReconcileRequestContext) ControllerSetter() operatorruntime.SetControllerFunc {
 	return operatorruntime.SetController
 }
+
+// OperandControllerSetter returns the SetControllerFunc for the object(s) of
+// the admission webhook. In a management cluster they are not owned by the
+// ClusterResourceOverride of the hosted cluster, the owner annotation enqueues
+// it instead.
+func (r *ReconcileRequestContext) OperandControllerSetter(a *asset.Asset) operatorruntime.SetControllerFunc {
+	if a.OnManagementCluster() {
+		return operatorruntime.SetOwnerAnnotation(a.Values().OwnerAnnotationKey)
+	}
+
+	return operatorruntime.SetController
+}
@@ -5,13 +5,17 @@ import (
 	"fmt"
 	"strings"
 
+	configv1 "github.com/openshift/api/config/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata/metadatalister"
 	"k8s.io/klog/v2"
 	controllerreconciler "sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -27,27 +31,37 @@ import (
 )
 
 func NewDeploymentHandler(o *Options) *deploymentHandler {
+	// the nodes of a management cluster are not watched.
+	var nodes metadatalister.Lister
+	if o.SecondaryLister != nil && !o.Asset.OnManagementCluster() {
+		nodes = o.SecondaryLister.NodeMetadataLister()
+	}
+
 	return &deploymentHandler{
-		client:       o.Client.Kubernetes,
-		dynamic:      o.Client.Dynamic,
-		deployment:   ensurer.NewDeploymentEnsurer(o.Client.Dynamic),
-		asset:        o.Asset,
-		lister:       o.SecondaryLister,
-		deploy:       o.Deploy,
-		dynClient:    o.DynamicClient,
-		isStandalone: o.IsStandalone,
+		client:        o.Client.Kubernetes,
+		operandClient: o.OperandClient.Kubernetes,
+		dynamic:       o.OperandClient.Dynamic,
+		deployment:    ensurer.NewDeploymentEnsurer(o.OperandClient.Dynamic),
+		asset:         o.Asset,
+		lister:        o.SecondaryLister,
+		deploy:        o.Deploy,
+		dynClient:     o.DynamicClient,
+		isStandalone:  o.IsStandalone,
+		nodes:         nodes,
 	}
 }
 
 type deploymentHandler struct {
-	client       kubernetes.Interface
-	deployment   *ensurer.DeploymentEnsurer
-	dynamic      dynamicclient.Ensurer
-	lister       *secondarywatch.Lister
-	asset        *asset.Asset
-	deploy       deploy.Interface
-	dynClient    dynamic.Interface
-	isStandalone bool
+	client        kubernetes.Interface
+	operandClient kubernetes.Interface
+	deployment    *ensurer.DeploymentEnsurer
+	dynamic       dynamicclient.Ensurer
+	lister        *secondarywatch.Lister
+	asset         *asset.Asset
+	deploy        deploy.Interface
+	dynClient     dynamic.Interface
+	isStandalone  bool
+	nodes         metadatalister.Lister
 }
 
 func (c *deploymentHandler) Handle(ctx *ReconcileRequestContext, original *operatorv1.ClusterResourceOverride) (current *operatorv1.ClusterResourceOverride, result controllerreconciler.Result, handleErr error) {
@@ -56,7 +70,7 @@ func (c *deploymentHandler) Handle(ctx *ReconcileRequestContext, original *opera
 	// Remove the DaemonSet if it exists; used prior to v4.17.0
 	dsName := c.asset.DaemonSet().Name()
 	dsNamespace := c.asset.Values().Namespace
-	if deleteErr := c.client.AppsV1().DaemonSets(dsNamespace).Delete(context.TODO(), dsName, metav1.DeleteOptions{}); deleteErr != nil && !k8serrors.IsNotFound(deleteErr) {
+	if deleteErr := c.operandClient.AppsV1().DaemonSets(dsNamespace).Delete(context.TODO(), dsName, metav1.DeleteOptions{}); deleteErr != nil && !k8serrors.IsNotFound(deleteErr) {
 		handleErr = fmt.Errorf("failed to delete DaemonSet - %s", deleteErr.Error())
 		return
 	} else if deleteErr == nil {
@@ -64,13 +78,12 @@ func (c *deploymentHandler) Handle(ctx *ReconcileRequestContext, original *opera
 		return
 	}
 
-	tlsArgs, err := tlsprofile.Fetch(context.TODO(), c.dynClient)
-	if err != nil {
-		klog.V(2).Infof("key=%s failed to fetch cluster TLS profile, proceeding with operand defaults: %s", original.Name, err)
-		tlsArgs = tlsprofile.Args{}
-	}
+	tlsArgs, tlsSource := c.tlsProfile(original)
 
-	ensure := false
+	// ensure re-creates the admission webhook, the MutatingWebhookConfiguration
+	// is removed until the new pods are rolled out. update rolls the pods out
+	// in place, the admission webhook keeps serving meanwhile.
+	ensure, update := false, false
 
 	object, accessor, getErr := c.deploy.Get()
 	if getErr != nil && !k8serrors.IsNotFound(getErr) {
@@ -88,6 +101,12 @@ func (c *deploymentHandler) Handle(ctx *ReconcileRequestContext, original *opera
 	case accessor.GetAnnotations()[values.TLSProfileHashAnnotationKey] != tlsArgs.Hash():
 		klog.V(2).Infof("key=%s resource=%T/%s TLS profile hash mismatch", original.Name, object, accessor.GetName())
 		ensure = true
+	case accessor.GetAnnotations()[values.WebhookTransportAnnotationKey] != string(c.asset.WebhookTransport(original.Spec.GetWebhookTransport())):
+		klog.V(2).Infof("key=%s resource=%T/%s webhook transport mismatch", original.Name, object, accessor.GetName())
+		ensure = true
+	case accessor.GetAnnotations()[values.PlatformAnnotationKey] != c.platform(original):
+		klog.V(2).Infof("key=%s resource=%T/%s platform mismatch", original.Name, object, accessor.GetName())
+		ensure = true
 	case values.OperandImage != current.Status.Image:
 		klog.V(2).Infof("operand image mismatch: current: %s original: %s", current.Status.Image, values.OperandImage)
 		ensure = true
@@ -96,13 +115,45 @@ func (c *deploymentHandler) Handle(ctx *ReconcileRequestContext, original *opera
 		ensure = true
 	}
 
-	if ensure {
+	// checked once nothing asks for the admission webhook to be re-created,
+	// the annotations are all brought in sync by either.
+	if !ensure {
+		switch {
+		case accessor.GetAnnotations()[values.ServingCertHashAnnotationKey] != current.Status.Hash.ServingCert:
+			klog.V(2).Infof("key=%s resource=%T/%s serving certificate hash mismatch", original.Name, object, accessor.GetName())
+			update = true
+		case accessor.GetAnnotations()[values.TopologyAnnotationKey] != c.topology(original).String():
+			// the replicas and the anti-affinity follow the nodes.
+			klog.V(2).Infof("key=%s resource=%T/%s topology mismatch", original.Name, object, accessor.GetName())
+			update = true
+		}
+	}
+
+	switch {
+	case ensure:
 		object, accessor, handleErr = c.Ensure(ctx, original, tlsArgs)
 		if handleErr != nil {
 			return
 		}
 
 		klog.V(2).Infof("key=%s resource=%T/%s successfully ensured", original.Name, object, accessor.GetName())
+	case update:
+		object, accessor, handleErr = c.Update(ctx, original, tlsArgs)
+		if handleErr != nil {
+			return
+		}
+
+		klog.V(2).Infof("key=%s resource=%T/%s successfully updated", original.Name, object, accessor.GetName())
+	}
+
+	tlsStatus := &operatorv1.TLSProfileStatus{
+		Source:        tlsSource,
+		MinTLSVersion: tlsArgs.MinVersion,
+		Ciphers:       tlsArgs.CipherSuites,
+	}
+	if !equality.Semantic.DeepEqual(current.Status.TLSProfile, tlsStatus) {
+		klog.V(2).Infof("key=%s TLS profile source=%s min-version=%s", original.Name, tlsSource, tlsArgs.MinVersion)
+		current.Status.TLSProfile = tlsStatus
 	}
 
 	if ref := current.Status.Resources.DeploymentRef; ref != nil && ref.ResourceVersion == accessor.GetResourceVersion() {
@@ -133,6 +184,12 @@ func (c *deploymentHandler) Ensure(ctx *ReconcileRequestContext, cro *operatorv1
 		return
 	}
 
+	return c.Update(ctx, cro, tlsArgs)
+}
+
+// Update applies the Deployment in place, the MutatingWebhookConfiguration is
+// left as is and the pods are rolled out without any admission downtime.
+func (c *deploymentHandler) Update(ctx *ReconcileRequestContext, cro *operatorv1.ClusterResourceOverride, tlsArgs tlsprofile.Args) (current runtime.Object, accessor metav1.Object, err error) {
 	parent := c.ApplyToDeploymentObject(ctx, cro, tlsArgs)
 	child := c.ApplyToToPodTemplate(ctx, cro, tlsArgs)
 	current, accessor, err = c.deploy.Ensure(parent, child)
@@ -153,14 +210,22 @@ func (c *deploymentHandler) ApplyToDeploymentObject(context *ReconcileRequestCon
 		}
 
 		deployment.GetAnnotations()[values.ConfigurationHashAnnotationKey] = cro.Status.Hash.Configuration
+		deployment.GetAnnotations()[values.ServingCertHashAnnotationKey] = cro.Status.Hash.ServingCert
 		deployment.GetAnnotations()[values.TLSProfileHashAnnotationKey] = tlsArgs.Hash()
 
-		// Override replica count, if specified in the CRD
-		if cro.Spec.DeploymentOverrides.Replicas != nil {
-			deployment.Spec.Replicas = cro.Spec.DeploymentOverrides.Replicas
-		}
+		// the MutatingWebhookConfiguration is re-created with the new transport
+		// once the deployment is rolled out.
+		deployment.GetAnnotations()[values.WebhookTransportAnnotationKey] = string(c.asset.WebhookTransport(cro.Spec.GetWebhookTransport()))
 
-		context.ControllerSetter().Set(object, cro)
+		// the scheduling of the admission webhook follows the platform.
+		deployment.GetAnnotations()[values.PlatformAnnotationKey] = c.platform(cro)
+
+		// the replica count follows the topology, unless specified in the CR.
+		topology := c.topology(cro)
+		deployment.GetAnnotations()[values.TopologyAnnotationKey] = topology.String()
+		deployment.Spec.Replicas = &topology.Replicas
+
+		context.OperandControllerSetter(c.asset).Set(object, cro)
 	}
 }
 
@@ -181,43 +246,35 @@ func (c *deploymentHandler) ApplyToToPodTemplate(context *ReconcileRequestContex
 		podTemplateSpec.GetAnnotations()[values.OwnerAnnotationKey] = cro.Name
 		podTemplateSpec.GetAnnotations()[values.ConfigurationHashAnnotationKey] = cro.Status.Hash.Configuration
 
+		// the operand is not relied upon to reload a rotated serving certificate,
+		// a rotation rolls the pods out.
+		podTemplateSpec.GetAnnotations()[values.ServingCertHashAnnotationKey] = cro.Status.Hash.ServingCert
+
 		// Replaces nodeSelector, if specified in the CR
-		if len(cro.Spec.DeploymentOverrides.NodeSelector) > 0 {
-			podTemplateSpec.Spec.NodeSelector = cro.Spec.DeploymentOverrides.NodeSelector
-		} else if c.isStandalone {
-			// On standalone clusters, schedule on control-plane nodes
-			podTemplateSpec.Spec.NodeSelector = map[string]string{
-				"node-role.kubernetes.io/control-plane": "",
-			}
+		if nodeSelector := c.nodeSelector(cro); nodeSelector != nil {
+			podTemplateSpec.Spec.NodeSelector = nodeSelector
 		}
 
 		// Replaces tolerations, if specified in the CR
-		if len(cro.Spec.DeploymentOverrides.Tolerations) > 0 {
-			podTemplateSpec.Spec.Tolerations = cro.Spec.DeploymentOverrides.Tolerations
-		} else if c.isStandalone {
-			// On standalone clusters, tolerate both master (legacy) and control-plane taints
-			podTemplateSpec.Spec.Tolerations = append(podTemplateSpec.Spec.Tolerations,
-				corev1.Toleration{
-					Key:      "node-role.kubernetes.io/master",
-					Operator: corev1.TolerationOpExists,
-					Effect:   corev1.TaintEffectNoSchedule,
-				},
-				corev1.Toleration{
-					Key:      "node-role.kubernetes.io/control-plane",
-					Operator: corev1.TolerationOpExists,
-					Effect:   corev1.TaintEffectNoSchedule,
-				},
-			)
-		}
+		podTemplateSpec.Spec.Tolerations = c.tolerations(cro, podTemplateSpec.Spec.Tolerations)
+
+		// a second replica that cannot be placed on another node must not stay
+		// pending, the anti-affinity is only preferred then.
+		podTemplateSpec.Spec.Affinity = c.asset.Deployment().Affinity(c.topology(cro).RequiredAntiAffinity)
 
 		if len(podTemplateSpec.Spec.Containers) > 0 {
 			var filtered []string
 			container := &podTemplateSpec.Spec.Containers[0]
 			for _, arg := range container.Args {
-				if !strings.HasPrefix(arg, "--tls-min-version=") && !strings.HasPrefix(arg, "--tls-cipher-suites=") {
+				// the TLS and webhook transport arguments are set below.
+				switch {
+				case strings.HasPrefix(arg, "--tls-min-version="), strings.HasPrefix(arg, "--tls-cipher-suites="):
+				case arg == asset.AuthenticationSkipLookupArg, strings.HasPrefix(arg, asset.AuthorizationAlwaysAllowPathsArg+"="):
+				default:
 					filtered = append(filtered, arg)
 				}
 			}
+			filtered = append(filtered, c.asset.WebhookTransportArgs(c.asset.WebhookTransport(cro.Spec.GetWebhookTransport()))...)
 			if tlsArgs.MinVersion != "" {
 				filtered = append(filtered, "--tls-min-version="+tlsArgs.MinVersion)
 			}
@@ -229,10 +286,248 @@ func (c *deploymentHandler) ApplyToToPodTemplate(context *ReconcileRequestContex
 	}
 }
 
+// tlsProfile returns the TLS arguments of the admission webhook and where they
+// come from. The tlsSecurityProfile of the ClusterResourceOverride takes
+// precedence over the cluster TLS profile, there is none without
+// config.openshift.io.
+func (c *deploymentHandler) tlsProfile(cro *operatorv1.ClusterResourceOverride) (tlsprofile.Args, operatorv1.TLSProfileSource) {
+	if profile := cro.Spec.TLSSecurityProfile; profile != nil {
+		// the profile has been validated.
+		args, _, err := tlsprofile.FromProfile(profile)
+		if err != nil {
+			klog.V(2).Infof("key=%s invalid tlsSecurityProfile, proceeding with operand defaults: %s", cro.Name, err)
+			return tlsprofile.Args{}, operatorv1.TLSProfileSourceDefault
+		}
+		return args, operatorv1.TLSProfileSourceClusterResourceOverride
+	}
+
+	if platform := cro.Status.Platform; platform != nil && !platform.HasOpenShiftConfig() {
+		return tlsprofile.Args{}, operatorv1.TLSProfileSourceDefault
+	}
+
+	args, err := tlsprofile.Fetch(context.TODO(), c.dynClient)
+	if err != nil {
+		klog.V(2).Infof("key=%s failed to fetch cluster TLS profile, proceeding with operand defaults: %s", cro.Name, err)
+		return tlsprofile.Args{}, operatorv1.TLSProfileSourceDefault
+	}
+
+	// the cluster TLS profile is not honored.
+	if args.Hash() == "" {
+		return args, operatorv1.TLSProfileSourceDefault
+	}
+
+	return args, operatorv1.TLSProfileSourceAPIServer
+}
+
+// standalone returns true if the control plane of the detected platform runs
+// in the cluster. Without a detected platform, e.g. when rendering the
+// manifests, the IsStandalone option decides.
+func (c *deploymentHandler) standalone(cro *operatorv1.ClusterResourceOverride) bool {
+	if platform := cro.Status.Platform; platform != nil {
+		return platform.IsStandalone()
+	}
+
+	return c.isStandalone
+}
+
+// nodeSelector returns the node selector of the admission webhook pods, nil
+// if the one of the pod template is kept.
+func (c *deploymentHandler) nodeSelector(cro *operatorv1.ClusterResourceOverride) map[string]string {
+	if len(cro.Spec.DeploymentOverrides.NodeSelector) > 0 {
+		return cro.Spec.DeploymentOverrides.NodeSelector
+	}
+
+	if c.standalone(cro) {
+		// On standalone clusters, schedule on control-plane nodes
+		return map[string]string{
+			"node-role.kubernetes.io/control-plane": "",
+		}
+	}
+
+	return nil
+}
+
+// tolerations returns the tolerations of the admission webhook pods given
+// the tolerations of the pod template.
+func (c *deploymentHandler) tolerations(cro *operatorv1.ClusterResourceOverride, template []corev1.Toleration) []corev1.Toleration {
+	if len(cro.Spec.DeploymentOverrides.Tolerations) > 0 {
+		return cro.Spec.DeploymentOverrides.Tolerations
+	}
+
+	if c.standalone(cro) {
+		// On standalone clusters, tolerate both master (legacy) and control-plane taints
+		return append(template,
+			corev1.Toleration{
+				Key:      "node-role.kubernetes.io/master",
+				Operator: corev1.TolerationOpExists,
+				Effect:   corev1.TaintEffectNoSchedule,
+			},
+			corev1.Toleration{
+				Key:      "node-role.kubernetes.io/control-plane",
+				Operator: corev1.TolerationOpExists,
+				Effect:   corev1.TaintEffectNoSchedule,
+			},
+		)
+	}
+
+	return template
+}
+
+// topology is the replica count and pod anti-affinity of the admission
+// webhook for the cluster it runs in.
+type topology struct {
+	Replicas             int32
+	RequiredAntiAffinity bool
+}
+
+func (t topology) String() string {
+	if t.RequiredAntiAffinity {
+		return fmt.Sprintf("%d/required", t.Replicas)
+	}
+	return fmt.Sprintf("%d/preferred", t.Replicas)
+}
+
+// topology derives the replica count and the anti-affinity from the detected
+// platform and the nodes the admission webhook may be scheduled on. A single
+// replica topology runs one replica, otherwise there are as many replicas as
+// schedulable nodes up to asset.DefaultReplicas. The anti-affinity is required
+// only if every replica can be placed on a node of its own. An explicit
+// replica count in the CR always wins. Without a node lister, e.g. when
+// rendering the manifests, the schedulable nodes are not known.
+func (c *deploymentHandler) topology(cro *operatorv1.ClusterResourceOverride) topology {
+	// the topology of a hosted cluster does not tell that of its management
+	// cluster.
+	platform := cro.Status.Platform
+	single := platform != nil && !c.asset.OnManagementCluster() && (platform.Type == operatorv1.PlatformSingleReplica ||
+		platform.InfrastructureTopology == string(configv1.SingleReplicaTopologyMode))
+
+	nodes := -1
+	if c.nodes != nil {
+		count, err := c.schedulableNodes(cro)
+		if err != nil {
+			klog.V(2).Infof("key=%s failed to count the schedulable nodes, proceeding with the defaults: %s", cro.Name, err)
+		} else if count > 0 {
+			nodes = count
+		}
+	}
+
+	replicas := asset.DefaultReplicas
+	switch {
+	case single:
+		replicas = 1
+	case nodes > 0 && int32(nodes) < replicas:
+		replicas = int32(nodes)
+	}
+	if override := cro.Spec.DeploymentOverrides.Replicas; override != nil {
+		replicas = *override
+	}
+
+	required := true
+	switch {
+	case single:
+		required = false
+	case nodes > 0:
+		required = nodes > 1 && int(replicas) <= nodes
+	}
+
+	return topology{Replicas: replicas, RequiredAntiAffinity: required}
+}
+
+// schedulableNodes returns the number of nodes the admission webhook pods may
+// be scheduled on. A node is schedulable if it is not cordoned, matches the
+// node selector and every NoSchedule and NoExecute taint is tolerated. Only
+// the metadata of the nodes is cached, the nodes matching the node selector
+// are read until enough schedulable nodes are found to tell the topology.
+func (c *deploymentHandler) schedulableNodes(cro *operatorv1.ClusterResourceOverride) (int, error) {
+	nodeSelector := c.nodeSelector(cro)
+	if nodeSelector == nil {
+		nodeSelector = asset.DefaultNodeSelector
+	}
+	tolerations := c.tolerations(cro, asset.DefaultTolerations)
+
+	candidates, err := c.nodes.List(labels.SelectorFromSet(nodeSelector))
+	if err != nil {
+		return 0, err
+	}
+
+	// more nodes than replicas do not change the topology.
+	limit := int(asset.DefaultReplicas)
+	if override := cro.Spec.DeploymentOverrides.Replicas; override != nil && int(*override) > limit {
+		limit = int(*override)
+	}
+
+	count := 0
+	for _, candidate := range candidates {
+		if count >= limit {
+			break
+		}
+
+		node, err := c.client.CoreV1().Nodes().Get(context.TODO(), candidate.GetName(), metav1.GetOptions{})
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return 0, err
+		}
+		if node.Spec.Unschedulable || !tolerated(node.Spec.Taints, tolerations) {
+			continue
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+func tolerated(taints []corev1.Taint, tolerations []corev1.Toleration) bool {
+	for i := range taints {
+		taint := &taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+
+		tolerates := false
+		for j := range tolerations {
+			if tolerations[j].ToleratesTaint(klog.Background(), taint, false) {
+				tolerates = true
+				break
+			}
+		}
+		if !tolerates {
+			return false
+		}
+	}
+
+	return true
+}
+
+// platform returns the type of the detected platform, empty if none was
+// detected.
+func (c *deploymentHandler) platform(cro *operatorv1.ClusterResourceOverride) string {
+	if platform := cro.Status.Platform; platform != nil {
+		return string(platform.Type)
+	}
+
+	return ""
+}
+
 func (c *deploymentHandler) EnsureRBAC(context *ReconcileRequestContext, in *operatorv1.ClusterResourceOverride) error {
 	list := c.asset.RBAC().New()
 	for _, item := range list {
-		context.ControllerSetter()(item.Object, in)
+		// in a management cluster the admission webhook reaches the hosted
+		// cluster with its kubeconfig, it needs no more than its service
+		// account.
+		if c.asset.OnManagementCluster() && item.Resource != "serviceaccounts" {
+			continue
+		}
+
+		if item.Aggregated && c.asset.WebhookTransport(in.Spec.GetWebhookTransport()) == operatorv1.WebhookTransportService {
+			if err := c.deleteRBAC(in, item); err != nil {
+				return err
+			}
+			continue
+		}
+
+		context.OperandControllerSetter(c.asset)(item.Object, in)
 
 		current, err := c.dynamic.Ensure(item.Resource, item.Object)
 		if err != nil {
@@ -244,3 +539,40 @@ func (c *deploymentHandler) EnsureRBAC(context *ReconcileRequestContext, in *ope
 
 	return nil
 }
+
+// deleteRBAC removes an RBAC item the webhook transport does not need, left
+// behind by the previous transport. An object of the same name that does not
+// belong to the operator is left alone.
+func (c *deploymentHandler) deleteRBAC(in *operatorv1.ClusterResourceOverride, item *asset.RBACItem) error {
+	gvk := item.Object.GetObjectKind().GroupVersionKind()
+	resource := gvk.GroupVersion().WithResource(item.Resource)
+
+	var client dynamic.ResourceInterface = c.dynClient.Resource(resource)
+	if namespace := item.Object.GetNamespace(); namespace != "" {
+		client = c.dynClient.Resource(resource).Namespace(namespace)
+	}
+
+	current, err := client.Get(context.TODO(), item.Object.GetName(), metav1.GetOptions{})
+	switch {
+	case k8serrors.IsNotFound(err):
+		return nil
+	case err != nil:
+		return fmt.Errorf("resource=%s failed to get RBAC %s - %s", item.Resource, item.Object.GetName(), err.Error())
+	}
+
+	if !owns(c.asset.Values(), in, current) {
+		klog.V(2).Infof("key=%s resource=%s/%s is not owned by the operator, skipping", in.Name, gvk.Kind, item.Object.GetName())
+		return nil
+	}
+
+	err = client.Delete(context.TODO(), item.Object.GetName(), metav1.DeleteOptions{})
+	switch {
+	case k8serrors.IsNotFound(err):
+		return nil
+	case err != nil:
+		return fmt.Errorf("resource=%s failed to delete RBAC %s - %s", item.Resource, item.Object.GetName(), err.Error())
+	}
+
+	klog.V(2).Infof("resource=%s/%s deleted, not needed with the %s webhook transport", gvk.Kind, item.Object.GetName(), operatorv1.WebhookTransportService)
+	return nil
+}
@@ -1,17 +1,28 @@
 package handlers
 
 import (
+	"context"
+	"fmt"
 	"testing"
 
+	configv1 "github.com/openshift/api/config/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/metadata/metadatalister"
+	"k8s.io/client-go/tools/cache"
 
 	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/deploy"
+	dynamicclient "github.com/openshift/cluster-resource-override-admission-operator/pkg/dynamic"
 	operatorruntime "github.com/openshift/cluster-resource-override-admission-operator/pkg/runtime"
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/tlsprofile"
 )
@@ -251,3 +262,492 @@ func TestApplyToToPodTemplate_UserOverrideOnStandalone(t *testing.T) {
 	assert.Equal(t, cro.Spec.DeploymentOverrides.Tolerations, pt.Spec.Tolerations,
 		"user override should take precedence over auto tolerations")
 }
+
+func TestApplyToToPodTemplate_ServingCertHashAnnotation(t *testing.T) {
+	h := minimalHandler(t)
+	ctx := NewReconcileRequestContext(operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0"))
+	cro := minimalCRO()
+	cro.Status.Hash.ServingCert = "certhash"
+	pt := podTemplateWithBaseArgs()
+
+	h.ApplyToToPodTemplate(ctx, cro, tlsprofile.Args{}).Apply(pt)
+
+	assert.Equal(t, "certhash", pt.GetAnnotations()[h.asset.Values().ServingCertHashAnnotationKey])
+}
+
+func TestApplyToToPodTemplate_ServiceWebhookTransport(t *testing.T) {
+	h := minimalHandler(t)
+	ctx := NewReconcileRequestContext(operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0"))
+	cro := minimalCRO()
+	cro.Spec.WebhookTransport = operatorv1.WebhookTransportService
+	pt := podTemplateWithBaseArgs()
+
+	h.ApplyToToPodTemplate(ctx, cro, tlsprofile.Args{}).Apply(pt)
+	h.ApplyToToPodTemplate(ctx, cro, tlsprofile.Args{}).Apply(pt)
+
+	args := pt.Spec.Containers[0].Args
+	assert.Len(t, args, 6, "the webhook transport args must not be duplicated")
+	assert.Contains(t, args, asset.AuthenticationSkipLookupArg)
+	assert.Contains(t, args, asset.AuthorizationAlwaysAllowPathsArg+"=/healthz,/readyz,/livez,/apis/admission.autoscaling.openshift.io/v1/clusterresourceoverrides")
+
+	// switching back to the Aggregated transport drops them.
+	cro.Spec.WebhookTransport = operatorv1.WebhookTransportAggregated
+	h.ApplyToToPodTemplate(ctx, cro, tlsprofile.Args{}).Apply(pt)
+	assert.Equal(t, podTemplateWithBaseArgs().Spec.Containers[0].Args, pt.Spec.Containers[0].Args)
+}
+
+func TestApplyToDeploymentObject_WebhookTransportAnnotation(t *testing.T) {
+	h := minimalHandler(t)
+	ctx := NewReconcileRequestContext(operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0"))
+	cro := minimalCRO()
+	deployment := &appsv1.Deployment{}
+
+	h.ApplyToDeploymentObject(ctx, cro, tlsprofile.Args{}).Apply(deployment)
+	assert.Equal(t, string(operatorv1.WebhookTransportAggregated), deployment.GetAnnotations()[h.asset.Values().WebhookTransportAnnotationKey])
+}
+
+func TestApplyToToPodTemplate_DetectedPlatformWins(t *testing.T) {
+	ctx := NewReconcileRequestContext(operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0"))
+
+	// the IsStandalone option only applies without a detected platform.
+	h := minimalStandaloneHandler(t)
+	cro := minimalCRO()
+	cro.Status.Platform = &operatorv1.PlatformStatus{Type: operatorv1.PlatformHyperShift}
+	pt := podTemplateWithBaseArgs()
+	h.ApplyToToPodTemplate(ctx, cro, tlsprofile.Args{}).Apply(pt)
+	assert.Empty(t, pt.Spec.NodeSelector)
+	assert.Empty(t, pt.Spec.Tolerations)
+
+	h = minimalHandler(t)
+	cro.Status.Platform = &operatorv1.PlatformStatus{Type: operatorv1.PlatformSingleReplica}
+	pt = podTemplateWithBaseArgs()
+	h.ApplyToToPodTemplate(ctx, cro, tlsprofile.Args{}).Apply(pt)
+	assert.Equal(t, map[string]string{"node-role.kubernetes.io/control-plane": ""}, pt.Spec.NodeSelector)
+
+	deployment := &appsv1.Deployment{}
+	h.ApplyToDeploymentObject(ctx, cro, tlsprofile.Args{}).Apply(deployment)
+	assert.Equal(t, string(operatorv1.PlatformSingleReplica), deployment.GetAnnotations()[h.asset.Values().PlatformAnnotationKey])
+}
+
+// watchNodes caches the metadata of the given nodes for the handler, the
+// nodes themselves are read from its client.
+func watchNodes(t *testing.T, h *deploymentHandler, nodes ...*corev1.Node) {
+	t.Helper()
+	if h.client == nil {
+		h.client = kubefake.NewSimpleClientset()
+	}
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, node := range nodes {
+		require.NoError(t, indexer.Add(&metav1.PartialObjectMetadata{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Node"}, ObjectMeta: node.ObjectMeta}))
+
+		_, err := h.client.CoreV1().Nodes().Create(context.TODO(), node, metav1.CreateOptions{})
+		if !k8serrors.IsAlreadyExists(err) {
+			require.NoError(t, err)
+		}
+	}
+	h.nodes = metadatalister.New(indexer, corev1.SchemeGroupVersion.WithResource("nodes"))
+}
+
+func controlPlaneNode(name string, spec corev1.NodeSpec) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"node-role.kubernetes.io/control-plane": ""},
+		},
+		Spec: spec,
+	}
+}
+
+func TestTopology(t *testing.T) {
+	controlPlaneTaint := corev1.Taint{Key: "node-role.kubernetes.io/control-plane", Effect: corev1.TaintEffectNoSchedule}
+	two := int32(2)
+
+	tests := []struct {
+		name     string
+		platform *operatorv1.PlatformStatus
+		replicas *int32
+		nodes    []*corev1.Node
+		want     topology
+	}{
+		{
+			name: "no nodes known keeps the defaults",
+			want: topology{Replicas: asset.DefaultReplicas, RequiredAntiAffinity: true},
+		},
+		{
+			name:     "SingleReplica runs one replica",
+			platform: &operatorv1.PlatformStatus{Type: operatorv1.PlatformSingleReplica},
+			want:     topology{Replicas: 1},
+		},
+		{
+			name:     "SingleReplica infrastructure topology runs one replica",
+			platform: &operatorv1.PlatformStatus{Type: operatorv1.PlatformHyperShift, InfrastructureTopology: "SingleReplica"},
+			want:     topology{Replicas: 1},
+		},
+		{
+			name:     "explicit replicas win",
+			platform: &operatorv1.PlatformStatus{Type: operatorv1.PlatformSingleReplica},
+			replicas: &two,
+			want:     topology{Replicas: 2},
+		},
+		{
+			name:     "three schedulable nodes",
+			platform: &operatorv1.PlatformStatus{Type: operatorv1.PlatformOpenShift},
+			nodes: []*corev1.Node{
+				controlPlaneNode("a", corev1.NodeSpec{Taints: []corev1.Taint{controlPlaneTaint}}),
+				controlPlaneNode("b", corev1.NodeSpec{Taints: []corev1.Taint{controlPlaneTaint}}),
+				controlPlaneNode("c", corev1.NodeSpec{}),
+			},
+			want: topology{Replicas: 2, RequiredAntiAffinity: true},
+		},
+		{
+			name:     "cordoned, untolerated and unselected nodes are not schedulable",
+			platform: &operatorv1.PlatformStatus{Type: operatorv1.PlatformOpenShift},
+			nodes: []*corev1.Node{
+				controlPlaneNode("a", corev1.NodeSpec{}),
+				controlPlaneNode("b", corev1.NodeSpec{Unschedulable: true}),
+				controlPlaneNode("c", corev1.NodeSpec{Taints: []corev1.Taint{{Key: "dedicated", Effect: corev1.TaintEffectNoExecute}}}),
+				{ObjectMeta: metav1.ObjectMeta{Name: "worker"}},
+			},
+			want: topology{Replicas: 1},
+		},
+		{
+			name:     "explicit replicas beyond the schedulable nodes",
+			platform: &operatorv1.PlatformStatus{Type: operatorv1.PlatformOpenShift},
+			replicas: &two,
+			nodes:    []*corev1.Node{controlPlaneNode("a", corev1.NodeSpec{})},
+			want:     topology{Replicas: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := minimalHandler(t)
+			if tt.nodes != nil {
+				watchNodes(t, h, tt.nodes...)
+			}
+			cro := minimalCRO()
+			cro.Status.Platform = tt.platform
+			cro.Spec.DeploymentOverrides.Replicas = tt.replicas
+
+			assert.Equal(t, tt.want, h.topology(cro))
+		})
+	}
+}
+
+func TestApplyTopology(t *testing.T) {
+	h := minimalHandler(t)
+	ctx := NewReconcileRequestContext(operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0"))
+	cro := minimalCRO()
+	cro.Status.Platform = &operatorv1.PlatformStatus{Type: operatorv1.PlatformSingleReplica}
+
+	deployment := h.asset.Deployment().New()
+	h.ApplyToDeploymentObject(ctx, cro, tlsprofile.Args{}).Apply(deployment)
+	h.ApplyToToPodTemplate(ctx, cro, tlsprofile.Args{}).Apply(&deployment.Spec.Template)
+
+	require.NotNil(t, deployment.Spec.Replicas)
+	assert.Equal(t, int32(1), *deployment.Spec.Replicas)
+	assert.Equal(t, "1/preferred", deployment.GetAnnotations()[h.asset.Values().TopologyAnnotationKey])
+
+	antiAffinity := deployment.Spec.Template.Spec.Affinity.PodAntiAffinity
+	assert.Empty(t, antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+	require.Len(t, antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution, 1)
+	assert.Equal(t, "kubernetes.io/hostname", antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0].PodAffinityTerm.TopologyKey)
+}
+
+func TestApplyOnManagementCluster(t *testing.T) {
+	operandContext := operatorruntime.NewHostedOperandContext("clusterresourceoverride", "clusters-hosted", "cluster", "img", "1.0", "clusterresourceoverride-kubeconfig")
+	h := &deploymentHandler{
+		asset: asset.New(operandContext),
+	}
+	ctx := NewReconcileRequestContext(operandContext)
+	cro := minimalCRO()
+	cro.Status.Platform = &operatorv1.PlatformStatus{Type: operatorv1.PlatformHyperShift, InfrastructureTopology: "SingleReplica"}
+
+	deployment := h.asset.Deployment().New()
+	h.ApplyToDeploymentObject(ctx, cro, tlsprofile.Args{}).Apply(deployment)
+	h.ApplyToToPodTemplate(ctx, cro, tlsprofile.Args{}).Apply(&deployment.Spec.Template)
+
+	// the hosted cluster is reached with the mounted kubeconfig, and calls the
+	// admission webhook by URL, as with the Service transport.
+	args := deployment.Spec.Template.Spec.Containers[0].Args
+	assert.Contains(t, args, "--kubeconfig=/etc/kubernetes/hosted/kubeconfig")
+	assert.Contains(t, args, "--authentication-kubeconfig=/etc/kubernetes/hosted/kubeconfig")
+	assert.Contains(t, args, "--authorization-kubeconfig=/etc/kubernetes/hosted/kubeconfig")
+	assert.Contains(t, args, asset.AuthenticationSkipLookupArg)
+	assert.Equal(t, string(operatorv1.WebhookTransportService), deployment.GetAnnotations()[h.asset.Values().WebhookTransportAnnotationKey])
+
+	var secretName string
+	for _, volume := range deployment.Spec.Template.Spec.Volumes {
+		if volume.Secret != nil && volume.Secret.SecretName == "clusterresourceoverride-kubeconfig" {
+			secretName = volume.Secret.SecretName
+		}
+	}
+	assert.Equal(t, "clusterresourceoverride-kubeconfig", secretName)
+
+	// the ClusterResourceOverride of the hosted cluster is not an owner in the
+	// management cluster, the topology of the hosted cluster does not apply.
+	assert.Empty(t, deployment.GetOwnerReferences())
+	assert.Equal(t, cro.Name, deployment.GetAnnotations()[h.asset.Values().OwnerAnnotationKey])
+	assert.Equal(t, asset.DefaultReplicas, *deployment.Spec.Replicas)
+
+	webhook := h.asset.NewMutatingWebhookConfiguration().New()
+	h.asset.NewMutatingWebhookConfiguration().ApplyWebhookTransport(webhook, h.asset.WebhookTransport(cro.Spec.GetWebhookTransport()))
+	for _, w := range webhook.Webhooks {
+		assert.Nil(t, w.ClientConfig.Service)
+		require.NotNil(t, w.ClientConfig.URL)
+		assert.Equal(t, "https://clusterresourceoverride.clusters-hosted.svc:443/apis/admission.autoscaling.openshift.io/v1/clusterresourceoverrides", *w.ClientConfig.URL)
+	}
+}
+
+// apiServerClient returns a dynamic client serving the cluster APIServer
+// config with the given TLS profile.
+func apiServerClient(t *testing.T, profile *configv1.TLSSecurityProfile) *dynamicfake.FakeDynamicClient {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, configv1.AddToScheme(scheme))
+	apiServer := &configv1.APIServer{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "config.openshift.io/v1", Kind: "APIServer"},
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: configv1.APIServerSpec{
+			TLSSecurityProfile: profile,
+			TLSAdherence:       configv1.TLSAdherencePolicyStrictAllComponents,
+		},
+	}
+	return dynamicfake.NewSimpleDynamicClient(scheme, apiServer)
+}
+
+func TestTLSProfile(t *testing.T) {
+	modern := &configv1.TLSSecurityProfile{Type: configv1.TLSProfileModernType}
+	custom := &configv1.TLSSecurityProfile{
+		Type: configv1.TLSProfileCustomType,
+		Custom: &configv1.CustomTLSProfile{
+			TLSProfileSpec: configv1.TLSProfileSpec{
+				Ciphers:       []string{"ECDHE-RSA-AES128-GCM-SHA256"},
+				MinTLSVersion: configv1.VersionTLS12,
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		spec       *configv1.TLSSecurityProfile
+		platform   *operatorv1.PlatformStatus
+		wantSource operatorv1.TLSProfileSource
+		wantArgs   tlsprofile.Args
+	}{
+		{
+			name:       "the cluster profile without a spec profile",
+			wantSource: operatorv1.TLSProfileSourceAPIServer,
+			wantArgs:   tlsprofile.Args{MinVersion: "VersionTLS13"},
+		},
+		{
+			name:       "the spec profile wins over the cluster profile",
+			spec:       custom,
+			wantSource: operatorv1.TLSProfileSourceClusterResourceOverride,
+			wantArgs:   tlsprofile.Args{MinVersion: "VersionTLS12", CipherSuites: "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+		},
+		{
+			name:       "no cluster profile without config.openshift.io",
+			platform:   &operatorv1.PlatformStatus{Type: operatorv1.PlatformKubernetes},
+			wantSource: operatorv1.TLSProfileSourceDefault,
+		},
+		{
+			name:       "the spec profile without config.openshift.io",
+			spec:       custom,
+			platform:   &operatorv1.PlatformStatus{Type: operatorv1.PlatformKubernetes},
+			wantSource: operatorv1.TLSProfileSourceClusterResourceOverride,
+			wantArgs:   tlsprofile.Args{MinVersion: "VersionTLS12", CipherSuites: "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := minimalHandler(t)
+			h.dynClient = apiServerClient(t, modern)
+			cro := minimalCRO()
+			cro.Spec.TLSSecurityProfile = tt.spec
+			cro.Status.Platform = tt.platform
+
+			args, source := h.tlsProfile(cro)
+			assert.Equal(t, tt.wantSource, source)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestValidateTLSSecurityProfile(t *testing.T) {
+	custom := func(minTLSVersion configv1.TLSProtocolVersion, ciphers ...string) *configv1.TLSSecurityProfile {
+		return &configv1.TLSSecurityProfile{
+			Type: configv1.TLSProfileCustomType,
+			Custom: &configv1.CustomTLSProfile{
+				TLSProfileSpec: configv1.TLSProfileSpec{Ciphers: ciphers, MinTLSVersion: minTLSVersion},
+			},
+		}
+	}
+
+	assert.NoError(t, validateTLSSecurityProfile(&configv1.TLSSecurityProfile{Type: configv1.TLSProfileIntermediateType}))
+	assert.NoError(t, validateTLSSecurityProfile(custom(configv1.VersionTLS12, "ECDHE-RSA-AES128-GCM-SHA256")))
+	assert.Error(t, validateTLSSecurityProfile(&configv1.TLSSecurityProfile{Type: "Unknown"}))
+	assert.Error(t, validateTLSSecurityProfile(custom("VersionTLS99", "ECDHE-RSA-AES128-GCM-SHA256")))
+	assert.Error(t, validateTLSSecurityProfile(custom(configv1.VersionTLS12, "NOT-A-CIPHER")))
+}
+
+// fakeDeployment is a deploy.Interface holding the Deployment in memory.
+type fakeDeployment struct {
+	asset      *asset.Asset
+	deployment *appsv1.Deployment
+	ensured    int
+}
+
+func (d *fakeDeployment) Name() string { return d.asset.Deployment().Name() }
+func (d *fakeDeployment) IsAvailable(checkGeneration bool) (bool, error) {
+	return d.deployment != nil, nil
+}
+func (d *fakeDeployment) HasAvailableReplicas() (bool, error) {
+	return d.deployment != nil, nil
+}
+func (d *fakeDeployment) Get() (runtime.Object, metav1.Object, error) {
+	if d.deployment == nil {
+		return nil, nil, k8serrors.NewNotFound(appsv1.Resource("deployments"), d.Name())
+	}
+	return d.deployment, d.deployment, nil
+}
+func (d *fakeDeployment) Ensure(parent, child deploy.Applier) (runtime.Object, metav1.Object, error) {
+	desired := d.asset.Deployment().New()
+	parent.Apply(desired)
+	child.Apply(&desired.Spec.Template)
+
+	d.ensured++
+	desired.ResourceVersion = fmt.Sprintf("%d", d.ensured)
+	d.deployment = desired
+	return desired, desired, nil
+}
+
+// newDeploymentHandlerFixture returns a deployment handler whose Deployment is
+// in sync with the given ClusterResourceOverride and nodes, and the client
+// holding its MutatingWebhookConfiguration.
+func newDeploymentHandlerFixture(t *testing.T, cro *operatorv1.ClusterResourceOverride, nodes ...*corev1.Node) (*deploymentHandler, *fakeDeployment, *kubefake.Clientset) {
+	t.Helper()
+	operandContext := operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0")
+	a := asset.New(operandContext)
+
+	client := kubefake.NewSimpleClientset(a.NewMutatingWebhookConfiguration().New())
+	fake := &fakeDeployment{asset: a}
+	h := &deploymentHandler{
+		client:        client,
+		operandClient: kubefake.NewSimpleClientset(),
+		asset:         a,
+		deploy:        fake,
+		dynClient:     apiServerClient(t, nil),
+	}
+	if len(nodes) > 0 {
+		watchNodes(t, h, nodes...)
+	}
+
+	cro.Status.Image = a.Values().OperandImage
+	cro.Status.Version = a.Values().OperandVersion
+	tlsArgs, _ := h.tlsProfile(cro)
+	_, _, err := h.Update(NewReconcileRequestContext(operandContext), cro, tlsArgs)
+	require.NoError(t, err)
+
+	return h, fake, client
+}
+
+func TestDeploymentHandlerServingCertRotation(t *testing.T) {
+	cro := minimalCRO()
+	cro.Status.Hash.ServingCert = "before"
+	h, fake, client := newDeploymentHandlerFixture(t, cro)
+
+	cro.Status.Hash.ServingCert = "after"
+	_, _, err := h.Handle(NewReconcileRequestContext(operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0")), cro)
+	require.NoError(t, err)
+
+	// the pods are rolled out with the rotated certificate, the admission
+	// webhook is not removed meanwhile.
+	assert.Equal(t, 2, fake.ensured)
+	assert.Equal(t, "after", fake.deployment.Spec.Template.GetAnnotations()[h.asset.Values().ServingCertHashAnnotationKey])
+
+	_, err = client.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), h.asset.NewMutatingWebhookConfiguration().Name(), metav1.GetOptions{})
+	require.NoError(t, err)
+	for _, action := range client.Actions() {
+		assert.NotEqual(t, "delete", action.GetVerb(), "%s was deleted", action.GetResource().Resource)
+	}
+}
+
+func TestDeploymentHandlerServingCertRotationWithTransportChange(t *testing.T) {
+	cro := minimalCRO()
+	cro.Status.Hash.ServingCert = "before"
+	h, _, client := newDeploymentHandlerFixture(t, cro)
+	h.dynamic = dynamicclient.NewEnsurer(dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()))
+	h.dynClient = dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+
+	// the transport change still re-creates the admission webhook.
+	cro.Status.Hash.ServingCert = "after"
+	cro.Spec.WebhookTransport = operatorv1.WebhookTransportService
+	_, _, err := h.Handle(NewReconcileRequestContext(operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0")), cro)
+	require.NoError(t, err)
+
+	_, err = client.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), h.asset.NewMutatingWebhookConfiguration().Name(), metav1.GetOptions{})
+	require.True(t, k8serrors.IsNotFound(err))
+}
+
+func TestEnsureRBACServiceTransportLeavesForeignObjects(t *testing.T) {
+	cro := minimalCRO()
+	h, _, _ := newDeploymentHandlerFixture(t, cro)
+
+	clusterRoleGVR := schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}
+	requester := newOwnedUnstructured(h.asset, clusterRoleGVR, "ClusterRole", "", "system:clusterresourceoverride-requester")
+	foreign := newUnstructured(roleBindingGVR, "RoleBinding", "kube-system", "extension-server-authentication-reader-clusterresourceoverride")
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), requester, foreign)
+	h.dynamic = dynamicclient.NewEnsurer(dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()))
+	h.dynClient = client
+
+	cro.Spec.WebhookTransport = operatorv1.WebhookTransportService
+	require.NoError(t, h.EnsureRBAC(NewReconcileRequestContext(operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0")), cro))
+
+	// the aggregation RBAC of the operator is removed, an object of the same
+	// name it did not create is left alone.
+	assert.False(t, exists(t, client, clusterRoleGVR, "", "system:clusterresourceoverride-requester"))
+	assert.True(t, exists(t, client, roleBindingGVR, "kube-system", "extension-server-authentication-reader-clusterresourceoverride"))
+}
+
+func TestDeploymentHandlerNodeCountChange(t *testing.T) {
+	cro := minimalCRO()
+	cro.Status.Platform = &operatorv1.PlatformStatus{Type: operatorv1.PlatformOpenShift}
+	h, fake, client := newDeploymentHandlerFixture(t, cro, controlPlaneNode("a", corev1.NodeSpec{}), controlPlaneNode("b", corev1.NodeSpec{}))
+	require.Equal(t, int32(2), *fake.deployment.Spec.Replicas)
+
+	// a node is removed.
+	watchNodes(t, h, controlPlaneNode("a", corev1.NodeSpec{}))
+	_, _, err := h.Handle(NewReconcileRequestContext(operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0")), cro)
+	require.NoError(t, err)
+
+	// the replicas and the anti-affinity are updated in place, the admission
+	// webhook is not removed meanwhile.
+	assert.Equal(t, 2, fake.ensured)
+	assert.Equal(t, int32(1), *fake.deployment.Spec.Replicas)
+	assert.Equal(t, "1/preferred", fake.deployment.GetAnnotations()[h.asset.Values().TopologyAnnotationKey])
+	assert.Empty(t, fake.deployment.Spec.Template.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+
+	_, err = client.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), h.asset.NewMutatingWebhookConfiguration().Name(), metav1.GetOptions{})
+	require.NoError(t, err)
+	for _, action := range client.Actions() {
+		assert.NotEqual(t, "delete", action.GetVerb(), "%s was deleted", action.GetResource().Resource)
+	}
+}
+
+func TestDeploymentHandlerConfigurationChange(t *testing.T) {
+	cro := minimalCRO()
+	h, fake, client := newDeploymentHandlerFixture(t, cro)
+	h.dynamic = dynamicclient.NewEnsurer(dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()))
+
+	cro.Status.Hash.Configuration = "changed"
+	_, _, err := h.Handle(NewReconcileRequestContext(operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0")), cro)
+	require.NoError(t, err)
+
+	// the admission webhook is re-created with the new configuration.
+	assert.Equal(t, 2, fake.ensured)
+	_, err = client.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), h.asset.NewMutatingWebhookConfiguration().Name(), metav1.GetOptions{})
+	require.True(t, k8serrors.IsNotFound(err))
+}
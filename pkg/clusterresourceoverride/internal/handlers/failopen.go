@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	listersadmissionregistrationv1 "k8s.io/client-go/listers/admissionregistration/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+	controllerreconciler "sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/clusterresourceoverride/internal/condition"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/deploy"
+)
+
+// NewFailOpenHandler returns a handler that switches the failurePolicy of the
+// MutatingWebhookConfiguration to Ignore when the admission webhook has had no
+// available replica for longer than the grace period of the failOpen policy,
+// and restores Fail once it is available again. A rollout that keeps a replica
+// available is not an outage.
+func NewFailOpenHandler(o *Options) *failOpenHandler {
+	return &failOpenHandler{
+		clock:  clock.RealClock{},
+		client: o.Client.Kubernetes,
+		lister: o.SecondaryLister.AdmissionRegistrationV1MutatingWebhookConfigurationLister(),
+		asset:  o.Asset,
+		deploy: o.Deploy,
+	}
+}
+
+type failOpenHandler struct {
+	clock  clock.PassiveClock
+	client kubernetes.Interface
+	lister listersadmissionregistrationv1.MutatingWebhookConfigurationLister
+	asset  *asset.Asset
+	deploy deploy.Interface
+}
+
+func (f *failOpenHandler) Handle(context *ReconcileRequestContext, original *operatorv1.ClusterResourceOverride) (current *operatorv1.ClusterResourceOverride, result controllerreconciler.Result, handleErr error) {
+	current = original
+	builder := condition.NewBuilderWithStatus(&current.Status)
+	key := f.asset.Values().FailOpenAnnotationKey
+
+	name := f.asset.NewMutatingWebhookConfiguration().Name()
+	webhook, err := f.lister.Get(name)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			handleErr = condition.NewInstallReadinessError(operatorv1.InternalError, err)
+			return
+		}
+
+		// the webhook configuration handler creates it with Fail.
+		webhook = nil
+	}
+	failingOpen := webhook != nil && webhook.GetAnnotations()[key] != ""
+
+	policy := original.Spec.FailOpen
+	available, _ := f.deploy.HasAvailableReplicas()
+
+	if policy == nil || available {
+		if failingOpen {
+			if err := f.setFailurePolicy(webhook, admissionregistrationv1.Fail, ""); err != nil {
+				handleErr = condition.NewInstallReadinessError(operatorv1.InternalError, err)
+				return
+			}
+
+			klog.V(2).Infof("key=%s resource=%T/%s failurePolicy restored to %s", original.Name, webhook, webhook.Name, admissionregistrationv1.Fail)
+		}
+
+		if policy == nil {
+			current.Status.FailOpen = nil
+			builder.WithoutCondition(operatorv1.Degraded)
+			return
+		}
+
+		current.Status.FailOpen = &operatorv1.FailOpenStatus{}
+		f.report(builder, corev1.ConditionFalse, "", "")
+		return
+	}
+
+	now := metav1.NewTime(f.clock.Now())
+	status := current.Status.FailOpen
+	if status == nil {
+		status = &operatorv1.FailOpenStatus{}
+		current.Status.FailOpen = status
+	}
+	if status.UnavailableSince == nil {
+		status.UnavailableSince = &now
+	}
+
+	if failingOpen {
+		if status.Since == nil {
+			since := now
+			if switched, err := time.Parse(time.RFC3339, webhook.GetAnnotations()[key]); err == nil {
+				since = metav1.NewTime(switched)
+			}
+			status.Since = &since
+		}
+
+		f.reportFailOpen(builder, status)
+		return
+	}
+
+	gracePeriod := policy.GetGracePeriod()
+	if policy.DuringRollout {
+		gracePeriod = 0
+	}
+
+	if remaining := gracePeriod - now.Sub(status.UnavailableSince.Time); remaining > 0 {
+		klog.V(2).Infof("key=%s resource=%s deployment unavailable, failing open in %s", original.Name, f.deploy.Name(), remaining)
+
+		context.RequeueAfter(remaining)
+		f.report(builder, corev1.ConditionFalse, "", fmt.Sprintf("the admission webhook is unavailable since %s, the failurePolicy is switched to Ignore after %s",
+			status.UnavailableSince.UTC().Format(time.RFC3339), gracePeriod))
+		return
+	}
+
+	if webhook == nil {
+		// nothing to fail open yet, the webhook configuration is created once the
+		// admission webhook is available.
+		return
+	}
+
+	if err := f.setFailurePolicy(webhook, admissionregistrationv1.Ignore, now.UTC().Format(time.RFC3339)); err != nil {
+		handleErr = condition.NewInstallReadinessError(operatorv1.InternalError, err)
+		return
+	}
+
+	klog.Warningf("key=%s resource=%T/%s admission webhook unavailable since %s, failurePolicy switched to %s", original.Name, webhook, webhook.Name,
+		status.UnavailableSince.UTC().Format(time.RFC3339), admissionregistrationv1.Ignore)
+
+	status.Since = &now
+	f.reportFailOpen(builder, status)
+	return
+}
+
+func (f *failOpenHandler) reportFailOpen(builder *condition.Builder, status *operatorv1.FailOpenStatus) {
+	f.report(builder, corev1.ConditionTrue, operatorv1.FailOpen, fmt.Sprintf("the admission webhook is unavailable since %s, the failurePolicy is switched to Ignore since %s, pods are admitted without overrides",
+		status.UnavailableSince.UTC().Format(time.RFC3339), status.Since.UTC().Format(time.RFC3339)))
+}
+
+func (f *failOpenHandler) report(builder *condition.Builder, status corev1.ConditionStatus, reason, message string) {
+	builder.WithCondition(&operatorv1.ClusterResourceOverrideCondition{
+		Type:               operatorv1.Degraded,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: metav1.NewTime(f.clock.Now()),
+	})
+}
+
+// setFailurePolicy sets the failurePolicy of every webhook of the given
+// configuration, and the fail-open annotation to the given value, or removes
+// it if empty.
+func (f *failOpenHandler) setFailurePolicy(original *admissionregistrationv1.MutatingWebhookConfiguration, policy admissionregistrationv1.FailurePolicyType, since string) error {
+	key := f.asset.Values().FailOpenAnnotationKey
+
+	webhook := original.DeepCopy()
+	for i := range webhook.Webhooks {
+		webhook.Webhooks[i].FailurePolicy = &policy
+	}
+
+	if since == "" {
+		delete(webhook.Annotations, key)
+	} else {
+		if webhook.Annotations == nil {
+			webhook.Annotations = map[string]string{}
+		}
+		webhook.Annotations[key] = since
+	}
+
+	if _, err := f.client.AdmissionregistrationV1().MutatingWebhookConfigurations().Update(context.TODO(), webhook, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to set failurePolicy of %s to %s - %s", webhook.Name, policy, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	listersadmissionregistrationv1 "k8s.io/client-go/listers/admissionregistration/v1"
+	listersappsv1 "k8s.io/client-go/listers/apps/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/clusterresourceoverride/internal/condition"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/deploy"
+	operatorruntime "github.com/openshift/cluster-resource-override-admission-operator/pkg/runtime"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time                  { return c.now }
+func (c *fakeClock) Since(t time.Time) time.Duration { return c.now.Sub(t) }
+
+type fakeDeploy struct {
+	available bool
+}
+
+func (d *fakeDeploy) Name() string { return "clusterresourceoverride" }
+func (d *fakeDeploy) IsAvailable(checkGeneration bool) (bool, error) {
+	return d.available, nil
+}
+func (d *fakeDeploy) HasAvailableReplicas() (bool, error) {
+	return d.available, nil
+}
+func (d *fakeDeploy) Get() (runtime.Object, metav1.Object, error) { return nil, nil, nil }
+func (d *fakeDeploy) Ensure(parent, child deploy.Applier) (runtime.Object, metav1.Object, error) {
+	return nil, nil, nil
+}
+
+type failOpenFixture struct {
+	handler *failOpenHandler
+	client  *kubefake.Clientset
+	indexer cache.Indexer
+	deploy  *fakeDeploy
+	clock   *fakeClock
+	name    string
+}
+
+func newFailOpenFixture(t *testing.T) *failOpenFixture {
+	a := asset.New(operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0"))
+	webhook := a.NewMutatingWebhookConfiguration().New()
+
+	f := &failOpenFixture{
+		client:  kubefake.NewSimpleClientset(webhook),
+		indexer: cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{}),
+		deploy:  &fakeDeploy{},
+		clock:   &fakeClock{now: time.Date(2024, 7, 24, 17, 0, 0, 0, time.UTC)},
+		name:    webhook.Name,
+	}
+	require.NoError(t, f.indexer.Add(webhook))
+
+	f.handler = &failOpenHandler{
+		clock:  f.clock,
+		client: f.client,
+		lister: listersadmissionregistrationv1.NewMutatingWebhookConfigurationLister(f.indexer),
+		asset:  a,
+		deploy: f.deploy,
+	}
+	return f
+}
+
+// handle invokes the handler and syncs the lister with the client.
+func (f *failOpenFixture) handle(t *testing.T, cro *operatorv1.ClusterResourceOverride) (*operatorv1.ClusterResourceOverride, *ReconcileRequestContext) {
+	context := NewReconcileRequestContext(operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0"))
+	current, _, err := f.handler.Handle(context, cro)
+	require.NoError(t, err)
+
+	require.NoError(t, f.indexer.Update(f.webhook(t)))
+	return current, context
+}
+
+func (f *failOpenFixture) webhook(t *testing.T) *admissionregistrationv1.MutatingWebhookConfiguration {
+	webhook, err := f.client.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), f.name, metav1.GetOptions{})
+	require.NoError(t, err)
+	return webhook
+}
+
+func (f *failOpenFixture) failurePolicy(t *testing.T) admissionregistrationv1.FailurePolicyType {
+	return *f.webhook(t).Webhooks[0].FailurePolicy
+}
+
+func TestFailOpenHandler(t *testing.T) {
+	f := newFailOpenFixture(t)
+	cro := minimalCRO()
+	cro.Spec.FailOpen = &operatorv1.FailOpenPolicy{GracePeriod: &metav1.Duration{Duration: 2 * time.Minute}}
+
+	// within the grace period, Fail is kept.
+	current, context := f.handle(t, cro)
+	require.Equal(t, admissionregistrationv1.Fail, f.failurePolicy(t))
+	require.Equal(t, 2*time.Minute, context.GetRequeueAfter())
+	require.Equal(t, f.clock.now, current.Status.FailOpen.UnavailableSince.Time)
+	require.Nil(t, current.Status.FailOpen.Since)
+	require.Equal(t, corev1.ConditionFalse, condition.Find(&current.Status, operatorv1.Degraded).Status)
+
+	// past the grace period, the admission webhook fails open.
+	f.clock.now = f.clock.now.Add(3 * time.Minute)
+	current, _ = f.handle(t, current)
+	require.Equal(t, admissionregistrationv1.Ignore, f.failurePolicy(t))
+	require.Equal(t, f.clock.now, current.Status.FailOpen.Since.Time)
+
+	degraded := condition.Find(&current.Status, operatorv1.Degraded)
+	require.Equal(t, corev1.ConditionTrue, degraded.Status)
+	require.Equal(t, operatorv1.FailOpen, degraded.Reason)
+	require.Contains(t, degraded.Message, "2024-07-24T17:00:00Z")
+
+	// once available again, Fail is restored.
+	f.deploy.available = true
+	current, _ = f.handle(t, current)
+	require.Equal(t, admissionregistrationv1.Fail, f.failurePolicy(t))
+	require.NotContains(t, f.webhook(t).Annotations, f.handler.asset.Values().FailOpenAnnotationKey)
+	require.Nil(t, current.Status.FailOpen.UnavailableSince)
+	require.Equal(t, corev1.ConditionFalse, condition.Find(&current.Status, operatorv1.Degraded).Status)
+}
+
+func TestFailOpenHandlerDuringRollout(t *testing.T) {
+	f := newFailOpenFixture(t)
+	cro := minimalCRO()
+	cro.Spec.FailOpen = &operatorv1.FailOpenPolicy{DuringRollout: true}
+
+	current, _ := f.handle(t, cro)
+	require.Equal(t, admissionregistrationv1.Ignore, f.failurePolicy(t))
+	require.Equal(t, corev1.ConditionTrue, condition.Find(&current.Status, operatorv1.Degraded).Status)
+}
+
+func TestFailOpenHandlerDisabled(t *testing.T) {
+	f := newFailOpenFixture(t)
+	cro := minimalCRO()
+	cro.Spec.FailOpen = &operatorv1.FailOpenPolicy{DuringRollout: true}
+
+	current, _ := f.handle(t, cro)
+	require.Equal(t, admissionregistrationv1.Ignore, f.failurePolicy(t))
+
+	// disabling the policy restores Fail, even if the admission webhook is
+	// still unavailable.
+	current.Spec.FailOpen = nil
+	current, _ = f.handle(t, current)
+	require.Equal(t, admissionregistrationv1.Fail, f.failurePolicy(t))
+	require.Nil(t, current.Status.FailOpen)
+	require.Nil(t, condition.Find(&current.Status, operatorv1.Degraded))
+}
+
+func TestFailOpenHandlerRolloutWithAvailableReplicas(t *testing.T) {
+	f := newFailOpenFixture(t)
+	ctx := operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0")
+
+	// half of the replicas are updated, the old ones still serve requests.
+	deployment := f.handler.asset.Deployment().New()
+	deployment.Generation = 2
+	deployment.Spec.Replicas = ptr.To[int32](2)
+	deployment.Status = appsv1.DeploymentStatus{
+		ObservedGeneration: 2,
+		Replicas:           3,
+		UpdatedReplicas:    1,
+		AvailableReplicas:  2,
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, indexer.Add(deployment))
+	f.handler.deploy = deploy.NewDeploymentInstall(listersappsv1.NewDeploymentLister(indexer), ctx, f.handler.asset, nil)
+
+	done, _ := f.handler.deploy.IsAvailable(true)
+	require.False(t, done)
+
+	cro := minimalCRO()
+	cro.Spec.FailOpen = &operatorv1.FailOpenPolicy{DuringRollout: true}
+	current, _ := f.handle(t, cro)
+	require.Equal(t, admissionregistrationv1.Fail, f.failurePolicy(t))
+	require.Nil(t, current.Status.FailOpen.UnavailableSince)
+	require.Equal(t, corev1.ConditionFalse, condition.Find(&current.Status, operatorv1.Degraded).Status)
+
+	// with no replica available the admission webhook fails open.
+	deployment.Status.AvailableReplicas = 0
+	require.NoError(t, indexer.Update(deployment))
+	f.handle(t, current)
+	require.Equal(t, admissionregistrationv1.Ignore, f.failurePolicy(t))
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/klog/v2"
+	controllerreconciler "sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/clusterresourceoverride/internal/condition"
+)
+
+// PlatformDetector classifies the cluster the operator runs on.
+type PlatformDetector interface {
+	Detect(ctx context.Context) (*operatorv1.PlatformStatus, error)
+}
+
+// NewPlatformHandler returns a handler that detects the platform of the
+// cluster and reports it in status.platform. The deployment handler schedules
+// the admission webhook and reads the cluster TLS profile according to it.
+// The Infrastructure object is watched, a change of topology requeues the
+// ClusterResourceOverride. The detector reads it from the informer cache and
+// discovers the API groups once, a reconcile makes no API call.
+func NewPlatformHandler(o *Options) *platformHandler {
+	return &platformHandler{
+		detector: o.Platform,
+	}
+}
+
+type platformHandler struct {
+	detector PlatformDetector
+}
+
+func (p *platformHandler) Handle(ctx *ReconcileRequestContext, original *operatorv1.ClusterResourceOverride) (current *operatorv1.ClusterResourceOverride, result controllerreconciler.Result, handleErr error) {
+	current = original
+
+	platform, err := p.detector.Detect(context.TODO())
+	if err != nil {
+		handleErr = condition.NewInstallReadinessError(operatorv1.PlatformDetectionFailed, err)
+		return
+	}
+
+	if equality.Semantic.DeepEqual(current.Status.Platform, platform) {
+		return
+	}
+
+	klog.V(1).Infof("key=%s platform detected type=%s control-plane-topology=%s infrastructure-topology=%s",
+		original.Name, platform.Type, platform.ControlPlaneTopology, platform.InfrastructureTopology)
+	current.Status.Platform = platform
+	return
+}
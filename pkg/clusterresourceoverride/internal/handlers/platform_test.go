@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/clusterresourceoverride/internal/condition"
+	operatorruntime "github.com/openshift/cluster-resource-override-admission-operator/pkg/runtime"
+)
+
+type fakeDetector struct {
+	platform *operatorv1.PlatformStatus
+	err      error
+}
+
+func (f *fakeDetector) Detect(ctx context.Context) (*operatorv1.PlatformStatus, error) {
+	return f.platform, f.err
+}
+
+func TestPlatformHandler(t *testing.T) {
+	detector := &fakeDetector{platform: &operatorv1.PlatformStatus{Type: operatorv1.PlatformOpenShift, ControlPlaneTopology: "HighlyAvailable"}}
+	handler := &platformHandler{detector: detector}
+	ctx := NewReconcileRequestContext(operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0"))
+
+	current, _, err := handler.Handle(ctx, minimalCRO())
+	require.NoError(t, err)
+	require.Equal(t, operatorv1.PlatformOpenShift, current.Status.Platform.Type)
+
+	// a topology change is reported.
+	detector.platform = &operatorv1.PlatformStatus{Type: operatorv1.PlatformHyperShift, ControlPlaneTopology: "External"}
+	current, _, err = handler.Handle(ctx, current)
+	require.NoError(t, err)
+	require.Equal(t, operatorv1.PlatformHyperShift, current.Status.Platform.Type)
+
+	// a failed detection keeps the last platform detected.
+	detector.err = errors.New("discovery failed")
+	current, _, err = handler.Handle(ctx, current)
+	require.Error(t, err)
+	condition.NewBuilderWithStatus(&current.Status).WithError(err)
+	require.Equal(t, operatorv1.PlatformDetectionFailed, condition.Find(&current.Status, operatorv1.InstallReadinessFailure).Reason)
+	require.Equal(t, operatorv1.PlatformHyperShift, current.Status.Platform.Type)
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	controllerreconciler "sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/reference"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/clusterresourceoverride/internal/condition"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/ensurer"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/secondarywatch"
+)
+
+func NewResourceOverridePolicyHandler(o *Options) *resourceOverridePolicyHandler {
+	return &resourceOverridePolicyHandler{
+		client:         o.Client.Kubernetes,
+		policyEnsurer:  ensurer.NewValidatingAdmissionPolicyEnsurer(o.Client.Dynamic),
+		bindingEnsurer: ensurer.NewValidatingAdmissionPolicyBindingEnsurer(o.Client.Dynamic),
+		lister:         o.SecondaryLister,
+		asset:          o.Asset,
+	}
+}
+
+// resourceOverridePolicyHandler enforces the resourceOverridePolicy of the
+// ClusterResourceOverride with a ValidatingAdmissionPolicy. The policy and its
+// binding are removed when no resourceOverridePolicy is specified.
+type resourceOverridePolicyHandler struct {
+	client         kubernetes.Interface
+	policyEnsurer  *ensurer.ValidatingAdmissionPolicyEnsurer
+	bindingEnsurer *ensurer.ValidatingAdmissionPolicyBindingEnsurer
+	lister         *secondarywatch.Lister
+	asset          *asset.Asset
+}
+
+func (r *resourceOverridePolicyHandler) Handle(context *ReconcileRequestContext, original *operatorv1.ClusterResourceOverride) (current *operatorv1.ClusterResourceOverride, result controllerreconciler.Result, handleErr error) {
+	current = original
+
+	policy := original.Spec.ResourceOverridePolicy
+	if policy == nil || len(r.asset.NewResourceOverridePolicy().New(policy).Spec.Validations) == 0 {
+		if err := r.remove(original); err != nil {
+			handleErr = condition.NewInstallReadinessError(operatorv1.InternalError, err)
+			return
+		}
+
+		current.Status.Resources.ResourceOverridePolicyRef = nil
+		current.Status.Resources.ResourceOverridePolicyBindingRef = nil
+		return
+	}
+
+	hash := policy.Hash()
+	hashKey := r.asset.Values().PolicyHashAnnotationKey
+
+	// Ensure ValidatingAdmissionPolicy
+	policyName := r.asset.NewResourceOverridePolicy().Name()
+	policyObject, err := r.lister.AdmissionRegistrationV1ValidatingAdmissionPolicyLister().Get(policyName)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		handleErr = condition.NewInstallReadinessError(operatorv1.InternalError, err)
+		return
+	}
+
+	if err != nil || policyObject.Annotations[hashKey] != hash {
+		desired := r.asset.NewResourceOverridePolicy().New(policy)
+		context.ControllerSetter().Set(desired, original)
+
+		object, err := r.policyEnsurer.Ensure(desired)
+		if err != nil {
+			handleErr = condition.NewInstallReadinessError(operatorv1.InternalError, err)
+			return
+		}
+
+		policyObject = object
+		klog.V(2).Infof("key=%s resource=%T/%s successfully ensured", original.Name, policyObject, policyObject.Name)
+	}
+
+	if ref := original.Status.Resources.ResourceOverridePolicyRef; ref != nil && ref.ResourceVersion == policyObject.ResourceVersion {
+		klog.V(2).Infof("key=%s resource=%T/%s is in sync", original.Name, policyObject, policyObject.Name)
+	} else {
+		newRef, err := reference.GetReference(policyObject)
+		if err != nil {
+			handleErr = condition.NewInstallReadinessError(operatorv1.CannotSetReference, err)
+			return
+		}
+
+		klog.V(2).Infof("key=%s resource=%T/%s resource-version=%s setting object reference", original.Name, policyObject, policyObject.Name, newRef.ResourceVersion)
+		current.Status.Resources.ResourceOverridePolicyRef = newRef
+	}
+
+	// Ensure ValidatingAdmissionPolicyBinding
+	bindingName := r.asset.NewResourceOverridePolicyBinding().Name()
+	bindingObject, err := r.lister.AdmissionRegistrationV1ValidatingAdmissionPolicyBindingLister().Get(bindingName)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		handleErr = condition.NewInstallReadinessError(operatorv1.InternalError, err)
+		return
+	}
+
+	if err != nil || bindingObject.Annotations[hashKey] != hash {
+		desired := r.asset.NewResourceOverridePolicyBinding().New(policy)
+		context.ControllerSetter().Set(desired, original)
+
+		object, err := r.bindingEnsurer.Ensure(desired)
+		if err != nil {
+			handleErr = condition.NewInstallReadinessError(operatorv1.InternalError, err)
+			return
+		}
+
+		bindingObject = object
+		klog.V(2).Infof("key=%s resource=%T/%s successfully ensured", original.Name, bindingObject, bindingObject.Name)
+	}
+
+	if ref := original.Status.Resources.ResourceOverridePolicyBindingRef; ref != nil && ref.ResourceVersion == bindingObject.ResourceVersion {
+		klog.V(2).Infof("key=%s resource=%T/%s is in sync", original.Name, bindingObject, bindingObject.Name)
+		return
+	}
+
+	newRef, err := reference.GetReference(bindingObject)
+	if err != nil {
+		handleErr = condition.NewInstallReadinessError(operatorv1.CannotSetReference, err)
+		return
+	}
+
+	klog.V(2).Infof("key=%s resource=%T/%s resource-version=%s setting object reference", original.Name, bindingObject, bindingObject.Name, newRef.ResourceVersion)
+	current.Status.Resources.ResourceOverridePolicyBindingRef = newRef
+
+	return
+}
+
+func (r *resourceOverridePolicyHandler) remove(original *operatorv1.ClusterResourceOverride) error {
+	bindingName := r.asset.NewResourceOverridePolicyBinding().Name()
+	if _, err := r.lister.AdmissionRegistrationV1ValidatingAdmissionPolicyBindingLister().Get(bindingName); err == nil {
+		if deleteErr := r.client.AdmissionregistrationV1().ValidatingAdmissionPolicyBindings().Delete(context.TODO(), bindingName, metav1.DeleteOptions{}); deleteErr != nil && !k8serrors.IsNotFound(deleteErr) {
+			return fmt.Errorf("failed to delete ValidatingAdmissionPolicyBinding - %s", deleteErr.Error())
+		}
+
+		klog.V(2).Infof("key=%s resource=%T/%s successfully deleted", original.Name, &admissionregistrationv1.ValidatingAdmissionPolicyBinding{}, bindingName)
+	}
+
+	policyName := r.asset.NewResourceOverridePolicy().Name()
+	if _, err := r.lister.AdmissionRegistrationV1ValidatingAdmissionPolicyLister().Get(policyName); err == nil {
+		if deleteErr := r.client.AdmissionregistrationV1().ValidatingAdmissionPolicies().Delete(context.TODO(), policyName, metav1.DeleteOptions{}); deleteErr != nil && !k8serrors.IsNotFound(deleteErr) {
+			return fmt.Errorf("failed to delete ValidatingAdmissionPolicy - %s", deleteErr.Error())
+		}
+
+		klog.V(2).Infof("key=%s resource=%T/%s successfully deleted", original.Name, &admissionregistrationv1.ValidatingAdmissionPolicy{}, policyName)
+	}
+
+	return nil
+}
@@ -25,10 +25,10 @@ const (
 
 func NewServiceHandler(o *Options) *serviceHandler {
 	return &serviceHandler{
-		dynamic: ensurer.NewServiceEnsurer(o.Client.Dynamic),
+		dynamic: ensurer.NewServiceEnsurer(o.OperandClient.Dynamic),
 		lister:  o.SecondaryLister,
 		asset:   o.Asset,
-		client:  o.Client.Kubernetes,
+		client:  o.OperandClient.Kubernetes,
 	}
 }
 
@@ -44,9 +44,12 @@ func (s *serviceHandler) Handle(ctx *ReconcileRequestContext, original *operator
 
 	name := s.asset.Service().Name()
 
+	// with another certificate provider the serving secret is not issued by
+	// the service-ca operator.
+	provider := original.Spec.GetCertificateProvider()
 	secretName := s.asset.ServiceServingSecret().Name()
 	secret, err := s.lister.CoreV1SecretLister().Secrets(ctx.WebhookNamespace()).Get(secretName)
-	if err == nil {
+	if err == nil && provider == operatorv1.CertificateProviderServiceCA {
 		// make sure the secret is not the old secret with self-signed generated cert one prior to 4.17
 		value, exists := secret.Annotations[AlphaServiceNameAnnotation]
 		if !exists || (exists && value != name) { // this means the secret is still old
@@ -56,13 +59,14 @@ func (s *serviceHandler) Handle(ctx *ReconcileRequestContext, original *operator
 				return
 			}
 		}
-	} else if !k8serrors.IsNotFound(err) {
+	} else if err != nil && !k8serrors.IsNotFound(err) {
 		handleErr = condition.NewInstallReadinessError(operatorv1.CertNotAvailable, err)
 		return
 	}
 
 	desired := s.asset.Service().New()
-	ctx.ControllerSetter().Set(desired, original)
+	asset.ApplyCertificateProvider(desired, provider)
+	ctx.OperandControllerSetter(s.asset).Set(desired, original)
 
 	object, err := s.lister.CoreV1ServiceLister().Services(ctx.WebhookNamespace()).Get(name)
 	if err != nil {
@@ -79,6 +83,25 @@ func (s *serviceHandler) Handle(ctx *ReconcileRequestContext, original *operator
 
 		object = service
 		klog.V(2).Infof("key=%s resource=%T/%s successfully created", original.Name, object, object.Name)
+	} else if object.Annotations[asset.ServingCertSecretAnnotationName] != desired.Annotations[asset.ServingCertSecretAnnotationName] {
+		// the certificate provider has changed.
+		service := object.DeepCopy()
+		if value, ok := desired.Annotations[asset.ServingCertSecretAnnotationName]; ok {
+			if service.Annotations == nil {
+				service.Annotations = map[string]string{}
+			}
+			service.Annotations[asset.ServingCertSecretAnnotationName] = value
+		} else {
+			delete(service.Annotations, asset.ServingCertSecretAnnotationName)
+		}
+
+		object, err = s.client.CoreV1().Services(service.Namespace).Update(context.TODO(), service, v1.UpdateOptions{})
+		if err != nil {
+			handleErr = condition.NewInstallReadinessError(operatorv1.CertNotAvailable, err)
+			return
+		}
+
+		klog.V(2).Infof("key=%s resource=%T/%s serving certificate annotation updated for certificate provider %s", original.Name, object, object.Name, provider)
 	}
 
 	if ref := current.Status.Resources.ServiceRef; ref != nil && ref.ResourceVersion == object.ResourceVersion {
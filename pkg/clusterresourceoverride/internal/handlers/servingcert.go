@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	listerscorev1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+	controllerreconciler "sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/clusterresourceoverride/internal/condition"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/metrics"
+)
+
+const (
+	// ServingCertExpiryThreshold is how long before its expiry a serving
+	// certificate that has not been rotated raises the ServingCertExpiring
+	// condition. It is capped at a fifth of the lifetime of the certificate.
+	ServingCertExpiryThreshold = 30 * 24 * time.Hour
+)
+
+// NewServingCertHandler returns a handler that hashes the serving Secret of
+// the admission webhook into status.hash.servingCert, so that the deployment
+// handler rolls the admission webhook pods out when the certificate is
+// rotated. It also reports the validity of the certificate in
+// status.servingCert and raises the ServingCertExpiring condition when the
+// certificate is close to its expiry.
+func NewServingCertHandler(o *Options) *servingCertHandler {
+	return &servingCertHandler{
+		clock:  clock.RealClock{},
+		lister: o.SecondaryLister.CoreV1SecretLister(),
+		asset:  o.Asset,
+	}
+}
+
+type servingCertHandler struct {
+	clock  clock.PassiveClock
+	lister listerscorev1.SecretLister
+	asset  *asset.Asset
+}
+
+func (s *servingCertHandler) Handle(context *ReconcileRequestContext, original *operatorv1.ClusterResourceOverride) (current *operatorv1.ClusterResourceOverride, result controllerreconciler.Result, handleErr error) {
+	current = original
+	builder := condition.NewBuilderWithStatus(&current.Status)
+
+	name := s.asset.ServiceServingSecret().Name()
+	secret, err := s.lister.Secrets(context.WebhookNamespace()).Get(name)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			handleErr = condition.NewInstallReadinessError(operatorv1.CertNotAvailable, err)
+			return
+		}
+
+		// the service-ca operator has not issued the serving certificate yet,
+		// the Secret is watched.
+		klog.V(2).Infof("key=%s resource=%T/%s not issued yet", original.Name, secret, name)
+		return
+	}
+
+	if hash := servingCertHash(secret); hash != current.Status.Hash.ServingCert {
+		klog.V(2).Infof("key=%s resource=%T/%s serving certificate hash changed, the admission webhook is rolled out", original.Name, secret, name)
+		current.Status.Hash.ServingCert = hash
+	}
+
+	cert, err := parseServingCert(secret)
+	if err != nil {
+		klog.Warningf("key=%s resource=%T/%s %s", original.Name, secret, name, err)
+
+		current.Status.ServingCert = nil
+		s.report(builder, corev1.ConditionUnknown, operatorv1.CertNotAvailable, err.Error())
+		return
+	}
+
+	current.Status.ServingCert = &operatorv1.ServingCertStatus{
+		NotBefore: metav1.NewTime(cert.NotBefore),
+		NotAfter:  metav1.NewTime(cert.NotAfter),
+	}
+	metrics.ServingCertExpirationTimestamp.Set(float64(cert.NotAfter.Unix()))
+
+	threshold := ServingCertExpiryThreshold
+	if lifetime := cert.NotAfter.Sub(cert.NotBefore); lifetime/5 < threshold {
+		threshold = lifetime / 5
+	}
+
+	now := s.clock.Now()
+	expiry := cert.NotAfter.UTC().Format(time.RFC3339)
+	switch remaining := cert.NotAfter.Sub(now); {
+	case remaining <= 0:
+		s.report(builder, corev1.ConditionTrue, operatorv1.ServingCertExpired, fmt.Sprintf("the serving certificate in Secret %s expired at %s and has not been rotated", name, expiry))
+	case remaining <= threshold:
+		context.RequeueAfter(remaining)
+		s.report(builder, corev1.ConditionTrue, operatorv1.ServingCertNearExpiry, fmt.Sprintf("the serving certificate in Secret %s expires at %s and has not been rotated", name, expiry))
+	default:
+		context.RequeueAfter(remaining - threshold)
+		s.report(builder, corev1.ConditionFalse, "", fmt.Sprintf("the serving certificate in Secret %s expires at %s", name, expiry))
+	}
+
+	return
+}
+
+func (s *servingCertHandler) report(builder *condition.Builder, status corev1.ConditionStatus, reason, message string) {
+	builder.WithCondition(&operatorv1.ClusterResourceOverrideCondition{
+		Type:               operatorv1.ServingCertExpiring,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: metav1.NewTime(s.clock.Now()),
+	})
+}
+
+// servingCertHash returns the hash of the certificate and key of the given
+// serving Secret.
+func servingCertHash(secret *corev1.Secret) string {
+	writer := sha256.New()
+	writer.Write(secret.Data[corev1.TLSCertKey])
+	writer.Write(secret.Data[corev1.TLSPrivateKeyKey])
+	return hex.EncodeToString(writer.Sum(nil))
+}
+
+// parseServingCert returns the leaf certificate of the given serving Secret.
+func parseServingCert(secret *corev1.Secret) (*x509.Certificate, error) {
+	block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("no PEM encoded certificate found in the serving Secret")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the serving certificate - %s", err.Error())
+	}
+
+	return cert, nil
+}
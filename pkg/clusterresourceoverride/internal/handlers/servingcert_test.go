@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	listerscorev1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/clusterresourceoverride/internal/condition"
+	operatorruntime "github.com/openshift/cluster-resource-override-admission-operator/pkg/runtime"
+)
+
+// newServingSecret returns the serving Secret with a self-signed certificate
+// valid for the given period.
+func newServingSecret(t *testing.T, a *asset.Asset, notBefore, notAfter time.Time) *corev1.Secret {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "clusterresourceoverride.test-ns.svc"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	secret := a.ServiceServingSecret().New()
+	secret.Data[corev1.TLSCertKey] = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	secret.Data[corev1.TLSPrivateKeyKey] = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return secret
+}
+
+func TestServingCertHandler(t *testing.T) {
+	ctx := operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0")
+	a := asset.New(ctx)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	clock := &fakeClock{now: time.Date(2024, 7, 24, 17, 0, 0, 0, time.UTC)}
+	handler := &servingCertHandler{
+		clock:  clock,
+		lister: listerscorev1.NewSecretLister(indexer),
+		asset:  a,
+	}
+
+	// not issued yet.
+	current, _, err := handler.Handle(NewReconcileRequestContext(ctx), minimalCRO())
+	require.NoError(t, err)
+	require.Empty(t, current.Status.Hash.ServingCert)
+	require.Nil(t, current.Status.ServingCert)
+
+	notBefore := clock.now.Add(-365 * 24 * time.Hour)
+	notAfter := clock.now.Add(365 * 24 * time.Hour)
+	require.NoError(t, indexer.Add(newServingSecret(t, a, notBefore, notAfter)))
+
+	context := NewReconcileRequestContext(ctx)
+	current, _, err = handler.Handle(context, current)
+	require.NoError(t, err)
+	require.NotEmpty(t, current.Status.Hash.ServingCert)
+	require.Equal(t, notAfter.Unix(), current.Status.ServingCert.NotAfter.Unix())
+	require.Equal(t, notAfter.Sub(clock.now)-ServingCertExpiryThreshold, context.GetRequeueAfter())
+	require.Equal(t, corev1.ConditionFalse, condition.Find(&current.Status, operatorv1.ServingCertExpiring).Status)
+
+	// close to its expiry without a rotation.
+	clock.now = notAfter.Add(-24 * time.Hour)
+	current, _, err = handler.Handle(NewReconcileRequestContext(ctx), current)
+	require.NoError(t, err)
+
+	expiring := condition.Find(&current.Status, operatorv1.ServingCertExpiring)
+	require.Equal(t, corev1.ConditionTrue, expiring.Status)
+	require.Equal(t, operatorv1.ServingCertNearExpiry, expiring.Reason)
+
+	// the rotated certificate changes the hash, the admission webhook is
+	// rolled out.
+	hash := current.Status.Hash.ServingCert
+	require.NoError(t, indexer.Update(newServingSecret(t, a, clock.now, clock.now.Add(2*365*24*time.Hour))))
+	current, _, err = handler.Handle(NewReconcileRequestContext(ctx), current)
+	require.NoError(t, err)
+	require.NotEqual(t, hash, current.Status.Hash.ServingCert)
+	require.Equal(t, corev1.ConditionFalse, condition.Find(&current.Status, operatorv1.ServingCertExpiring).Status)
+}
+
+func TestServingCertHandlerShortLived(t *testing.T) {
+	ctx := operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0")
+	a := asset.New(ctx)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	clock := &fakeClock{now: time.Date(2024, 7, 24, 17, 0, 0, 0, time.UTC)}
+	handler := &servingCertHandler{clock: clock, lister: listerscorev1.NewSecretLister(indexer), asset: a}
+
+	// a 10 day certificate is expiring within its last 2 days.
+	require.NoError(t, indexer.Add(newServingSecret(t, a, clock.now, clock.now.Add(10*24*time.Hour))))
+
+	context := NewReconcileRequestContext(ctx)
+	current, _, err := handler.Handle(context, minimalCRO())
+	require.NoError(t, err)
+	require.Equal(t, 8*24*time.Hour, context.GetRequeueAfter())
+	require.Equal(t, corev1.ConditionFalse, condition.Find(&current.Status, operatorv1.ServingCertExpiring).Status)
+
+	clock.now = clock.now.Add(11 * 24 * time.Hour)
+	current, _, err = handler.Handle(NewReconcileRequestContext(ctx), current)
+	require.NoError(t, err)
+	require.Equal(t, operatorv1.ServingCertExpired, condition.Find(&current.Status, operatorv1.ServingCertExpiring).Reason)
+}
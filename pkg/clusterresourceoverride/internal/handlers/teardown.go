@@ -0,0 +1,375 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+	controllerreconciler "sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/clusterresourceoverride/internal/condition"
+)
+
+const (
+	// TeardownRequeuePeriod is how long to wait before checking again whether
+	// the object(s) of a teardown step are gone.
+	TeardownRequeuePeriod = 5 * time.Second
+)
+
+// NewTeardownHandler returns a handler that tears the admission webhook down.
+// The MutatingWebhookConfiguration goes first so that no pod is sent to an
+// admission webhook whose backend is gone, then the APIService, the Deployment,
+// the admission policies, the Service, ConfigMap and admission probe RoleBinding,
+// and the RBAC last. Each step waits for the object(s) of the previous one to be
+// gone. Once they are, the policy ConfigMap(s) published in the opted-in
+// namespaces are deleted, and the opt-in label is removed from the namespaces
+// the operator opted in itself; a label set by the user is left alone.
+func NewTeardownHandler(o *Options) *teardownHandler {
+	a := o.Asset
+	values := a.Values()
+
+	steps := []teardownStep{
+		webhookTeardownStep(a),
+		{
+			{resource: apiServiceGVR, kind: "APIService", name: a.APIService().Name()},
+		},
+		{
+			{resource: deploymentGVR, kind: "Deployment", namespace: values.Namespace, name: a.Deployment().Name()},
+			{resource: daemonSetGVR, kind: "DaemonSet", namespace: values.Namespace, name: a.DaemonSet().Name()},
+		},
+		{
+			{resource: validatingAdmissionPolicyBindingGVR, kind: "ValidatingAdmissionPolicyBinding", name: a.NewValidatingAdmissionPolicyBinding().Name()},
+			{resource: validatingAdmissionPolicyGVR, kind: "ValidatingAdmissionPolicy", name: a.NewValidatingAdmissionPolicy().Name()},
+			{resource: validatingAdmissionPolicyBindingGVR, kind: "ValidatingAdmissionPolicyBinding", name: a.NewResourceOverridePolicyBinding().Name()},
+			{resource: validatingAdmissionPolicyGVR, kind: "ValidatingAdmissionPolicy", name: a.NewResourceOverridePolicy().Name()},
+		},
+		{
+			{resource: serviceGVR, kind: "Service", namespace: values.Namespace, name: a.Service().Name()},
+			{resource: secretGVR, kind: "Secret", namespace: values.Namespace, name: a.ServiceServingSecret().Name()},
+			{resource: secretGVR, kind: "Secret", namespace: values.Namespace, name: a.CertificateAuthoritySecret().Name()},
+			{resource: certificateGVR, kind: "Certificate", namespace: values.Namespace, name: a.CertManager().ServingCertificateName()},
+			{resource: certificateGVR, kind: "Certificate", namespace: values.Namespace, name: a.CertManager().CACertificateName()},
+			{resource: issuerGVR, kind: "Issuer", namespace: values.Namespace, name: a.CertManager().CAIssuerName()},
+			{resource: issuerGVR, kind: "Issuer", namespace: values.Namespace, name: a.CertManager().SelfSignedIssuerName()},
+			{resource: configMapGVR, kind: "ConfigMap", namespace: values.Namespace, name: a.Configuration().Name()},
+			{resource: roleBindingGVR, kind: "RoleBinding", namespace: a.AdmissionProbe().Namespace(), name: a.AdmissionProbe().ClusterRoleName()},
+		},
+	}
+
+	// bindings go before the roles they refer to, the service account last.
+	rbac := teardownStep{}
+	items := a.RBAC().New()
+	for i := len(items) - 1; i >= 0; i-- {
+		object := items[i].Object
+		gvk := object.GetObjectKind().GroupVersionKind()
+		rbac = append(rbac, teardownObject{
+			resource:  gvk.GroupVersion().WithResource(items[i].Resource),
+			kind:      gvk.Kind,
+			namespace: object.GetNamespace(),
+			name:      object.GetName(),
+		})
+	}
+	steps = append(steps, rbac)
+
+	return &teardownHandler{
+		dynamic:          o.DynamicClient,
+		operand:          o.OperandClient.RawDynamic,
+		operandNamespace: values.Namespace,
+		steps:            steps,
+		values:           values,
+	}
+}
+
+// NewSuspendHandler returns a handler that removes the MutatingWebhookConfiguration
+// only, so that pods are admitted without overrides.
+func NewSuspendHandler(o *Options) *teardownHandler {
+	return &teardownHandler{
+		dynamic: o.DynamicClient,
+		operand: o.OperandClient.RawDynamic,
+		steps:   []teardownStep{webhookTeardownStep(o.Asset)},
+		suspend: true,
+		values:  o.Asset.Values(),
+	}
+}
+
+var (
+	apiServiceGVR                       = schema.GroupVersionResource{Group: "apiregistration.k8s.io", Version: "v1", Resource: "apiservices"}
+	mutatingWebhookConfigurationGVR     = schema.GroupVersionResource{Group: "admissionregistration.k8s.io", Version: "v1", Resource: "mutatingwebhookconfigurations"}
+	validatingAdmissionPolicyGVR        = schema.GroupVersionResource{Group: "admissionregistration.k8s.io", Version: "v1", Resource: "validatingadmissionpolicies"}
+	validatingAdmissionPolicyBindingGVR = schema.GroupVersionResource{Group: "admissionregistration.k8s.io", Version: "v1", Resource: "validatingadmissionpolicybindings"}
+	deploymentGVR                       = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	daemonSetGVR                        = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}
+	serviceGVR                          = schema.GroupVersionResource{Version: "v1", Resource: "services"}
+	secretGVR                           = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	configMapGVR                        = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	namespaceGVR                        = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	roleBindingGVR                      = schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "rolebindings"}
+)
+
+type teardownObject struct {
+	resource  schema.GroupVersionResource
+	kind      string
+	namespace string
+	name      string
+}
+
+func (t teardownObject) String() string {
+	if t.namespace == "" {
+		return fmt.Sprintf("%s/%s", t.kind, t.name)
+	}
+
+	return fmt.Sprintf("%s/%s/%s", t.kind, t.namespace, t.name)
+}
+
+// teardownStep is a set of object(s) deleted together.
+type teardownStep []teardownObject
+
+func webhookTeardownStep(a *asset.Asset) teardownStep {
+	return teardownStep{
+		{resource: mutatingWebhookConfigurationGVR, kind: "MutatingWebhookConfiguration", name: a.NewMutatingWebhookConfiguration().Name()},
+	}
+}
+
+type teardownHandler struct {
+	dynamic dynamic.Interface
+	steps   []teardownStep
+
+	// operand deletes the object(s) in operandNamespace, in the management
+	// cluster if the admission webhook runs in one.
+	operand          dynamic.Interface
+	operandNamespace string
+
+	// suspend reports the progress in the Available condition rather than in
+	// the Uninstalled condition.
+	suspend bool
+
+	// values holds the owner label and annotation of the object(s) created
+	// by the operator, any other object is left alone.
+	values *asset.Values
+}
+
+func (t *teardownHandler) Handle(context *ReconcileRequestContext, original *operatorv1.ClusterResourceOverride) (current *operatorv1.ClusterResourceOverride, result controllerreconciler.Result, handleErr error) {
+	current = original
+
+	for i, step := range t.steps {
+		remaining, err := t.delete(original, step)
+		if err != nil {
+			handleErr = condition.NewInstallReadinessError(operatorv1.InternalError, err)
+			return
+		}
+
+		if len(remaining) > 0 {
+			names := make([]string, 0, len(remaining))
+			for _, object := range remaining {
+				names = append(names, object.String())
+			}
+
+			message := fmt.Sprintf("step %d of %d, waiting for %s to be deleted", i+1, len(t.steps), strings.Join(names, ", "))
+			klog.V(2).Infof("key=%s teardown %s", original.Name, message)
+
+			t.report(current, corev1.ConditionFalse, TeardownInProgressReason(t.suspend), message)
+			result.RequeueAfter = TeardownRequeuePeriod
+			return
+		}
+	}
+
+	// with no webhook configuration there is nothing to fail open.
+	current.Status.Resources.MutatingWebhookConfigurationRef = nil
+	current.Status.FailOpen = nil
+	condition.NewBuilderWithStatus(&current.Status).WithoutCondition(operatorv1.Degraded)
+	if t.suspend {
+		t.report(current, corev1.ConditionFalse, operatorv1.WebhookSuspended, "the MutatingWebhookConfiguration is removed, pods are admitted without overrides")
+		return
+	}
+
+	if err := t.cleanupNamespaces(original); err != nil {
+		handleErr = condition.NewInstallReadinessError(operatorv1.InternalError, err)
+		return
+	}
+
+	current.Status.Resources = operatorv1.ClusterResourceOverrideResources{}
+	current.Status.AdmissionProbe = nil
+	current.Status.ServingCert = nil
+	condition.NewBuilderWithStatus(&current.Status).WithoutCondition(operatorv1.ServingCertExpiring)
+	current.Status.Hash = operatorv1.ClusterResourceOverrideResourceHash{}
+	current.Status.Version = ""
+	current.Status.Image = ""
+	t.report(current, corev1.ConditionTrue, operatorv1.TeardownComplete, "the admission webhook is removed")
+
+	klog.V(2).Infof("key=%s teardown complete", original.Name)
+	return
+}
+
+// TeardownInProgressReason returns the reason reported while the object(s) are
+// being deleted.
+func TeardownInProgressReason(suspend bool) string {
+	if suspend {
+		return operatorv1.WebhookSuspended
+	}
+
+	return operatorv1.TeardownInProgress
+}
+
+func (t *teardownHandler) report(current *operatorv1.ClusterResourceOverride, status corev1.ConditionStatus, reason, message string) {
+	builder := condition.NewBuilderWithStatus(&current.Status)
+	now := metav1.Now()
+
+	if t.suspend {
+		builder.WithCondition(&operatorv1.ClusterResourceOverrideCondition{
+			Type:               operatorv1.Available,
+			Status:             corev1.ConditionFalse,
+			Reason:             reason,
+			Message:            message,
+			LastTransitionTime: now,
+		})
+		return
+	}
+
+	builder.WithCondition(&operatorv1.ClusterResourceOverrideCondition{
+		Type:               operatorv1.Available,
+		Status:             corev1.ConditionFalse,
+		Reason:             reason,
+		Message:            "the admission webhook is being removed",
+		LastTransitionTime: now,
+	})
+	builder.WithCondition(&operatorv1.ClusterResourceOverrideCondition{
+		Type:               operatorv1.Uninstalled,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: now,
+	})
+}
+
+// delete deletes the object(s) of the given step, and returns those that are
+// not gone yet. An object that does not belong to the operator is left alone,
+// even if it has the name of one of the object(s) of the admission webhook.
+func (t *teardownHandler) delete(original *operatorv1.ClusterResourceOverride, step teardownStep) (remaining []teardownObject, err error) {
+	// the pods of the Deployment are deleted before the Deployment itself.
+	propagation := metav1.DeletePropagationForeground
+
+	for _, object := range step {
+		cluster := t.dynamic
+		if object.namespace != "" && object.namespace == t.operandNamespace {
+			cluster = t.operand
+		}
+		client := cluster.Resource(object.resource).Namespace(object.namespace)
+
+		current, getErr := client.Get(context.TODO(), object.name, metav1.GetOptions{})
+		if getErr != nil {
+			if k8serrors.IsNotFound(getErr) {
+				continue
+			}
+
+			err = fmt.Errorf("failed to get %s - %s", object, getErr.Error())
+			return
+		}
+
+		if !owns(t.values, original, current) {
+			klog.V(2).Infof("key=%s resource=%s is not owned by the operator, skipping", original.Name, object)
+			continue
+		}
+
+		remaining = append(remaining, object)
+		if current.GetDeletionTimestamp() != nil {
+			continue
+		}
+
+		deleteErr := client.Delete(context.TODO(), object.name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+		if deleteErr != nil && !k8serrors.IsNotFound(deleteErr) {
+			err = fmt.Errorf("failed to delete %s - %s", object, deleteErr.Error())
+			return
+		}
+
+		klog.V(2).Infof("key=%s resource=%s deleted", original.Name, object)
+	}
+
+	return
+}
+
+// cleanupNamespaces deletes the policy ConfigMap(s) and removes the opt-in label
+// the operator added to a namespace, either by autoOptIn or by translating the
+// legacy annotation. Both are marked with an annotation, a namespace without
+// one was labeled by the user and is left as is.
+func (t *teardownHandler) cleanupNamespaces(original *operatorv1.ClusterResourceOverride) error {
+	configMaps, err := t.dynamic.Resource(configMapGVR).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=true", asset.NamespacePolicyLabelKey),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list policy configmaps - %s", err.Error())
+	}
+
+	for _, cm := range configMaps.Items {
+		if cm.GetName() != asset.NamespacePolicyConfigMapName {
+			continue
+		}
+
+		deleteErr := t.dynamic.Resource(configMapGVR).Namespace(cm.GetNamespace()).Delete(context.TODO(), cm.GetName(), metav1.DeleteOptions{})
+		if deleteErr != nil && !k8serrors.IsNotFound(deleteErr) {
+			return fmt.Errorf("failed to delete policy configmap in namespace %q - %s", cm.GetNamespace(), deleteErr.Error())
+		}
+
+		klog.V(2).Infof("key=%s namespace=%s policy configmap deleted", original.Name, cm.GetNamespace())
+	}
+
+	namespaces, err := t.dynamic.Resource(namespaceGVR).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=true", asset.NamespaceOptInLabelKey),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list opted-in namespaces - %s", err.Error())
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{asset.NamespaceOptInLabelKey: nil},
+			"annotations": map[string]interface{}{
+				asset.NamespaceAutoOptInAnnotationKey:   nil,
+				asset.NamespaceLegacyOptInAnnotationKey: nil,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, ns := range namespaces.Items {
+		annotations := ns.GetAnnotations()
+		if annotations[asset.NamespaceAutoOptInAnnotationKey] != "true" && annotations[asset.NamespaceLegacyOptInAnnotationKey] != "true" {
+			continue
+		}
+
+		if _, err := t.dynamic.Resource(namespaceGVR).Patch(context.TODO(), ns.GetName(), types.MergePatchType, patch, metav1.PatchOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to patch namespace %q - %s", ns.GetName(), err.Error())
+		}
+
+		klog.V(2).Infof("key=%s namespace=%s opt-in label removed", original.Name, ns.GetName())
+	}
+
+	return nil
+}
+
+// owns returns true if the given object carries the owner label of the
+// operator, is controlled by the given ClusterResourceOverride or, in a
+// management cluster, names it in the owner annotation.
+func owns(values *asset.Values, cro *operatorv1.ClusterResourceOverride, object metav1.Object) bool {
+	if object.GetLabels()[values.OwnerLabelKey] == values.OwnerLabelValue {
+		return true
+	}
+
+	if ref := metav1.GetControllerOf(object); ref != nil && ref.Kind == operatorv1.ClusterResourceOverrideKind && ref.Name == cro.Name && ref.UID == cro.UID {
+		return true
+	}
+
+	return object.GetAnnotations()[values.OwnerAnnotationKey] == cro.Name
+}
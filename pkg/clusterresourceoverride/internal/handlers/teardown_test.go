@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/clusterresourceoverride/internal/condition"
+	operatorruntime "github.com/openshift/cluster-resource-override-admission-operator/pkg/runtime"
+	controllerreconciler "sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func newUnstructured(gvr schema.GroupVersionResource, kind, namespace, name string) *unstructured.Unstructured {
+	object := &unstructured.Unstructured{}
+	object.SetGroupVersionKind(gvr.GroupVersion().WithKind(kind))
+	object.SetNamespace(namespace)
+	object.SetName(name)
+	return object
+}
+
+// newOwnedUnstructured returns an object carrying the owner label, as created
+// by the operator.
+func newOwnedUnstructured(a *asset.Asset, gvr schema.GroupVersionResource, kind, namespace, name string) *unstructured.Unstructured {
+	object := newUnstructured(gvr, kind, namespace, name)
+	object.SetLabels(map[string]string{a.Values().OwnerLabelKey: a.Values().OwnerLabelValue})
+	return object
+}
+
+// newTeardownDynamicClient returns a fake client that can list the policy
+// ConfigMap(s) and the opted-in namespaces.
+func newTeardownDynamicClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		configMapGVR: "ConfigMapList",
+		namespaceGVR: "NamespaceList",
+	}, objects...)
+}
+
+func newTeardownOptions(objects ...runtime.Object) (*Options, *dynamicfake.FakeDynamicClient) {
+	ctx := operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0")
+	client := newTeardownDynamicClient(objects...)
+	return &Options{
+		OperandContext: ctx,
+		Asset:          asset.New(ctx),
+		DynamicClient:  client,
+		OperandClient:  &operatorruntime.Client{RawDynamic: client},
+	}, client
+}
+
+func exists(t *testing.T, client *dynamicfake.FakeDynamicClient, gvr schema.GroupVersionResource, namespace, name string) bool {
+	t.Helper()
+	_, err := client.Resource(gvr).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return false
+	}
+	require.NoError(t, err)
+	return true
+}
+
+func TestTeardownHandler(t *testing.T) {
+	a := asset.New(operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0"))
+	options, client := newTeardownOptions(
+		newOwnedUnstructured(a, mutatingWebhookConfigurationGVR, "MutatingWebhookConfiguration", "", a.NewMutatingWebhookConfiguration().Name()),
+		newOwnedUnstructured(a, apiServiceGVR, "APIService", "", a.APIService().Name()),
+		newOwnedUnstructured(a, deploymentGVR, "Deployment", "test-ns", a.Deployment().Name()),
+		newOwnedUnstructured(a, serviceGVR, "Service", "test-ns", a.Service().Name()),
+	)
+
+	handler := NewTeardownHandler(options)
+	context := NewReconcileRequestContext(options.OperandContext)
+	cro := minimalCRO()
+	cro.Status.Resources.DeploymentRef = &corev1.ObjectReference{Name: a.Deployment().Name()}
+
+	// the MutatingWebhookConfiguration goes first, the rest is left as is.
+	current, result, err := handler.Handle(context, cro)
+	require.NoError(t, err)
+	require.Equal(t, TeardownRequeuePeriod, result.RequeueAfter)
+	require.False(t, exists(t, client, mutatingWebhookConfigurationGVR, "", a.NewMutatingWebhookConfiguration().Name()))
+	require.True(t, exists(t, client, apiServiceGVR, "", a.APIService().Name()))
+	require.True(t, exists(t, client, deploymentGVR, "test-ns", a.Deployment().Name()))
+
+	uninstalled := condition.Find(&current.Status, operatorv1.Uninstalled)
+	require.NotNil(t, uninstalled)
+	require.Equal(t, corev1.ConditionFalse, uninstalled.Status)
+	require.Equal(t, operatorv1.TeardownInProgress, uninstalled.Reason)
+	require.Contains(t, uninstalled.Message, "step 1 of 6")
+
+	for i := 0; i < 10 && (i == 0 || result.RequeueAfter > 0); i++ {
+		current, result, err = handler.Handle(context, current)
+		require.NoError(t, err)
+	}
+	require.Zero(t, result.RequeueAfter)
+	require.False(t, exists(t, client, apiServiceGVR, "", a.APIService().Name()))
+	require.False(t, exists(t, client, deploymentGVR, "test-ns", a.Deployment().Name()))
+	require.False(t, exists(t, client, serviceGVR, "test-ns", a.Service().Name()))
+
+	uninstalled = condition.Find(&current.Status, operatorv1.Uninstalled)
+	require.Equal(t, corev1.ConditionTrue, uninstalled.Status)
+	require.Equal(t, operatorv1.TeardownComplete, uninstalled.Reason)
+	require.Nil(t, current.Status.Resources.DeploymentRef)
+}
+
+func TestTeardownHandlerCleansUpNamespaces(t *testing.T) {
+	policy := newUnstructured(configMapGVR, "ConfigMap", "tenant-a", asset.NamespacePolicyConfigMapName)
+	policy.SetLabels(map[string]string{asset.NamespacePolicyLabelKey: "true"})
+	tenant := newUnstructured(configMapGVR, "ConfigMap", "tenant-b", asset.NamespacePolicyConfigMapName)
+
+	newNamespace := func(name, annotation string) *unstructured.Unstructured {
+		ns := newUnstructured(namespaceGVR, "Namespace", "", name)
+		ns.SetLabels(map[string]string{asset.NamespaceOptInLabelKey: "true", "team": name})
+		if annotation != "" {
+			ns.SetAnnotations(map[string]string{annotation: "true"})
+		}
+		return ns
+	}
+
+	options, client := newTeardownOptions(
+		policy,
+		tenant,
+		newNamespace("tenant-a", asset.NamespaceAutoOptInAnnotationKey),
+		newNamespace("legacy", asset.NamespaceLegacyOptInAnnotationKey),
+		newNamespace("tenant-b", ""),
+	)
+
+	handler := NewTeardownHandler(options)
+	current, result, err := handler.Handle(NewReconcileRequestContext(options.OperandContext), minimalCRO())
+	require.NoError(t, err)
+	require.Zero(t, result.RequeueAfter)
+	require.Equal(t, operatorv1.TeardownComplete, condition.Find(&current.Status, operatorv1.Uninstalled).Reason)
+
+	// the policy ConfigMap is deleted, the one of the tenant is left alone.
+	require.False(t, exists(t, client, configMapGVR, "tenant-a", asset.NamespacePolicyConfigMapName))
+	require.True(t, exists(t, client, configMapGVR, "tenant-b", asset.NamespacePolicyConfigMapName))
+
+	// the opt-in label added by the operator is removed, the one set by the
+	// user is left alone.
+	for name, want := range map[string]string{"tenant-a": "", "legacy": "", "tenant-b": "true"} {
+		ns, err := client.Resource(namespaceGVR).Get(context.TODO(), name, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, want, ns.GetLabels()[asset.NamespaceOptInLabelKey], name)
+		require.Equal(t, name, ns.GetLabels()["team"])
+		require.Empty(t, ns.GetAnnotations()[asset.NamespaceAutoOptInAnnotationKey])
+		require.Empty(t, ns.GetAnnotations()[asset.NamespaceLegacyOptInAnnotationKey])
+	}
+}
+
+func TestTeardownHandlerLeavesForeignObjects(t *testing.T) {
+	a := asset.New(operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0"))
+	cro := minimalCRO()
+	cro.SetUID("cro-uid")
+
+	controlled := newUnstructured(deploymentGVR, "Deployment", "test-ns", a.Deployment().Name())
+	controlled.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(cro, cro.GroupVersionKind())})
+	options, client := newTeardownOptions(
+		newOwnedUnstructured(a, mutatingWebhookConfigurationGVR, "MutatingWebhookConfiguration", "", a.NewMutatingWebhookConfiguration().Name()),
+		controlled,
+		// an object of the same name, created by someone else.
+		newUnstructured(serviceGVR, "Service", "test-ns", a.Service().Name()),
+		newUnstructured(namespaceGVR, "Namespace", "", a.AdmissionProbe().Namespace()),
+	)
+
+	handler := NewTeardownHandler(options)
+	context := NewReconcileRequestContext(options.OperandContext)
+
+	current := cro
+	result := controllerreconciler.Result{}
+	for i := 0; i < 10 && (i == 0 || result.RequeueAfter > 0); i++ {
+		var err error
+		current, result, err = handler.Handle(context, current)
+		require.NoError(t, err)
+	}
+	require.Zero(t, result.RequeueAfter)
+	require.False(t, exists(t, client, mutatingWebhookConfigurationGVR, "", a.NewMutatingWebhookConfiguration().Name()))
+	require.False(t, exists(t, client, deploymentGVR, "test-ns", a.Deployment().Name()))
+	require.True(t, exists(t, client, serviceGVR, "test-ns", a.Service().Name()))
+	require.True(t, exists(t, client, namespaceGVR, "", a.AdmissionProbe().Namespace()))
+
+	uninstalled := condition.Find(&current.Status, operatorv1.Uninstalled)
+	require.NotNil(t, uninstalled)
+	require.Equal(t, corev1.ConditionTrue, uninstalled.Status)
+}
+
+func TestSuspendHandler(t *testing.T) {
+	a := asset.New(operatorruntime.NewOperandContext("clusterresourceoverride", "test-ns", "cluster", "img", "1.0"))
+	options, client := newTeardownOptions(
+		newOwnedUnstructured(a, mutatingWebhookConfigurationGVR, "MutatingWebhookConfiguration", "", a.NewMutatingWebhookConfiguration().Name()),
+		newOwnedUnstructured(a, deploymentGVR, "Deployment", "test-ns", a.Deployment().Name()),
+	)
+
+	handler := NewSuspendHandler(options)
+	context := NewReconcileRequestContext(options.OperandContext)
+	cro := minimalCRO()
+	cro.Status.Resources.MutatingWebhookConfigurationRef = &corev1.ObjectReference{Name: a.NewMutatingWebhookConfiguration().Name()}
+
+	current, result, err := handler.Handle(context, cro)
+	require.NoError(t, err)
+	require.Equal(t, TeardownRequeuePeriod, result.RequeueAfter)
+	require.False(t, exists(t, client, mutatingWebhookConfigurationGVR, "", a.NewMutatingWebhookConfiguration().Name()))
+
+	current, result, err = handler.Handle(context, current)
+	require.NoError(t, err)
+	require.Zero(t, result.RequeueAfter)
+	require.True(t, exists(t, client, deploymentGVR, "test-ns", a.Deployment().Name()))
+	require.Nil(t, current.Status.Resources.MutatingWebhookConfigurationRef)
+	require.Nil(t, condition.Find(&current.Status, operatorv1.Uninstalled))
+
+	available := condition.Find(&current.Status, operatorv1.Available)
+	require.NotNil(t, available)
+	require.Equal(t, corev1.ConditionFalse, available.Status)
+	require.Equal(t, operatorv1.WebhookSuspended, available.Reason)
+}
+
+func TestTeardownHandlerOnManagementCluster(t *testing.T) {
+	ctx := operatorruntime.NewHostedOperandContext("clusterresourceoverride", "clusters-hosted", "cluster", "img", "1.0", "clusterresourceoverride-kubeconfig")
+	a := asset.New(ctx)
+
+	// an object in the management cluster names its owner in an annotation.
+	service := newUnstructured(serviceGVR, "Service", "clusters-hosted", a.Service().Name())
+	service.SetAnnotations(map[string]string{a.Values().OwnerAnnotationKey: "cluster"})
+
+	hosted := newTeardownDynamicClient(
+		newOwnedUnstructured(a, mutatingWebhookConfigurationGVR, "MutatingWebhookConfiguration", "", a.NewMutatingWebhookConfiguration().Name()),
+	)
+	management := newTeardownDynamicClient(
+		newOwnedUnstructured(a, deploymentGVR, "Deployment", "clusters-hosted", a.Deployment().Name()),
+		service,
+	)
+
+	handler := NewTeardownHandler(&Options{
+		OperandContext: ctx,
+		Asset:          a,
+		DynamicClient:  hosted,
+		OperandClient:  &operatorruntime.Client{RawDynamic: management},
+	})
+	context := NewReconcileRequestContext(ctx)
+	current := minimalCRO()
+
+	// the MutatingWebhookConfiguration is deleted in the hosted cluster, the
+	// object(s) of the admission webhook in the management cluster.
+	for i := 0; i < 10; i++ {
+		var err error
+		current, _, err = handler.Handle(context, current)
+		require.NoError(t, err)
+	}
+
+	require.False(t, exists(t, hosted, mutatingWebhookConfigurationGVR, "", a.NewMutatingWebhookConfiguration().Name()))
+	require.False(t, exists(t, management, deploymentGVR, "clusters-hosted", a.Deployment().Name()))
+	require.False(t, exists(t, management, serviceGVR, "clusters-hosted", a.Service().Name()))
+
+	uninstalled := condition.Find(&current.Status, operatorv1.Uninstalled)
+	require.NotNil(t, uninstalled)
+	require.Equal(t, corev1.ConditionTrue, uninstalled.Status)
+}
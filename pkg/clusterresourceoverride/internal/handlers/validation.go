@@ -1,8 +1,13 @@
 package handlers
 
 import (
+	"fmt"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
 	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/clusterresourceoverride/internal/condition"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/tlsprofile"
 	controllerreconciler "sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -26,5 +31,57 @@ func (c *validationHandler) Handle(context *ReconcileRequestContext, original *o
 		handleErr = condition.NewInstallReadinessError(operatorv1.InvalidParameters, deploymentOverridesValidationErr)
 	}
 
+	if autoOptIn := original.Spec.AutoOptIn; autoOptIn != nil {
+		if autoOptInValidationErr := autoOptIn.Validate(); autoOptInValidationErr != nil {
+			handleErr = condition.NewInstallReadinessError(operatorv1.InvalidParameters, autoOptInValidationErr)
+		}
+	}
+
+	if policy := original.Spec.ResourceOverridePolicy; policy != nil {
+		if policyValidationErr := policy.Validate(); policyValidationErr != nil {
+			handleErr = condition.NewInstallReadinessError(operatorv1.InvalidParameters, policyValidationErr)
+		}
+	}
+
+	if failOpen := original.Spec.FailOpen; failOpen != nil {
+		if failOpenValidationErr := failOpen.Validate(); failOpenValidationErr != nil {
+			handleErr = condition.NewInstallReadinessError(operatorv1.InvalidParameters, failOpenValidationErr)
+		}
+	}
+
+	if certificateProviderValidationErr := original.Spec.GetCertificateProvider().Validate(); certificateProviderValidationErr != nil {
+		handleErr = condition.NewInstallReadinessError(operatorv1.InvalidParameters, certificateProviderValidationErr)
+	}
+
+	if webhookTransportValidationErr := original.Spec.GetWebhookTransport().Validate(); webhookTransportValidationErr != nil {
+		handleErr = condition.NewInstallReadinessError(operatorv1.InvalidParameters, webhookTransportValidationErr)
+	}
+
+	if profile := original.Spec.TLSSecurityProfile; profile != nil {
+		if tlsProfileValidationErr := validateTLSSecurityProfile(profile); tlsProfileValidationErr != nil {
+			handleErr = condition.NewInstallReadinessError(operatorv1.InvalidParameters, tlsProfileValidationErr)
+		}
+	}
+
 	return
 }
+
+// validateTLSSecurityProfile rejects a TLS profile of an unknown type, or one
+// with cipher(s) the admission webhook does not support, rather than serve
+// with another profile than the one asked for.
+func validateTLSSecurityProfile(profile *configv1.TLSSecurityProfile) error {
+	if _, ok := configv1.TLSProfiles[profile.Type]; !ok && profile.Type != configv1.TLSProfileCustomType {
+		return fmt.Errorf("invalid value for tlsSecurityProfile.type %q, must be one of %s, %s, %s or %s", profile.Type,
+			configv1.TLSProfileOldType, configv1.TLSProfileIntermediateType, configv1.TLSProfileModernType, configv1.TLSProfileCustomType)
+	}
+
+	_, unsupported, err := tlsprofile.FromProfile(profile)
+	if err != nil {
+		return fmt.Errorf("invalid tlsSecurityProfile - %s", err.Error())
+	}
+	if len(unsupported) > 0 {
+		return fmt.Errorf("invalid tlsSecurityProfile - unsupported cipher(s) %s", strings.Join(unsupported, ","))
+	}
+
+	return nil
+}
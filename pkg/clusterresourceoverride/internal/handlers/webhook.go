@@ -1,13 +1,20 @@
 package handlers
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+
 	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/reference"
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/clusterresourceoverride/internal/condition"
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/ensurer"
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/secondarywatch"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 	controllerreconciler "sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -17,6 +24,7 @@ func NewWebhookConfigurationHandlerHandler(o *Options) *webhookConfigurationHand
 		dynamic: ensurer.NewMutatingWebhookConfigurationEnsurer(o.Client.Dynamic),
 		lister:  o.SecondaryLister,
 		asset:   o.Asset,
+		client:  o.Client.Kubernetes,
 	}
 }
 
@@ -24,6 +32,7 @@ type webhookConfigurationHandler struct {
 	dynamic *ensurer.MutatingWebhookConfigurationEnsurer
 	lister  *secondarywatch.Lister
 	asset   *asset.Asset
+	client  kubernetes.Interface
 }
 
 func (w *webhookConfigurationHandler) Handle(context *ReconcileRequestContext, original *operatorv1.ClusterResourceOverride) (current *operatorv1.ClusterResourceOverride, result controllerreconciler.Result, handleErr error) {
@@ -43,6 +52,8 @@ func (w *webhookConfigurationHandler) Handle(context *ReconcileRequestContext, o
 
 	if ensure {
 		desired := w.asset.NewMutatingWebhookConfiguration().New()
+		w.asset.NewMutatingWebhookConfiguration().ApplyWebhookTransport(desired, w.asset.WebhookTransport(original.Spec.GetWebhookTransport()))
+		asset.ApplyCertificateProvider(desired, original.Spec.GetCertificateProvider())
 		context.ControllerSetter().Set(desired, original)
 
 		webhook, err := w.dynamic.Ensure(desired)
@@ -55,6 +66,12 @@ func (w *webhookConfigurationHandler) Handle(context *ReconcileRequestContext, o
 		klog.V(2).Infof("key=%s resource=%T/%s successfully created", original.Name, object, object.Name)
 	}
 
+	object, err = w.injectCABundle(context, original, object)
+	if err != nil {
+		handleErr = condition.NewInstallReadinessError(operatorv1.CertNotAvailable, err)
+		return
+	}
+
 	if ref := original.Status.Resources.MutatingWebhookConfigurationRef; ref != nil && ref.ResourceVersion == object.ResourceVersion {
 		klog.V(2).Infof("key=%s resource=%T/%s is in sync", original.Name, object, object.Name)
 		return
@@ -71,3 +88,94 @@ func (w *webhookConfigurationHandler) Handle(context *ReconcileRequestContext, o
 	current.Status.Resources.MutatingWebhookConfigurationRef = newRef
 	return
 }
+
+// injectCABundle sets the CA bundle on the webhooks of the given configuration.
+// With the Aggregated transport they call the admission webhook through the
+// aggregated API and verify the kube-apiserver, with the Service transport they
+// verify the serving certificate of the admission webhook. With the ServiceCA
+// provider, the service-ca operator is asked to inject it, unless the
+// admission webhook runs in a management cluster whose service-ca operator
+// issued the serving certificate.
+func (w *webhookConfigurationHandler) injectCABundle(reconcileContext *ReconcileRequestContext, original *operatorv1.ClusterResourceOverride, object *admissionregistrationv1.MutatingWebhookConfiguration) (*admissionregistrationv1.MutatingWebhookConfiguration, error) {
+	_, annotated := object.Annotations[asset.InjectCABundleAnnotationName]
+	webhook := object.DeepCopy()
+
+	if original.Spec.GetCertificateProvider() == operatorv1.CertificateProviderServiceCA && !w.asset.OnManagementCluster() {
+		if annotated {
+			return object, nil
+		}
+
+		if webhook.Annotations == nil {
+			webhook.Annotations = map[string]string{}
+		}
+		webhook.Annotations[asset.InjectCABundleAnnotationName] = "true"
+	} else {
+		bundle, err := w.caBundle(reconcileContext, original)
+		if err != nil {
+			return nil, err
+		}
+
+		injected := !annotated
+		for i := range webhook.Webhooks {
+			injected = injected && bytes.Equal(webhook.Webhooks[i].ClientConfig.CABundle, bundle)
+			webhook.Webhooks[i].ClientConfig.CABundle = bundle
+		}
+		if injected {
+			return object, nil
+		}
+
+		delete(webhook.Annotations, asset.InjectCABundleAnnotationName)
+	}
+
+	updated, err := w.client.AdmissionregistrationV1().MutatingWebhookConfigurations().Update(context.TODO(), webhook, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to inject the CA bundle into MutatingWebhookConfiguration %s - %s", webhook.Name, err.Error())
+	}
+
+	klog.V(2).Infof("key=%s resource=%T/%s CA bundle injected for certificate provider %s", original.Name, updated, updated.Name, original.Spec.GetCertificateProvider())
+	return updated, nil
+}
+
+// caBundle returns the CA bundle the webhooks verify the server they call
+// with, when the operator injects it.
+func (w *webhookConfigurationHandler) caBundle(reconcileContext *ReconcileRequestContext, original *operatorv1.ClusterResourceOverride) ([]byte, error) {
+	// the service-ca operator of the management cluster publishes its CA
+	// bundle in the control plane namespace.
+	if w.asset.OnManagementCluster() && original.Spec.GetCertificateProvider() == operatorv1.CertificateProviderServiceCA {
+		configMap, err := w.lister.CoreV1ConfigMapLister().ConfigMaps(reconcileContext.WebhookNamespace()).Get(asset.ServiceCAConfigMapName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the CA bundle from ConfigMap %s - %s", asset.ServiceCAConfigMapName, err.Error())
+		}
+
+		bundle := []byte(configMap.Data[asset.ServiceCABundleKey])
+		if len(bundle) == 0 {
+			return nil, fmt.Errorf("no %s in ConfigMap %s", asset.ServiceCABundleKey, asset.ServiceCAConfigMapName)
+		}
+		return bundle, nil
+	}
+
+	if w.asset.WebhookTransport(original.Spec.GetWebhookTransport()) == operatorv1.WebhookTransportService {
+		name := w.asset.ServiceServingSecret().Name()
+		secret, err := w.lister.CoreV1SecretLister().Secrets(reconcileContext.WebhookNamespace()).Get(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the CA bundle from Secret %s - %s", name, err.Error())
+		}
+
+		bundle := secret.Data[asset.CABundleKey]
+		if len(bundle) == 0 {
+			return nil, fmt.Errorf("no %s in Secret %s", asset.CABundleKey, name)
+		}
+		return bundle, nil
+	}
+
+	configMap, err := w.lister.CoreV1ConfigMapLister().ConfigMaps(reconcileContext.WebhookNamespace()).Get(asset.KubeRootCAConfigMapName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the CA bundle from ConfigMap %s - %s", asset.KubeRootCAConfigMapName, err.Error())
+	}
+
+	bundle := []byte(configMap.Data[asset.CABundleKey])
+	if len(bundle) == 0 {
+		return nil, fmt.Errorf("no %s in ConfigMap %s", asset.CABundleKey, asset.KubeRootCAConfigMapName)
+	}
+	return bundle, nil
+}
@@ -5,14 +5,17 @@ import (
 	"fmt"
 
 	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/clusterresourceoverride/internal/condition"
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/clusterresourceoverride/internal/handlers"
 	dynamicclient "github.com/openshift/cluster-resource-override-admission-operator/pkg/dynamic"
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/clientset/versioned"
 	operatorv1listers "github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/listers/operator/v1"
 	operatorruntime "github.com/openshift/cluster-resource-override-admission-operator/pkg/runtime"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	controllerreconciler "sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -25,23 +28,53 @@ var (
 )
 
 func NewReconciler(options *handlers.Options) *reconciler {
-	handlers := HandlerChain{
+	managed := HandlerChain{
 		handlers.NewAvailabilityHandler(options),
+		handlers.NewFailOpenHandler(options),
 		handlers.NewValidationHandler(options),
+		handlers.NewPlatformHandler(options),
 		handlers.NewConfigurationHandler(options),
 		handlers.NewServiceHandler(options),
+		handlers.NewCertificateHandler(options),
+		handlers.NewServingCertHandler(options),
 		handlers.NewDeploymentHandler(options),
 		handlers.NewDeploymentReadyHandler(options),
 		handlers.NewAPIServiceHandler(options),
 		handlers.NewWebhookConfigurationHandlerHandler(options),
 		handlers.NewValidatingAdmissionPolicyHandler(options),
+		handlers.NewResourceOverridePolicyHandler(options),
+		handlers.NewAdmissionProbeHandler(options),
 		handlers.NewAvailabilityHandler(options),
 	}
 
+	// the MutatingWebhookConfiguration is removed first, so that the kill
+	// switch works even if the admission webhook is not available.
+	suspended := HandlerChain{
+		handlers.NewSuspendHandler(options),
+		handlers.NewValidationHandler(options),
+		handlers.NewPlatformHandler(options),
+		handlers.NewConfigurationHandler(options),
+		handlers.NewServiceHandler(options),
+		handlers.NewCertificateHandler(options),
+		handlers.NewServingCertHandler(options),
+		handlers.NewDeploymentHandler(options),
+		handlers.NewDeploymentReadyHandler(options),
+		handlers.NewAPIServiceHandler(options),
+		handlers.NewValidatingAdmissionPolicyHandler(options),
+		handlers.NewResourceOverridePolicyHandler(options),
+	}
+
+	removed := HandlerChain{
+		handlers.NewTeardownHandler(options),
+	}
+
 	return &reconciler{
-		client:   options.Client.Operator,
-		lister:   options.PrimaryLister,
-		handlers: handlers,
+		client:    options.Client.Operator,
+		lister:    options.PrimaryLister,
+		handlers:  managed,
+		suspended: suspended,
+		removed:   removed,
+		finalizer: options.Asset.Values().Finalizer,
 		updater: &StatusUpdater{
 			client: options.Client.Operator,
 		},
@@ -54,6 +87,9 @@ type reconciler struct {
 	client         versioned.Interface
 	lister         operatorv1listers.ClusterResourceOverrideLister
 	handlers       HandlerChain
+	suspended      HandlerChain
+	removed        HandlerChain
+	finalizer      string
 	updater        *StatusUpdater
 	operandContext operatorruntime.OperandContext
 	dynamic        dynamicclient.Ensurer
@@ -87,7 +123,50 @@ func (r *reconciler) Reconcile(ctx context.Context, request controllerreconciler
 	copy.SetGroupVersionKind(ClusterResourceOverrideGVK)
 
 	reconcileContext := handlers.NewReconcileRequestContext(r.operandContext)
-	current, result, err := r.handlers.Handle(reconcileContext, copy)
+
+	if copy.GetDeletionTimestamp() != nil {
+		return r.finalize(reconcileContext, original, copy)
+	}
+
+	state := copy.Spec.GetManagementState()
+	if state == operatorv1.Unmanaged {
+		klog.V(2).Infof("key=%s management state is %s, skipping reconcile", request.Name, state)
+
+		copy.Status.ManagementState = state
+		err = r.updater.Update(original, copy)
+		return
+	}
+
+	if !controllerutil.ContainsFinalizer(copy, r.finalizer) {
+		controllerutil.AddFinalizer(copy, r.finalizer)
+
+		updated, updateErr := r.client.OperatorV1().ClusterResourceOverrides().Update(ctx, copy, metav1.UpdateOptions{})
+		if updateErr != nil {
+			err = fmt.Errorf("[reconciler] key=%s failed to add finalizer - %s", request.Name, updateErr.Error())
+			return
+		}
+
+		original = updated
+		copy = updated.DeepCopy()
+		copy.SetGroupVersionKind(ClusterResourceOverrideGVK)
+	}
+
+	var current *operatorv1.ClusterResourceOverride
+	switch state {
+	case operatorv1.Managed:
+		current, result, err = r.handlers.Handle(reconcileContext, copy)
+		condition.NewBuilderWithStatus(&current.Status).WithoutCondition(operatorv1.Uninstalled)
+	case operatorv1.Suspended:
+		current, result, err = r.suspended.Handle(reconcileContext, copy)
+		condition.NewBuilderWithStatus(&current.Status).WithoutCondition(operatorv1.Uninstalled)
+	case operatorv1.Removed:
+		current, result, err = r.removed.Handle(reconcileContext, copy)
+	default:
+		current = copy
+		err = condition.NewInstallReadinessError(operatorv1.InvalidParameters, fmt.Errorf("unknown management state %q", state))
+		condition.NewBuilderWithStatus(&current.Status).WithError(err)
+	}
+	current.Status.ManagementState = state
 
 	updateErr := r.updater.Update(original, current)
 	if updateErr != nil {
@@ -99,7 +178,48 @@ func (r *reconciler) Reconcile(ctx context.Context, request controllerreconciler
 		}
 
 		err = updateErr
+		return
+	}
+
+	// a handler is waiting for a deadline, such as the grace period of the
+	// failOpen policy, which the rate limited retry of an error may overshoot.
+	if after := reconcileContext.GetRequeueAfter(); after > 0 {
+		if err != nil {
+			klog.Errorf("[reconciler] key=%s reconciliation error, requeue after %s - %s", request.Name, after, err.Error())
+			err = nil
+			result = controllerreconciler.Result{RequeueAfter: after}
+			return
+		}
+
+		if result.RequeueAfter == 0 || after < result.RequeueAfter {
+			result.RequeueAfter = after
+		}
+	}
+
+	return
+}
+
+// finalize tears the admission webhook down before the ClusterResourceOverride
+// is deleted, and removes the finalizer once done.
+func (r *reconciler) finalize(reconcileContext *handlers.ReconcileRequestContext, original, copy *operatorv1.ClusterResourceOverride) (result controllerreconciler.Result, err error) {
+	if !controllerutil.ContainsFinalizer(copy, r.finalizer) {
+		return
+	}
+
+	current, result, err := r.removed.Handle(reconcileContext, copy)
+	if err != nil || result.Requeue || result.RequeueAfter > 0 {
+		if updateErr := r.updater.Update(original, current); updateErr != nil {
+			klog.Errorf("[reconciler] key=%s failed to update status - %s", original.Name, updateErr.Error())
+		}
+		return
+	}
+
+	controllerutil.RemoveFinalizer(current, r.finalizer)
+	if _, updateErr := r.client.OperatorV1().ClusterResourceOverrides().Update(context.TODO(), current, metav1.UpdateOptions{}); updateErr != nil {
+		err = fmt.Errorf("[reconciler] key=%s failed to remove finalizer - %s", original.Name, updateErr.Error())
+		return
 	}
 
+	klog.V(2).Infof("key=%s admission webhook torn down, finalizer removed", original.Name)
 	return
 }
@@ -0,0 +1,148 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/clusterresourceoverride/internal/handlers"
+	operatorruntime "github.com/openshift/cluster-resource-override-admission-operator/pkg/runtime"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/tlsprofile"
+)
+
+// Options configures Render.
+type Options struct {
+	ClusterResourceOverride *operatorv1.ClusterResourceOverride
+
+	// Name and Namespace of the admission webhook.
+	Name      string
+	Namespace string
+
+	OperandImage   string
+	OperandVersion string
+
+	// TLSArgs is the TLS profile passed to the admission webhook, empty for
+	// the defaults of the admission webhook. The tlsSecurityProfile of the
+	// ClusterResourceOverride takes precedence.
+	TLSArgs tlsprofile.Args
+
+	// IsStandalone schedules the admission webhook on control-plane nodes,
+	// unless the ClusterResourceOverride specifies a nodeSelector.
+	IsStandalone bool
+}
+
+// Render returns the object(s) the operator creates for the given
+// ClusterResourceOverride, built the same way the operator builds them, in
+// the order they are created.
+//
+// Owner references are dropped, the ClusterResourceOverride has no UID until
+// it is created. So are the serving certificate Secret and the CA bundles,
+// they are issued at runtime by the certificate provider.
+func Render(options *Options) ([]runtime.Object, error) {
+	if options.ClusterResourceOverride == nil {
+		return nil, errors.New("no ClusterResourceOverride given")
+	}
+	if options.OperandImage == "" || options.OperandVersion == "" {
+		return nil, errors.New("operand image and version must be specified")
+	}
+
+	cro := options.ClusterResourceOverride.DeepCopy()
+	context := handlers.NewReconcileRequestContext(operatorruntime.NewOperandContext(options.Name, options.Namespace, cro.Name, options.OperandImage, options.OperandVersion))
+	handlerOptions := &handlers.Options{
+		OperandContext: context.OperandContext,
+		Client:         &operatorruntime.Client{},
+		OperandClient:  &operatorruntime.Client{},
+		Asset:          asset.New(context.OperandContext),
+		IsStandalone:   options.IsStandalone,
+	}
+	a := handlerOptions.Asset
+
+	if err := Validate(cro); err != nil {
+		return nil, fmt.Errorf("invalid ClusterResourceOverride - %s", err.Error())
+	}
+
+	// the configuration handler records the hash the deployment is annotated with.
+	cro.Status.Hash.Configuration = cro.Spec.Hash()
+
+	transport := a.WebhookTransport(cro.Spec.GetWebhookTransport())
+	objects := make([]runtime.Object, 0)
+	for _, item := range a.RBAC().New() {
+		if item.Aggregated && transport == operatorv1.WebhookTransportService {
+			continue
+		}
+		objects = append(objects, item.Object)
+	}
+
+	configuration, err := handlers.NewConfigurationHandler(handlerOptions).NewConfiguration(context, cro)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build configuration - %s", err.Error())
+	}
+	provider := cro.Spec.GetCertificateProvider()
+	service := a.Service().New()
+	asset.ApplyCertificateProvider(service, provider)
+	objects = append(objects, configuration, service)
+
+	if provider == operatorv1.CertificateProviderCertManager {
+		for _, object := range a.CertManager().New() {
+			objects = append(objects, object)
+		}
+	}
+
+	tlsArgs := options.TLSArgs
+	if profile := cro.Spec.TLSSecurityProfile; profile != nil {
+		// the profile has been validated.
+		if tlsArgs, _, err = tlsprofile.FromProfile(profile); err != nil {
+			return nil, fmt.Errorf("failed to build TLS profile - %s", err.Error())
+		}
+	}
+
+	deployment := handlers.NewDeploymentHandler(handlerOptions)
+	desired := a.Deployment().New()
+	deployment.ApplyToDeploymentObject(context, cro, tlsArgs).Apply(desired)
+	deployment.ApplyToToPodTemplate(context, cro, tlsArgs).Apply(&desired.Spec.Template)
+	objects = append(objects, desired)
+
+	if transport == operatorv1.WebhookTransportAggregated {
+		apiservice := a.APIService().New()
+		asset.ApplyCertificateProvider(apiservice, provider)
+		objects = append(objects, apiservice)
+	}
+
+	webhook := a.NewMutatingWebhookConfiguration().New()
+	a.NewMutatingWebhookConfiguration().ApplyWebhookTransport(webhook, transport)
+	asset.ApplyCertificateProvider(webhook, provider)
+
+	objects = append(objects,
+		webhook,
+		a.NewValidatingAdmissionPolicy().New(),
+		a.NewValidatingAdmissionPolicyBinding().New(),
+	)
+
+	if policy := cro.Spec.ResourceOverridePolicy; policy != nil {
+		// an empty policy is not enforced, see the resourceOverridePolicy handler.
+		if desired := a.NewResourceOverridePolicy().New(policy); len(desired.Spec.Validations) > 0 {
+			objects = append(objects, desired, a.NewResourceOverridePolicyBinding().New(policy))
+		}
+	}
+
+	for _, object := range objects {
+		accessor, err := meta.Accessor(object)
+		if err != nil {
+			return nil, err
+		}
+		accessor.SetOwnerReferences(nil)
+	}
+
+	return objects, nil
+}
+
+// Validate returns the error the operator reports for the spec of the given
+// ClusterResourceOverride, nil if it is valid.
+func Validate(cro *operatorv1.ClusterResourceOverride) error {
+	_, _, err := handlers.NewValidationHandler(&handlers.Options{}).Handle(nil, cro)
+	return err
+}
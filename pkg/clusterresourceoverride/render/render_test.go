@@ -0,0 +1,168 @@
+package render
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/manifest"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/tlsprofile"
+)
+
+func newOptions() *Options {
+	replicas := int32(3)
+	return &Options{
+		ClusterResourceOverride: &operatorv1.ClusterResourceOverride{
+			TypeMeta:   metav1.TypeMeta{Kind: "ClusterResourceOverride", APIVersion: operatorv1.SchemeGroupVersion.String()},
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Spec: operatorv1.ClusterResourceOverrideSpec{
+				PodResourceOverride: operatorv1.PodResourceOverride{
+					Spec: operatorv1.PodResourceOverrideSpec{MemoryRequestToLimitPercent: 50},
+				},
+				DeploymentOverrides: operatorv1.DeploymentOverrides{Replicas: &replicas},
+			},
+		},
+		Name:           "clusterresourceoverride",
+		Namespace:      "clusterresourceoverride-operator",
+		OperandImage:   "quay.io/openshift/clusterresourceoverride:latest",
+		OperandVersion: "1.0.0",
+		TLSArgs:        tlsprofile.Args{MinVersion: "VersionTLS12"},
+		IsStandalone:   true,
+	}
+}
+
+func TestRender(t *testing.T) {
+	objects, err := Render(newOptions())
+	require.NoError(t, err)
+
+	kinds := make([]string, 0, len(objects))
+	for _, object := range objects {
+		kinds = append(kinds, object.GetObjectKind().GroupVersionKind().Kind)
+
+		accessor, err := meta.Accessor(object)
+		require.NoError(t, err)
+		require.Empty(t, accessor.GetOwnerReferences())
+	}
+	require.Contains(t, kinds, "ServiceAccount")
+	require.Contains(t, kinds, "APIService")
+	require.Contains(t, kinds, "MutatingWebhookConfiguration")
+	require.NotContains(t, kinds, "Secret")
+
+	configuration := find[*corev1.ConfigMap](t, objects)
+	require.Equal(t, "spec:\n  forceSelinuxRelabel: false\n  memoryRequestToLimitPercent: 50\n", configuration.Data["configuration.yaml"])
+
+	deployment := find[*appsv1.Deployment](t, objects)
+	require.Equal(t, int32(3), *deployment.Spec.Replicas)
+	require.Equal(t, "quay.io/openshift/clusterresourceoverride:latest", deployment.Spec.Template.Spec.Containers[0].Image)
+	require.Equal(t, newOptions().ClusterResourceOverride.Spec.Hash(), deployment.Annotations["clusterresourceoverride.operator.autoscaling.openshift.io/configuration.hash"])
+	require.Equal(t, "cluster", deployment.Spec.Template.Annotations["clusterresourceoverride.operator.autoscaling.openshift.io/owner"])
+	require.Contains(t, deployment.Spec.Template.Spec.Containers[0].Args, "--tls-min-version=VersionTLS12")
+	require.Equal(t, map[string]string{"node-role.kubernetes.io/control-plane": ""}, deployment.Spec.Template.Spec.NodeSelector)
+}
+
+func TestRenderWithResourceOverridePolicy(t *testing.T) {
+	options := newOptions()
+	options.ClusterResourceOverride.Spec.ResourceOverridePolicy = &operatorv1.ResourceOverridePolicy{
+		MemoryRequestToLimitPercent: &operatorv1.PercentRange{Min: 25, Max: 75},
+	}
+
+	objects, err := Render(options)
+	require.NoError(t, err)
+
+	policies := 0
+	for _, object := range objects {
+		if _, ok := object.(*admissionregistrationv1.ValidatingAdmissionPolicy); ok {
+			policies++
+		}
+	}
+	require.Equal(t, 2, policies)
+}
+
+func TestRenderWithCertManager(t *testing.T) {
+	options := newOptions()
+	options.ClusterResourceOverride.Spec.CertificateProvider = operatorv1.CertificateProviderCertManager
+
+	objects, err := Render(options)
+	require.NoError(t, err)
+
+	kinds := map[string]int{}
+	for _, object := range objects {
+		kinds[object.GetObjectKind().GroupVersionKind().Kind]++
+	}
+	require.Equal(t, 2, kinds["Issuer"])
+	require.Equal(t, 2, kinds["Certificate"])
+
+	service := find[*corev1.Service](t, objects)
+	require.Empty(t, service.Annotations)
+	require.Empty(t, find[*admissionregistrationv1.MutatingWebhookConfiguration](t, objects).Annotations)
+
+	buffer := &bytes.Buffer{}
+	require.NoError(t, manifest.Write(buffer, objects...))
+	require.Contains(t, buffer.String(), "secretName: server-serving-cert-clusterresourceoverride")
+}
+
+func TestRenderWithServiceWebhookTransport(t *testing.T) {
+	options := newOptions()
+	options.ClusterResourceOverride.Spec.WebhookTransport = operatorv1.WebhookTransportService
+
+	objects, err := Render(options)
+	require.NoError(t, err)
+
+	for _, object := range objects {
+		kind := object.GetObjectKind().GroupVersionKind().Kind
+		require.NotEqual(t, "APIService", kind)
+		require.NotEqual(t, "RoleBinding", kind, "the aggregation RBAC is not rendered")
+	}
+
+	webhook := find[*admissionregistrationv1.MutatingWebhookConfiguration](t, objects)
+	service := webhook.Webhooks[0].ClientConfig.Service
+	require.Equal(t, "clusterresourceoverride", service.Name)
+	require.Equal(t, "clusterresourceoverride-operator", service.Namespace)
+	require.Equal(t, int32(443), *service.Port)
+}
+
+func TestRenderIsDeterministic(t *testing.T) {
+	write := func() string {
+		objects, err := Render(newOptions())
+		require.NoError(t, err)
+
+		buffer := &bytes.Buffer{}
+		require.NoError(t, manifest.Write(buffer, objects...))
+		return buffer.String()
+	}
+
+	require.Equal(t, write(), write())
+}
+
+func TestRenderRejectsInvalidClusterResourceOverride(t *testing.T) {
+	options := newOptions()
+	options.ClusterResourceOverride.Spec.PodResourceOverride.Spec.MemoryRequestToLimitPercent = 200
+
+	_, err := Render(options)
+	require.Error(t, err)
+
+	options = newOptions()
+	options.OperandImage = ""
+	_, err = Render(options)
+	require.EqualError(t, err, "operand image and version must be specified")
+}
+
+func find[T runtime.Object](t *testing.T, objects []runtime.Object) T {
+	for _, object := range objects {
+		if typed, ok := object.(T); ok {
+			return typed
+		}
+	}
+
+	var zero T
+	t.Fatalf("no %T rendered", zero)
+	return zero
+}
@@ -0,0 +1,104 @@
+package operator
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/diagnose"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/operator"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/runtime"
+)
+
+const (
+	DefaultDiagnoseOutput = "clusterresourceoverride-diagnose.tar.gz"
+)
+
+func NewDiagnoseCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "diagnose",
+		Short: "Collect a support bundle of the ClusterResourceOverride admission webhook",
+		Long: `diagnose collects the ClusterResourceOverride and ResourceOverride objects, the
+objects referenced in the ClusterResourceOverride status, the admission webhook
+Deployment, pods and logs, the APIService and MutatingWebhookConfiguration, the
+cluster TLS profile and the namespace opt-in labels into a tarball.
+
+A health summary flagging common failures, such as a missing serving certificate
+Secret or an unavailable APIService, is written to stdout and into the tarball.
+The key material of the serving certificate Secret is never collected.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDiagnose(cmd, cmd.OutOrStdout())
+		},
+	}
+
+	command.Flags().String("kubeconfig", "", "absolute path to kubeconfig file")
+	command.Flags().String("namespace", DefaultOperandNamespace, "namespace the operand is deployed in")
+	command.Flags().Int64("tail", 1000, "number of log lines collected from each operand container, all if 0")
+	command.Flags().StringP("output", "o", DefaultDiagnoseOutput, "file to write the tarball to")
+
+	return command
+}
+
+func runDiagnose(command *cobra.Command, stdout io.Writer) error {
+	flags := command.Flags()
+
+	kubeconfig, err := flags.GetString("kubeconfig")
+	if err != nil {
+		return err
+	}
+	namespace, err := flags.GetString("namespace")
+	if err != nil {
+		return err
+	}
+	tail, err := flags.GetInt64("tail")
+	if err != nil {
+		return err
+	}
+	output, err := flags.GetString("output")
+	if err != nil {
+		return err
+	}
+
+	restConfig, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return fmt.Errorf("error building kubeconfig: %s", err.Error())
+	}
+
+	client, err := runtime.NewClient(restConfig)
+	if err != nil {
+		return err
+	}
+
+	report := diagnose.Collect(command.Context(), &diagnose.Options{
+		Client:         client,
+		OperandContext: runtime.NewOperandContext(OperatorName, namespace, operator.DefaultCR, "", ""),
+		TailLines:      tail,
+	})
+
+	file, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create %s - %s", output, err.Error())
+	}
+	defer file.Close()
+
+	directory := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(output), ".gz"), ".tar")
+	if err := report.WriteTarball(file, directory); err != nil {
+		return fmt.Errorf("failed to write %s - %s", output, err.Error())
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write %s - %s", output, err.Error())
+	}
+
+	if err := report.WriteSummary(stdout); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(stdout, "The support bundle is written to %s\n", output)
+	return err
+}
@@ -0,0 +1,94 @@
+package operator
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/operator"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/resourceoverride/lint"
+)
+
+func NewLintCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "lint",
+		Short: "Check ClusterResourceOverride and ResourceOverride manifests",
+		Long: `lint reads ClusterResourceOverride, ResourceOverride and Namespace objects from
+manifest files, or the .yaml, .yml and .json files of directories, and runs the
+checks the operator runs on them, so that an invalid spec is caught before it is
+applied. Other kinds are skipped.
+
+It also checks for duplicate objects, ResourceOverride(s) whose podSelector
+overlaps with another one taking precedence, ResourceOverride(s) in system
+namespaces and, for the Namespace objects given, ResourceOverride(s) in namespaces
+that are not opted in.
+
+The findings are written as JSON, or as a SARIF log for code scanning tools. The
+command fails if an error is found, or a warning with --strict.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLint(cmd, cmd.OutOrStdout())
+		},
+	}
+
+	command.Flags().StringArrayP("filename", "f", nil, "path to a manifest or a directory of manifests, may be repeated")
+	command.Flags().StringP("output", "o", "json", "output format, json or sarif")
+	command.Flags().Bool("strict", false, "fail on warnings too")
+	command.MarkFlagRequired("filename")
+
+	return command
+}
+
+func runLint(command *cobra.Command, stdout io.Writer) error {
+	flags := command.Flags()
+
+	paths, err := flags.GetStringArray("filename")
+	if err != nil {
+		return err
+	}
+
+	output, err := flags.GetString("output")
+	if err != nil {
+		return err
+	}
+	write := lint.WriteJSON
+	switch output {
+	case "json":
+	case "sarif":
+		write = lint.WriteSARIF
+	default:
+		return fmt.Errorf("unsupported output format %q", output)
+	}
+
+	strict, err := flags.GetBool("strict")
+	if err != nil {
+		return err
+	}
+
+	input, err := lint.Load(paths...)
+	if err != nil {
+		return fmt.Errorf("failed to read manifest - %s", err.Error())
+	}
+
+	findings := lint.Lint(input, operator.DefaultCR)
+	if err := write(stdout, findings); err != nil {
+		return err
+	}
+
+	errors, warnings := 0, 0
+	for _, finding := range findings {
+		if finding.Severity == lint.SeverityError {
+			errors++
+			continue
+		}
+		warnings++
+	}
+
+	if errors > 0 || (strict && warnings > 0) {
+		return fmt.Errorf("%d error(s) and %d warning(s) found", errors, warnings)
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package operator
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/manifest"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/migrate"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/operator"
+)
+
+func NewMigrateCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "migrate",
+		Short: "Convert an OpenShift 3 ClusterResourceOverride plugin configuration",
+		Long: `migrate reads the ClusterResourceOverride admission plugin configuration of an
+OpenShift 3 master-config.yaml and writes the equivalent ClusterResourceOverride.
+
+The plugin applied to every project unless it was annotated with
+quota.openshift.io/cluster-resource-override-enabled: "false", whereas the operator
+only applies to opted-in namespaces. Given namespace or project dumps, migrate also
+writes the opt-in label of each namespace the plugin applied to. The plugin had no
+per-project ratios, so no ResourceOverride is written; create them afterwards to
+tune individual namespaces.
+
+Warnings about what could not be carried over are written to stderr.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMigrate(cmd, cmd.OutOrStdout(), cmd.ErrOrStderr())
+		},
+	}
+
+	command.Flags().String("master-config", "", "path to the OpenShift 3 master-config.yaml")
+	command.Flags().StringArray("namespaces", nil, "path to a namespace or project dump (oc get projects -o yaml), may be repeated")
+	command.Flags().StringP("output", "o", "", "file to write the manifests to, stdout if empty")
+	command.MarkFlagRequired("master-config")
+
+	return command
+}
+
+func runMigrate(command *cobra.Command, stdout, stderr io.Writer) error {
+	masterConfigPath, err := command.Flags().GetString("master-config")
+	if err != nil {
+		return err
+	}
+
+	namespacePaths, err := command.Flags().GetStringArray("namespaces")
+	if err != nil {
+		return err
+	}
+
+	output, err := command.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+
+	masterConfig, err := os.ReadFile(masterConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to read master configuration - %s", err.Error())
+	}
+
+	options := &migrate.Options{
+		Name:         operator.DefaultCR,
+		MasterConfig: masterConfig,
+		ReadFile: func(path string) ([]byte, error) {
+			// a relative location is relative to the master configuration.
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(filepath.Dir(masterConfigPath), path)
+			}
+			return os.ReadFile(path)
+		},
+	}
+
+	for _, path := range namespacePaths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read namespaces - %s", err.Error())
+		}
+
+		namespaces, err := migrate.ReadNamespaces(data)
+		if err != nil {
+			return fmt.Errorf("%s: %s", path, err.Error())
+		}
+		options.Namespaces = append(options.Namespaces, namespaces...)
+	}
+
+	result, err := migrate.Migrate(options)
+	if err != nil {
+		return err
+	}
+
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(stderr, "warning: %s\n", warning)
+	}
+
+	objects := []runtime.Object{result.ClusterResourceOverride}
+	for _, ns := range result.Namespaces {
+		objects = append(objects, ns)
+	}
+
+	return writeManifests(output, stdout, objects...)
+}
+
+// writeManifests writes the given object(s) as a multi-document YAML stream to
+// the output file, or to stdout if it is empty.
+func writeManifests(output string, stdout io.Writer, objects ...runtime.Object) error {
+	if output == "" {
+		return manifest.Write(stdout, objects...)
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := manifest.Write(file, objects...); err != nil {
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,118 @@
+package operator
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/clusterresourceoverride/render"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/tlsprofile"
+)
+
+const (
+	DefaultOperandNamespace = "openshift-cluster-resource-override"
+)
+
+func NewRenderCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "render",
+		Short: "Write the manifests the operator creates for a ClusterResourceOverride",
+		Long: `render reads a ClusterResourceOverride and writes the RBAC, ConfigMap, Service,
+Deployment, APIService, MutatingWebhookConfiguration and ValidatingAdmissionPolicy
+objects the operator would create for it, as a multi-document YAML stream. The
+output is deterministic, it can be reviewed or diffed between versions, and applied
+without the operator.
+
+Owner references are not written, and neither is the serving certificate Secret,
+which is issued by the service CA operator.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRender(cmd, cmd.OutOrStdout())
+		},
+	}
+
+	command.Flags().StringP("filename", "f", "", "path to the ClusterResourceOverride")
+	command.Flags().String("image", "", fmt.Sprintf("operand image, defaults to $%s", OperandImageEnvName))
+	command.Flags().String("version", "", fmt.Sprintf("operand version, defaults to $%s", OperandVersionEnvName))
+	command.Flags().String("namespace", DefaultOperandNamespace, "namespace the operand is deployed in")
+	command.Flags().Bool("standalone", false, "schedule the operand on control-plane nodes, as on a standalone cluster")
+	command.Flags().String("tls-min-version", "", "minimum TLS version of the operand, the operand default if empty")
+	command.Flags().String("tls-cipher-suites", "", "comma-separated TLS cipher suites of the operand, the operand default if empty")
+	command.Flags().StringP("output", "o", "", "file to write the manifests to, stdout if empty")
+	command.MarkFlagRequired("filename")
+
+	return command
+}
+
+func runRender(command *cobra.Command, stdout io.Writer) error {
+	flags := command.Flags()
+	options := &render.Options{
+		Name: OperatorName,
+	}
+
+	path, err := flags.GetString("filename")
+	if err != nil {
+		return err
+	}
+
+	if options.OperandImage, err = flags.GetString("image"); err != nil {
+		return err
+	}
+	if options.OperandImage == "" {
+		options.OperandImage = os.Getenv(OperandImageEnvName)
+	}
+
+	if options.OperandVersion, err = flags.GetString("version"); err != nil {
+		return err
+	}
+	if options.OperandVersion == "" {
+		options.OperandVersion = os.Getenv(OperandVersionEnvName)
+	}
+
+	if options.Namespace, err = flags.GetString("namespace"); err != nil {
+		return err
+	}
+	if options.IsStandalone, err = flags.GetBool("standalone"); err != nil {
+		return err
+	}
+
+	tlsArgs := tlsprofile.Args{}
+	if tlsArgs.MinVersion, err = flags.GetString("tls-min-version"); err != nil {
+		return err
+	}
+	if tlsArgs.CipherSuites, err = flags.GetString("tls-cipher-suites"); err != nil {
+		return err
+	}
+	options.TLSArgs = tlsArgs
+
+	output, err := flags.GetString("output")
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read ClusterResourceOverride - %s", err.Error())
+	}
+
+	cro := &operatorv1.ClusterResourceOverride{}
+	if err := yaml.Unmarshal(data, cro); err != nil {
+		return fmt.Errorf("failed to parse ClusterResourceOverride - %s", err.Error())
+	}
+	if cro.Kind != "ClusterResourceOverride" {
+		return fmt.Errorf("%s: expected a ClusterResourceOverride, found %q", path, cro.Kind)
+	}
+	options.ClusterResourceOverride = cro
+
+	objects, err := render.Render(options)
+	if err != nil {
+		return err
+	}
+
+	return writeManifests(output, stdout, objects...)
+}
@@ -0,0 +1,78 @@
+package operator
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/resourceoverride/simulate"
+)
+
+func NewSimulateCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "simulate",
+		Short: "Show how the admission webhook would override the resources of a pod",
+		Long: `simulate reads a Pod or a workload (Deployment, StatefulSet, DaemonSet, ReplicaSet,
+ReplicationController, Job or CronJob), a ClusterResourceOverride and, optionally,
+ResourceOverride, LimitRange and Namespace objects, and prints the resources each
+container of the pod would be admitted with. Each value is explained by the rule
+that produced it.
+
+The pod is first defaulted by the API server and the LimitRanger admission plugin,
+then the ResourceOverride selecting the pod, if any, is merged with the
+ClusterResourceOverride. If the namespace of the pod is given, it must be opted in.
+Objects without a namespace are taken to be in the namespace of the pod.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSimulate(cmd, cmd.OutOrStdout())
+		},
+	}
+
+	command.Flags().StringArrayP("filename", "f", nil, "path to a manifest, may be repeated")
+	command.Flags().StringP("output", "o", "", "output format, empty for a report or yaml for the admitted pod")
+	command.MarkFlagRequired("filename")
+
+	return command
+}
+
+func runSimulate(command *cobra.Command, stdout io.Writer) error {
+	paths, err := command.Flags().GetStringArray("filename")
+	if err != nil {
+		return err
+	}
+
+	output, err := command.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+	if output != "" && output != "yaml" {
+		return fmt.Errorf("unsupported output format %q", output)
+	}
+
+	input := &simulate.Input{}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read manifest - %s", err.Error())
+		}
+
+		if err := input.Read(data); err != nil {
+			return fmt.Errorf("%s: %s", path, err.Error())
+		}
+	}
+
+	result, err := simulate.Simulate(input)
+	if err != nil {
+		return err
+	}
+
+	if output == "yaml" {
+		result.Pod.APIVersion, result.Pod.Kind = "v1", "Pod"
+		return writeManifests("", stdout, result.Pod)
+	}
+
+	return result.Write(stdout)
+}
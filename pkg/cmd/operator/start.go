@@ -2,6 +2,7 @@ package operator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/spf13/cobra"
 	"k8s.io/apiserver/pkg/server"
@@ -36,6 +37,9 @@ func NewStartCommand() *cobra.Command {
 
 	command.Flags().String("kubeconfig", "", "absolute path to kubeconfig file")
 	command.Flags().String("namespace", "", "operator namespace")
+	command.Flags().String("management-kubeconfig", "", "absolute path to the kubeconfig file of the management cluster, runs the operand in the control plane namespace of the hosted cluster --kubeconfig points to")
+	command.Flags().String("management-namespace", "", "control plane namespace of the hosted cluster in the management cluster")
+	command.Flags().String("hosted-kubeconfig-secret", "", "Secret in the management namespace with the kubeconfig the operand reaches the hosted cluster with, required with --management-kubeconfig")
 
 	return command
 }
@@ -69,12 +73,18 @@ func load(command *cobra.Command) (config *operator.Config, err error) {
 		return
 	}
 
+	management, err := loadManagement(command)
+	if err != nil {
+		return
+	}
+
 	c := &operator.Config{
 		Namespace:      namespace,
 		Name:           OperatorName,
 		RestConfig:     restConfig,
 		OperandImage:   operandImage,
 		OperandVersion: operandVersion,
+		Management:     management,
 	}
 	if validationError := c.Validate(); validationError != nil {
 		err = fmt.Errorf("invalid configuration: %s", validationError.Error())
@@ -85,6 +95,56 @@ func load(command *cobra.Command) (config *operator.Config, err error) {
 	return
 }
 
+// loadManagement returns the configuration of the management cluster the
+// operand runs in, nil if no management kubeconfig is specified.
+func loadManagement(command *cobra.Command) (config *operator.ManagementConfig, err error) {
+	kubeconfig, err := command.Flags().GetString("management-kubeconfig")
+	if err != nil {
+		return
+	}
+
+	namespace, err := command.Flags().GetString("management-namespace")
+	if err != nil {
+		return
+	}
+
+	secret, err := command.Flags().GetString("hosted-kubeconfig-secret")
+	if err != nil {
+		return
+	}
+
+	if kubeconfig == "" {
+		switch {
+		case namespace != "":
+			err = errors.New("--management-namespace requires --management-kubeconfig")
+		case secret != "":
+			err = errors.New("--hosted-kubeconfig-secret requires --management-kubeconfig")
+		}
+		return
+	}
+
+	// there is no default, a kubeconfig of the control plane such as
+	// clusterresourceoverride-kubeconfig is far more privileged than the
+	// operand needs.
+	if secret == "" {
+		err = errors.New("--management-kubeconfig requires --hosted-kubeconfig-secret")
+		return
+	}
+
+	restConfig, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		err = fmt.Errorf("error building management kubeconfig: %s", err.Error())
+		return
+	}
+
+	config = &operator.ManagementConfig{
+		RestConfig:             restConfig,
+		Namespace:              namespace,
+		HostedKubeconfigSecret: secret,
+	}
+	return
+}
+
 func run(config *operator.Config) {
 	shutdown, cancel := context.WithCancel(context.TODO())
 	config.ShutdownContext = shutdown
@@ -42,6 +42,19 @@ func (d *deployment) IsAvailable(checkGeneration bool) (available bool, err erro
 	return
 }
 
+// HasAvailableReplicas returns true if the admission webhook can serve requests,
+// even if a rollout is in progress.
+func (d *deployment) HasAvailableReplicas() (available bool, err error) {
+	name := d.asset.Deployment().Name()
+	current, err := d.lister.Deployments(d.context.WebhookNamespace()).Get(name)
+	if err != nil {
+		return
+	}
+
+	available = IsDeploymentAvailable(&current.Status)
+	return
+}
+
 func (d *deployment) Get() (object runtime.Object, accessor metav1.Object, err error) {
 	name := d.asset.Deployment().Name()
 	object, err = d.lister.Deployments(d.context.WebhookNamespace()).Get(name)
@@ -33,6 +33,17 @@ func IsDeploymentFailedCreate(status *appsv1.DeploymentStatus) bool {
 	return cond.Reason == "FailedCreate" && cond.Status == corev1.ConditionTrue
 }
 
+// IsDeploymentAvailable returns true if at least one replica is available or
+// the Available condition is True, regardless of whether a rollout is done.
+func IsDeploymentAvailable(status *appsv1.DeploymentStatus) bool {
+	if status.AvailableReplicas > 0 {
+		return true
+	}
+
+	cond := GetDeploymentCondition(status, appsv1.DeploymentAvailable)
+	return cond != nil && cond.Status == corev1.ConditionTrue
+}
+
 func GetDeploymentStatus(deployment *appsv1.Deployment, checkGeneration bool) (done bool, err error) {
 	if checkGeneration && deployment.Generation > deployment.Status.ObservedGeneration {
 		err = fmt.Errorf("waiting for deployment spec update name=%s; generation=%d observed generation=%d", deployment.Name, deployment.Generation, deployment.Status.ObservedGeneration)
@@ -0,0 +1,386 @@
+package diagnose
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+
+	autoscalingv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/autoscaling/v1"
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/deploy"
+	operatorruntime "github.com/openshift/cluster-resource-override-admission-operator/pkg/runtime"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/tlsprofile"
+)
+
+var (
+	apiServiceGVR                   = apiregistrationv1.SchemeGroupVersion.WithResource("apiservices")
+	mutatingWebhookConfigurationGVR = admissionregistrationv1.SchemeGroupVersion.WithResource("mutatingwebhookconfigurations")
+)
+
+// Options configures Collect.
+type Options struct {
+	Client         *operatorruntime.Client
+	OperandContext operatorruntime.OperandContext
+
+	// TailLines is the number of log lines collected from each operand container.
+	TailLines int64
+}
+
+// Collect gathers the state of the ClusterResourceOverride, the
+// ResourceOverride(s) and the operand, and checks it for common failures.
+// A failure to read an object is reported as a finding, Collect gathers as much
+// as it can.
+func Collect(ctx context.Context, options *Options) *Report {
+	c := &collector{
+		ctx:     ctx,
+		client:  options.Client,
+		context: options.OperandContext,
+		asset:   asset.New(options.OperandContext),
+		tail:    options.TailLines,
+		report:  &Report{Time: time.Now()},
+
+		transport: operatorv1.WebhookTransportAggregated,
+	}
+
+	c.clusterResourceOverride()
+	ros := c.resourceOverrides()
+	c.operand()
+	c.admission()
+	c.tlsProfile()
+	c.namespaces(ros)
+
+	return c.report
+}
+
+type collector struct {
+	ctx     context.Context
+	client  *operatorruntime.Client
+	context operatorruntime.OperandContext
+	asset   *asset.Asset
+	tail    int64
+	report  *Report
+
+	// transport is the webhook transport of the ClusterResourceOverride,
+	// Aggregated if it cannot be read.
+	transport operatorv1.WebhookTransport
+}
+
+func (c *collector) clusterResourceOverride() {
+	name := c.context.ResourceName()
+	cro, err := c.client.Operator.OperatorV1().ClusterResourceOverrides().Get(c.ctx, name, metav1.GetOptions{})
+	if err != nil {
+		c.failed(fmt.Sprintf("ClusterResourceOverride %q", name), err)
+		return
+	}
+	c.report.add("clusterresourceoverride.yaml", cro)
+	c.transport = cro.Spec.GetWebhookTransport()
+
+	for _, condition := range cro.Status.Conditions {
+		switch {
+		case condition.Type == operatorv1.Available && condition.Status != corev1.ConditionTrue:
+			c.report.error("ClusterResourceOverride %q is not available: %s %s", name, condition.Reason, condition.Message)
+		case condition.Type == operatorv1.InstallReadinessFailure && condition.Status == corev1.ConditionTrue:
+			c.report.error("ClusterResourceOverride %q failed to install: %s %s", name, condition.Reason, condition.Message)
+		}
+	}
+
+	resources := cro.Status.Resources
+	refs := []*corev1.ObjectReference{
+		resources.ConfigurationRef,
+		resources.ServiceRef,
+		resources.DeploymentRef,
+		resources.APiServiceRef,
+		resources.MutatingWebhookConfigurationRef,
+		resources.ValidatingAdmissionPolicyRef,
+		resources.ValidatingAdmissionPolicyBindingRef,
+		resources.ResourceOverridePolicyRef,
+		resources.ResourceOverridePolicyBindingRef,
+	}
+	for _, ref := range refs {
+		if ref != nil {
+			c.reference(ref)
+		}
+	}
+}
+
+// reference collects an object referenced in status.resources.
+func (c *collector) reference(ref *corev1.ObjectReference) {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		c.report.warning("status.resources references %s %q with an invalid apiVersion %q", ref.Kind, ref.Name, ref.APIVersion)
+		return
+	}
+	gvr, _ := meta.UnsafeGuessKindToResource(gv.WithKind(ref.Kind))
+
+	object, err := c.client.RawDynamic.Resource(gvr).Namespace(ref.Namespace).Get(c.ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			c.report.error("%s %q referenced in status.resources is not found", ref.Kind, ref.Name)
+			return
+		}
+		c.failed(fmt.Sprintf("%s %q", ref.Kind, ref.Name), err)
+		return
+	}
+
+	c.report.add(fmt.Sprintf("resources/%s-%s.yaml", strings.ToLower(ref.Kind), ref.Name), object)
+}
+
+func (c *collector) resourceOverrides() []autoscalingv1.ResourceOverride {
+	ros, err := c.client.Operator.AutoscalingV1().ResourceOverrides(metav1.NamespaceAll).List(c.ctx, metav1.ListOptions{})
+	if err != nil {
+		c.failed("ResourceOverrides", err)
+		return nil
+	}
+	c.report.add("resourceoverrides.yaml", ros)
+
+	for _, ro := range ros.Items {
+		for _, condition := range ro.Status.Conditions {
+			if condition.Status != corev1.ConditionTrue || condition.Type == autoscalingv1.NoMatchingPods {
+				continue
+			}
+			c.report.warning("ResourceOverride %s/%s has condition %s: %s %s", ro.Namespace, ro.Name, condition.Type, condition.Reason, condition.Message)
+		}
+	}
+
+	return ros.Items
+}
+
+// operand collects the Deployment, pods, logs and serving certificate Secret
+// of the admission webhook.
+func (c *collector) operand() {
+	namespace := c.context.WebhookNamespace()
+	values := c.asset.Values()
+
+	name := c.asset.Deployment().Name()
+	deployment, err := c.client.Kubernetes.AppsV1().Deployments(namespace).Get(c.ctx, name, metav1.GetOptions{})
+	switch {
+	case k8serrors.IsNotFound(err):
+		c.report.error("Deployment %s/%s of the admission webhook is missing", namespace, name)
+	case err != nil:
+		c.failed(fmt.Sprintf("Deployment %s/%s", namespace, name), err)
+	default:
+		c.report.add("operand/deployment.yaml", deployment)
+		if _, err := deploy.GetDeploymentStatus(deployment, false); err != nil {
+			c.report.error("Deployment %s/%s of the admission webhook is not available: %s", namespace, name, err.Error())
+		}
+	}
+
+	selector := labels.SelectorFromSet(labels.Set{values.SelectorLabelKey: values.SelectorLabelValue})
+	pods, err := c.client.Kubernetes.CoreV1().Pods(namespace).List(c.ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		c.failed("operand pods", err)
+	} else {
+		c.report.add("operand/pods.yaml", pods)
+		if len(pods.Items) == 0 {
+			c.report.error("no admission webhook pod is found in namespace %s", namespace)
+		}
+		for i := range pods.Items {
+			c.pod(&pods.Items[i])
+		}
+	}
+
+	secretName := c.asset.ServiceServingSecret().Name()
+	secret, err := c.client.Kubernetes.CoreV1().Secrets(namespace).Get(c.ctx, secretName, metav1.GetOptions{})
+	switch {
+	case k8serrors.IsNotFound(err):
+		c.report.error("serving certificate Secret %s/%s is missing, check that the service CA operator is running", namespace, secretName)
+	case err != nil:
+		c.failed(fmt.Sprintf("Secret %s/%s", namespace, secretName), err)
+	default:
+		for _, key := range []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey} {
+			if len(secret.Data[key]) == 0 {
+				c.report.error("serving certificate Secret %s/%s has no %s", namespace, secretName, key)
+			}
+		}
+
+		// the key material is never collected.
+		keys := make([]string, 0, len(secret.Data))
+		for key := range secret.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		secret.Data, secret.StringData = nil, nil
+		c.report.add("operand/serving-cert-secret.yaml", secret)
+		c.report.addText("operand/serving-cert-secret-keys.txt", strings.Join(keys, "\n")+"\n")
+	}
+}
+
+func (c *collector) pod(pod *corev1.Pod) {
+	for _, status := range pod.Status.ContainerStatuses {
+		switch {
+		case status.State.Waiting != nil:
+			c.report.error("container %s of pod %s is waiting: %s %s", status.Name, pod.Name, status.State.Waiting.Reason, status.State.Waiting.Message)
+		case !status.Ready:
+			c.report.warning("container %s of pod %s is not ready", status.Name, pod.Name)
+		}
+		if status.RestartCount > 0 {
+			c.report.warning("container %s of pod %s has restarted %d time(s)", status.Name, pod.Name, status.RestartCount)
+		}
+	}
+
+	for _, container := range pod.Spec.Containers {
+		c.logs(pod, container.Name, false)
+
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name == container.Name && status.RestartCount > 0 {
+				c.logs(pod, container.Name, true)
+			}
+		}
+	}
+}
+
+func (c *collector) logs(pod *corev1.Pod, container string, previous bool) {
+	options := &corev1.PodLogOptions{
+		Container: container,
+		Previous:  previous,
+	}
+	if c.tail > 0 {
+		options.TailLines = &c.tail
+	}
+
+	logs, err := c.client.Kubernetes.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, options).DoRaw(c.ctx)
+	if err != nil {
+		c.report.warning("failed to get the logs of container %s of pod %s - %s", container, pod.Name, err.Error())
+		return
+	}
+
+	name := fmt.Sprintf("operand/logs/%s-%s.log", pod.Name, container)
+	if previous {
+		name = fmt.Sprintf("operand/logs/%s-%s-previous.log", pod.Name, container)
+	}
+	c.report.addText(name, string(logs))
+}
+
+// admission collects the APIService and MutatingWebhookConfiguration the
+// admission webhook is served through. There is no APIService with the
+// Service transport.
+func (c *collector) admission() {
+	name := c.asset.APIService().Name()
+	object, err := c.client.RawDynamic.Resource(apiServiceGVR).Get(c.ctx, name, metav1.GetOptions{})
+	switch {
+	case c.transport == operatorv1.WebhookTransportService:
+		if err == nil {
+			c.report.warning("APIService %s is left behind, it is not used with the %s webhook transport", name, operatorv1.WebhookTransportService)
+		}
+	case k8serrors.IsNotFound(err):
+		c.report.error("APIService %s is missing", name)
+	case err != nil:
+		c.failed(fmt.Sprintf("APIService %s", name), err)
+	default:
+		c.report.add("admission/apiservice.yaml", object)
+
+		apiservice := &apiregistrationv1.APIService{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(object.Object, apiservice); err != nil {
+			c.failed(fmt.Sprintf("APIService %s", name), err)
+		} else if status, message := deploy.IsAPIServiceAvailable(apiservice); status != corev1.ConditionTrue {
+			if message == "" {
+				message = "no Available condition is reported"
+			}
+			c.report.error("APIService %s is not available: %s", name, message)
+		}
+	}
+
+	name = c.asset.NewMutatingWebhookConfiguration().Name()
+	object, err = c.client.RawDynamic.Resource(mutatingWebhookConfigurationGVR).Get(c.ctx, name, metav1.GetOptions{})
+	switch {
+	case k8serrors.IsNotFound(err):
+		c.report.error("MutatingWebhookConfiguration %s is missing", name)
+	case err != nil:
+		c.failed(fmt.Sprintf("MutatingWebhookConfiguration %s", name), err)
+	default:
+		c.report.add("admission/mutatingwebhookconfiguration.yaml", object)
+
+		configuration := &admissionregistrationv1.MutatingWebhookConfiguration{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(object.Object, configuration); err != nil {
+			c.failed(fmt.Sprintf("MutatingWebhookConfiguration %s", name), err)
+			return
+		}
+		for _, webhook := range configuration.Webhooks {
+			if len(webhook.ClientConfig.CABundle) == 0 {
+				c.report.error("webhook %s of MutatingWebhookConfiguration %s has no caBundle, check that the service CA operator is running", webhook.Name, name)
+			}
+		}
+	}
+}
+
+// tlsProfile collects the cluster APIServer configuration and the TLS
+// arguments the operand is started with.
+func (c *collector) tlsProfile() {
+	object, err := c.client.RawDynamic.Resource(tlsprofile.APIServerGVR).Get(c.ctx, "cluster", metav1.GetOptions{})
+	if err == nil {
+		c.report.add("tlsprofile/apiserver.yaml", object)
+	} else if !k8serrors.IsNotFound(err) {
+		c.failed("APIServer cluster", err)
+	}
+
+	args, err := tlsprofile.Fetch(c.ctx, c.client.RawDynamic)
+	if err != nil {
+		c.report.warning("the cluster TLS profile cannot be read, the admission webhook uses its default - %s", err.Error())
+		return
+	}
+	c.report.addText("tlsprofile/args.txt", fmt.Sprintf("minVersion: %s\ncipherSuites: %s\n", args.MinVersion, args.CipherSuites))
+}
+
+// namespaces collects the namespaces that are opted in, along with those that
+// hold a ResourceOverride.
+func (c *collector) namespaces(ros []autoscalingv1.ResourceOverride) {
+	selector := labels.SelectorFromSet(labels.Set{asset.NamespaceOptInLabelKey: "true"})
+	optedIn, err := c.client.Kubernetes.CoreV1().Namespaces().List(c.ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		c.failed("opted-in namespaces", err)
+		return
+	}
+
+	list := &corev1.NamespaceList{}
+	seen := map[string]bool{}
+	for _, ns := range optedIn.Items {
+		seen[ns.Name] = true
+		list.Items = append(list.Items, ns)
+	}
+
+	for _, ro := range ros {
+		if seen[ro.Namespace] {
+			continue
+		}
+		seen[ro.Namespace] = true
+
+		ns, err := c.client.Kubernetes.CoreV1().Namespaces().Get(c.ctx, ro.Namespace, metav1.GetOptions{})
+		if err != nil {
+			c.failed(fmt.Sprintf("Namespace %s", ro.Namespace), err)
+			continue
+		}
+		list.Items = append(list.Items, *ns)
+	}
+
+	if len(optedIn.Items) == 0 {
+		c.report.warning("no namespace has the %s=true label, no pod is overridden", asset.NamespaceOptInLabelKey)
+	}
+
+	summary := make([]string, 0, len(list.Items))
+	for _, ns := range list.Items {
+		summary = append(summary, fmt.Sprintf("%s %s=%q %s=%q", ns.Name,
+			asset.NamespaceOptInLabelKey, ns.Labels[asset.NamespaceOptInLabelKey],
+			asset.NamespaceAutoOptInAnnotationKey, ns.Annotations[asset.NamespaceAutoOptInAnnotationKey]))
+	}
+	sort.Strings(summary)
+
+	c.report.add("namespaces.yaml", list)
+	c.report.addText("namespaces.txt", strings.Join(summary, "\n")+"\n")
+}
+
+func (c *collector) failed(what string, err error) {
+	c.report.warning("failed to get %s - %s", what, err.Error())
+}
@@ -0,0 +1,196 @@
+package diagnose
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/clientset/versioned/fake"
+	operatorruntime "github.com/openshift/cluster-resource-override-admission-operator/pkg/runtime"
+)
+
+type fixture struct {
+	context operatorruntime.OperandContext
+	asset   *asset.Asset
+
+	apiServiceAvailable bool
+	withSecret          bool
+}
+
+func newFixture() *fixture {
+	context := operatorruntime.NewOperandContext("clusterresourceoverride", "clusterresourceoverride-operator", "cluster", "", "")
+	return &fixture{
+		context:             context,
+		asset:               asset.New(context),
+		apiServiceAvailable: true,
+		withSecret:          true,
+	}
+}
+
+func (f *fixture) collect(t *testing.T) *Report {
+	namespace := f.context.WebhookNamespace()
+	values := f.asset.Values()
+
+	cro := &operatorv1.ClusterResourceOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Status: operatorv1.ClusterResourceOverrideStatus{
+			Conditions: []operatorv1.ClusterResourceOverrideCondition{
+				{Type: operatorv1.Available, Status: corev1.ConditionTrue},
+			},
+			Resources: operatorv1.ClusterResourceOverrideResources{
+				APiServiceRef: &corev1.ObjectReference{APIVersion: "apiregistration.k8s.io/v1", Kind: "APIService", Name: f.asset.APIService().Name()},
+			},
+		},
+	}
+
+	replicas := int32(1)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: f.asset.Deployment().Name(), Namespace: namespace},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "clusterresourceoverride-abcde",
+			Namespace: namespace,
+			Labels:    map[string]string{values.SelectorLabelKey: values.SelectorLabelValue},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "clusterresourceoverride"}}},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "clusterresourceoverride", Ready: true}},
+		},
+	}
+	optedIn := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Labels: map[string]string{asset.NamespaceOptInLabelKey: "true"}},
+	}
+	kubeObjects := []runtime.Object{deployment, pod, optedIn}
+	if f.withSecret {
+		kubeObjects = append(kubeObjects, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: f.asset.ServiceServingSecret().Name(), Namespace: namespace},
+			Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert"), corev1.TLSPrivateKeyKey: []byte("key")},
+		})
+	}
+
+	status := "True"
+	if !f.apiServiceAvailable {
+		status = "False"
+	}
+	apiservice := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiregistration.k8s.io/v1",
+		"kind":       "APIService",
+		"metadata":   map[string]interface{}{"name": f.asset.APIService().Name()},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Available", "status": status, "message": "failing or missing response"},
+			},
+		},
+	}}
+	webhook := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "admissionregistration.k8s.io/v1",
+		"kind":       "MutatingWebhookConfiguration",
+		"metadata":   map[string]interface{}{"name": f.asset.NewMutatingWebhookConfiguration().Name()},
+		"webhooks": []interface{}{
+			map[string]interface{}{"name": "clusterresourceoverrides.admission.autoscaling.openshift.io", "clientConfig": map[string]interface{}{"caBundle": "Y2E="}},
+		},
+	}}
+
+	client := &operatorruntime.Client{
+		Operator:   fake.NewSimpleClientset(cro),
+		Kubernetes: kubefake.NewSimpleClientset(kubeObjects...),
+		RawDynamic: dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), apiservice, webhook),
+	}
+
+	return Collect(context.TODO(), &Options{
+		Client:         client,
+		OperandContext: f.context,
+		TailLines:      100,
+	})
+}
+
+func TestCollect(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(f *fixture)
+		errors []string
+	}{
+		{
+			name: "healthy",
+		},
+		{
+			name:   "missing serving certificate secret",
+			modify: func(f *fixture) { f.withSecret = false },
+			errors: []string{"serving certificate Secret clusterresourceoverride-operator/server-serving-cert-clusterresourceoverride is missing, check that the service CA operator is running"},
+		},
+		{
+			name:   "unavailable APIService",
+			modify: func(f *fixture) { f.apiServiceAvailable = false },
+			errors: []string{"APIService v1.admission.autoscaling.openshift.io is not available: failing or missing response"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := newFixture()
+			if test.modify != nil {
+				test.modify(f)
+			}
+
+			report := f.collect(t)
+
+			errors := make([]string, 0)
+			for _, finding := range report.Findings {
+				if finding.Severity == SeverityError {
+					errors = append(errors, finding.Message)
+				}
+			}
+			require.ElementsMatch(t, test.errors, errors)
+			require.Equal(t, len(test.errors) == 0, report.Healthy())
+		})
+	}
+}
+
+func TestWriteTarball(t *testing.T) {
+	report := newFixture().collect(t)
+
+	buffer := &bytes.Buffer{}
+	require.NoError(t, report.WriteTarball(buffer, "diagnose"))
+
+	gz, err := gzip.NewReader(buffer)
+	require.NoError(t, err)
+	files := map[string]string{}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[header.Name] = string(data)
+	}
+
+	require.Contains(t, files["diagnose/summary.txt"], ": healthy\n")
+	require.Contains(t, files["diagnose/clusterresourceoverride.yaml"], "kind: ClusterResourceOverride")
+	require.Contains(t, files["diagnose/resources/apiservice-v1.admission.autoscaling.openshift.io.yaml"], "kind: APIService")
+	require.Contains(t, files["diagnose/operand/deployment.yaml"], "kind: Deployment")
+	require.Equal(t, "fake logs", files["diagnose/operand/logs/clusterresourceoverride-abcde-clusterresourceoverride.log"])
+	require.Equal(t, "tls.crt\ntls.key\n", files["diagnose/operand/serving-cert-secret-keys.txt"])
+	require.NotContains(t, files["diagnose/operand/serving-cert-secret.yaml"], "Y2VydA==")
+	require.Contains(t, files["diagnose/namespaces.txt"], "test ")
+}
@@ -0,0 +1,178 @@
+package diagnose
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+
+	operatorscheme "github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/clientset/versioned/scheme"
+)
+
+// SummaryFileName is the name of the health summary in the bundle.
+const SummaryFileName = "summary.txt"
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	// the kind of an object is not set by a typed client, it is looked up to
+	// make the collected manifests self-describing.
+	clientgoscheme.AddToScheme(scheme)
+	operatorscheme.AddToScheme(scheme)
+}
+
+// Severity is the severity of a Finding.
+type Severity string
+
+const (
+	// SeverityError is a failure that prevents pods from being overridden.
+	SeverityError Severity = "Error"
+
+	// SeverityWarning is a condition that may explain unexpected results.
+	SeverityWarning Severity = "Warning"
+)
+
+// Finding is an issue found while collecting.
+type Finding struct {
+	Severity Severity
+	Message  string
+}
+
+// File is a file of the bundle.
+type File struct {
+	Name string
+	Data []byte
+}
+
+// Report holds what Collect gathered.
+type Report struct {
+	Time     time.Time
+	Files    []File
+	Findings []Finding
+}
+
+// Healthy returns true if no error is found.
+func (r *Report) Healthy() bool {
+	for _, finding := range r.Findings {
+		if finding.Severity == SeverityError {
+			return false
+		}
+	}
+
+	return true
+}
+
+// WriteSummary writes the human-readable health summary to w.
+func (r *Report) WriteSummary(w io.Writer) error {
+	status := "healthy"
+	if !r.Healthy() {
+		status = "unhealthy"
+	}
+
+	if _, err := fmt.Fprintf(w, "ClusterResourceOverride health summary, collected at %s: %s\n", r.Time.UTC().Format(time.RFC3339), status); err != nil {
+		return err
+	}
+
+	if len(r.Findings) == 0 {
+		_, err := fmt.Fprintln(w, "No issue found.")
+		return err
+	}
+
+	for _, finding := range r.Findings {
+		if _, err := fmt.Fprintf(w, "%s: %s\n", finding.Severity, finding.Message); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteTarball writes the collected files and the health summary to w as a
+// gzip-compressed tarball, under the given directory.
+func (r *Report) WriteTarball(w io.Writer, directory string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	summary := &summaryWriter{}
+	if err := r.WriteSummary(summary); err != nil {
+		return err
+	}
+
+	files := append([]File{{Name: SummaryFileName, Data: summary.data}}, r.Files...)
+	for _, file := range files {
+		header := &tar.Header{
+			Name:    path.Join(directory, file.Name),
+			Mode:    0644,
+			Size:    int64(len(file.Data)),
+			ModTime: r.Time,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(file.Data); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return gz.Close()
+}
+
+type summaryWriter struct {
+	data []byte
+}
+
+func (s *summaryWriter) Write(p []byte) (int, error) {
+	s.data = append(s.data, p...)
+	return len(p), nil
+}
+
+func (r *Report) add(name string, object runtime.Object) {
+	setKind(object)
+	if meta.IsListType(object) {
+		meta.EachListItem(object, func(item runtime.Object) error {
+			setKind(item)
+			return nil
+		})
+	}
+
+	data, err := yaml.Marshal(object)
+	if err != nil {
+		r.warning("failed to marshal %s - %s", name, err.Error())
+		return
+	}
+
+	r.Files = append(r.Files, File{Name: name, Data: data})
+}
+
+func (r *Report) addText(name, text string) {
+	r.Files = append(r.Files, File{Name: name, Data: []byte(text)})
+}
+
+func (r *Report) error(format string, args ...interface{}) {
+	r.Findings = append(r.Findings, Finding{Severity: SeverityError, Message: fmt.Sprintf(format, args...)})
+}
+
+func (r *Report) warning(format string, args ...interface{}) {
+	r.Findings = append(r.Findings, Finding{Severity: SeverityWarning, Message: fmt.Sprintf(format, args...)})
+}
+
+func setKind(object runtime.Object) {
+	if !object.GetObjectKind().GroupVersionKind().Empty() {
+		return
+	}
+
+	if gvks, _, err := scheme.ObjectKinds(object); err == nil && len(gvks) > 0 {
+		object.GetObjectKind().SetGroupVersionKind(gvks[0])
+	}
+}
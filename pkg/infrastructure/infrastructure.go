@@ -2,13 +2,21 @@ package infrastructure
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
 
 	configv1 "github.com/openshift/api/config/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/klog/v2"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
 )
 
 var InfrastructureGVR = schema.GroupVersionResource{
@@ -17,24 +25,140 @@ var InfrastructureGVR = schema.GroupVersionResource{
 	Resource: "infrastructures",
 }
 
-// IsStandalone fetches the cluster Infrastructure resource and returns true
-// if the control plane topology is NOT external (i.e. not a Hosted Control
-// Plane cluster). On error it logs a warning and defaults to true (standalone)
-// for backward compatibility.
-func IsStandalone(ctx context.Context, dynClient dynamic.Interface) bool {
-	obj, err := dynClient.Resource(InfrastructureGVR).Get(ctx, "cluster", metav1.GetOptions{})
+const (
+	// routeGroup is served by OpenShift and MicroShift, MicroShift does not
+	// serve config.openshift.io.
+	routeGroup = "route.openshift.io"
+)
+
+// NewDetector returns a Detector that classifies the cluster with the given
+// discovery and dynamic clients.
+func NewDetector(discovery discovery.DiscoveryInterface, dynamic dynamic.Interface) *Detector {
+	return &Detector{
+		discovery: discovery,
+		dynamic:   dynamic,
+	}
+}
+
+// Detector classifies the cluster as OpenShift, single node OpenShift,
+// HyperShift hosted, MicroShift or vanilla Kubernetes. The API groups the
+// cluster serves tell OpenShift from MicroShift and Kubernetes, the topologies
+// of the Infrastructure object tell the OpenShift platforms apart.
+type Detector struct {
+	discovery discovery.DiscoveryInterface
+	dynamic   dynamic.Interface
+
+	lock sync.Mutex
+	// served is the set of API groups discovered once, the operator decides
+	// at startup which of them are watched.
+	served map[string]bool
+	// infrastructures reads the Infrastructure object from the informer
+	// cache once it is watched, the dynamic client is used until then.
+	infrastructures cache.GenericLister
+}
+
+// SetInfrastructureLister has the Infrastructure object read from the given
+// informer cache rather than from the API server.
+func (d *Detector) SetInfrastructureLister(lister cache.GenericLister) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.infrastructures = lister
+}
+
+// Detect returns the platform of the cluster. An error reading discovery or
+// the Infrastructure object is returned, no platform is assumed.
+func (d *Detector) Detect(ctx context.Context) (*operatorv1.PlatformStatus, error) {
+	served, err := d.discover()
+	if err != nil {
+		return nil, err
+	}
+
+	if !served[InfrastructureGVR.Group] {
+		if served[routeGroup] {
+			return &operatorv1.PlatformStatus{Type: operatorv1.PlatformMicroShift}, nil
+		}
+		return &operatorv1.PlatformStatus{Type: operatorv1.PlatformKubernetes}, nil
+	}
+
+	infrastructure, err := d.infrastructure(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	platform := &operatorv1.PlatformStatus{
+		Type:                   operatorv1.PlatformOpenShift,
+		ControlPlaneTopology:   string(infrastructure.Status.ControlPlaneTopology),
+		InfrastructureTopology: string(infrastructure.Status.InfrastructureTopology),
+	}
+	switch infrastructure.Status.ControlPlaneTopology {
+	case configv1.ExternalTopologyMode:
+		platform.Type = operatorv1.PlatformHyperShift
+	case configv1.SingleReplicaTopologyMode:
+		platform.Type = operatorv1.PlatformSingleReplica
+	}
+
+	return platform, nil
+}
+
+// discover returns the API groups the cluster serves, discovered on the first
+// call that succeeds.
+func (d *Detector) discover() (map[string]bool, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.served != nil {
+		return d.served, nil
+	}
+
+	groups, err := d.discovery.ServerGroups()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover the API groups - %s", err.Error())
+	}
+
+	served := map[string]bool{}
+	for _, group := range groups.Groups {
+		served[group.Name] = true
+	}
+
+	d.served = served
+	return served, nil
+}
+
+func (d *Detector) infrastructure(ctx context.Context) (*configv1.Infrastructure, error) {
+	object, err := d.getInfrastructure(ctx)
 	if err != nil {
-		klog.Warningf("Failed to fetch Infrastructure resource, assuming standalone: %v", err)
-		return true
+		if k8serrors.IsNotFound(err) {
+			return nil, errors.New("no Infrastructure cluster found although config.openshift.io is served")
+		}
+		return nil, fmt.Errorf("failed to get Infrastructure cluster - %s", err.Error())
 	}
 
-	infra := &configv1.Infrastructure{}
-	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, infra); err != nil {
-		klog.Warningf("Failed to convert Infrastructure resource, assuming standalone: %v", err)
-		return true
+	infrastructure := &configv1.Infrastructure{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(object.Object, infrastructure); err != nil {
+		return nil, fmt.Errorf("failed to convert Infrastructure cluster - %s", err.Error())
 	}
 
-	topology := infra.Status.ControlPlaneTopology
-	klog.Infof("Detected control plane topology: %s", topology)
-	return topology != configv1.ExternalTopologyMode
+	return infrastructure, nil
+}
+
+func (d *Detector) getInfrastructure(ctx context.Context) (*unstructured.Unstructured, error) {
+	d.lock.Lock()
+	lister := d.infrastructures
+	d.lock.Unlock()
+
+	if lister == nil {
+		return d.dynamic.Resource(InfrastructureGVR).Get(ctx, "cluster", metav1.GetOptions{})
+	}
+
+	cached, err := lister.Get("cluster")
+	if err != nil {
+		return nil, err
+	}
+
+	object, ok := cached.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("unexpected Infrastructure type %T", cached)
+	}
+	return object, nil
 }
@@ -2,14 +2,22 @@ package infrastructure
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	configv1 "github.com/openshift/api/config/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
 )
 
 func makeFakeDynClient(t *testing.T, topology configv1.TopologyMode) *dynamicfake.FakeDynamicClient {
@@ -20,50 +28,147 @@ func makeFakeDynClient(t *testing.T, topology configv1.TopologyMode) *dynamicfak
 		TypeMeta:   metav1.TypeMeta{APIVersion: "config.openshift.io/v1", Kind: "Infrastructure"},
 		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
 		Status: configv1.InfrastructureStatus{
-			ControlPlaneTopology: topology,
+			ControlPlaneTopology:   topology,
+			InfrastructureTopology: configv1.HighlyAvailableTopologyMode,
 		},
 	}
 	return dynamicfake.NewSimpleDynamicClient(scheme, infra)
 }
 
-func TestIsStandalone(t *testing.T) {
+// makeFakeDiscovery returns a discovery client serving the given group
+// version(s).
+func makeFakeDiscovery(groupVersions ...string) *fakediscovery.FakeDiscovery {
+	discovery := kubefake.NewSimpleClientset().Discovery().(*fakediscovery.FakeDiscovery)
+	for _, groupVersion := range groupVersions {
+		discovery.Resources = append(discovery.Resources, &metav1.APIResourceList{GroupVersion: groupVersion})
+	}
+	return discovery
+}
+
+func TestDetect(t *testing.T) {
 	tests := []struct {
 		name     string
 		topology configv1.TopologyMode
-		want     bool
+		want     operatorv1.PlatformType
 	}{
 		{
-			name:     "HighlyAvailable is standalone",
+			name:     "HighlyAvailable is OpenShift",
 			topology: configv1.HighlyAvailableTopologyMode,
-			want:     true,
+			want:     operatorv1.PlatformOpenShift,
 		},
 		{
-			name:     "SingleReplica is standalone",
+			name:     "SingleReplica is single node OpenShift",
 			topology: configv1.SingleReplicaTopologyMode,
-			want:     true,
+			want:     operatorv1.PlatformSingleReplica,
 		},
 		{
-			name:     "External (HCP) is not standalone",
+			name:     "External is HyperShift",
 			topology: configv1.ExternalTopologyMode,
-			want:     false,
+			want:     operatorv1.PlatformHyperShift,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			dynClient := makeFakeDynClient(t, tt.topology)
-			got := IsStandalone(context.Background(), dynClient)
-			assert.Equal(t, tt.want, got)
+			detector := NewDetector(makeFakeDiscovery("v1", "config.openshift.io/v1", "route.openshift.io/v1"), makeFakeDynClient(t, tt.topology))
+			got, err := detector.Detect(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Type)
+			assert.Equal(t, string(tt.topology), got.ControlPlaneTopology)
+			assert.Equal(t, string(configv1.HighlyAvailableTopologyMode), got.InfrastructureTopology)
+			assert.Equal(t, tt.want != operatorv1.PlatformHyperShift, got.IsStandalone())
+			assert.True(t, got.HasOpenShiftConfig())
 		})
 	}
 }
 
-func TestIsStandalone_NotFound(t *testing.T) {
+func TestDetect_WithoutOpenShiftConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		groupVersions []string
+		want          operatorv1.PlatformType
+	}{
+		{
+			name:          "route.openshift.io only is MicroShift",
+			groupVersions: []string{"v1", "route.openshift.io/v1", "security.openshift.io/v1"},
+			want:          operatorv1.PlatformMicroShift,
+		},
+		{
+			name:          "no OpenShift API is Kubernetes",
+			groupVersions: []string{"v1", "apps/v1"},
+			want:          operatorv1.PlatformKubernetes,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the Infrastructure object is not read.
+			detector := NewDetector(makeFakeDiscovery(tt.groupVersions...), dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()))
+			got, err := detector.Detect(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Type)
+			assert.True(t, got.IsStandalone())
+			assert.False(t, got.HasOpenShiftConfig())
+		})
+	}
+}
+
+func TestDetect_InfrastructureNotFound(t *testing.T) {
 	scheme := runtime.NewScheme()
 	require.NoError(t, configv1.AddToScheme(scheme))
-	dynClient := dynamicfake.NewSimpleDynamicClient(scheme)
+	detector := NewDetector(makeFakeDiscovery("config.openshift.io/v1"), dynamicfake.NewSimpleDynamicClient(scheme))
+
+	// no platform is assumed when the Infrastructure object cannot be read.
+	_, err := detector.Detect(context.Background())
+	assert.Error(t, err)
+}
+
+func TestDetect_Cached(t *testing.T) {
+	discovery := makeFakeDiscovery("config.openshift.io/v1")
+	dynamic := makeFakeDynClient(t, configv1.HighlyAvailableTopologyMode)
+	detector := NewDetector(discovery, dynamic)
+
+	got, err := detector.Detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, operatorv1.PlatformOpenShift, got.Type)
+
+	// the Infrastructure object is read from the informer cache once it is
+	// watched, and the API groups are not discovered again.
+	object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&configv1.Infrastructure{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "config.openshift.io/v1", Kind: "Infrastructure"},
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Status: configv1.InfrastructureStatus{
+			ControlPlaneTopology:   configv1.SingleReplicaTopologyMode,
+			InfrastructureTopology: configv1.SingleReplicaTopologyMode,
+		},
+	})
+	require.NoError(t, err)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, indexer.Add(&unstructured.Unstructured{Object: object}))
+	detector.SetInfrastructureLister(cache.NewGenericLister(indexer, InfrastructureGVR.GroupResource()))
+
+	discovery.ClearActions()
+	dynamic.ClearActions()
+	got, err = detector.Detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, operatorv1.PlatformSingleReplica, got.Type)
+	assert.Empty(t, discovery.Actions())
+	assert.Empty(t, dynamic.Actions())
+}
+
+func TestDetect_DiscoveryNotCachedOnError(t *testing.T) {
+	discovery := makeFakeDiscovery("v1")
+	discovery.PrependReactor("get", "group", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("connection refused")
+	})
+	detector := NewDetector(discovery, dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()))
+
+	_, err := detector.Detect(context.Background())
+	require.Error(t, err)
 
-	// Should default to true (standalone) when Infrastructure CR is missing
-	got := IsStandalone(context.Background(), dynClient)
-	assert.True(t, got, "should default to standalone when Infrastructure resource is not found")
+	// a transient error is not remembered.
+	discovery.ReactionChain = discovery.ReactionChain[1:]
+	got, err := detector.Detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, operatorv1.PlatformKubernetes, got.Type)
 }
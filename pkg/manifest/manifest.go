@@ -0,0 +1,70 @@
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+// Write writes the given object(s) to w as a multi-document YAML stream, in
+// order. The status and the creationTimestamp of each object are dropped so
+// that the output only holds what is meant to be applied.
+func Write(w io.Writer, objects ...runtime.Object) error {
+	for i, object := range objects {
+		bytes, err := Marshal(object)
+		if err != nil {
+			return err
+		}
+
+		if i > 0 {
+			if _, err := io.WriteString(w, "---\n"); err != nil {
+				return err
+			}
+		}
+
+		if _, err := w.Write(bytes); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Marshal returns the YAML representation of the given object, without its
+// status and creationTimestamp. Keys are sorted so the output is deterministic.
+func Marshal(object runtime.Object) ([]byte, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %T - %s", object, err.Error())
+	}
+
+	delete(content, "status")
+	if metadata, ok := content["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "creationTimestamp")
+	}
+
+	return yaml.Marshal(content)
+}
+
+// Split splits a multi-document YAML stream into its non-empty documents.
+func Split(data []byte) [][]byte {
+	documents := make([][]byte, 0)
+	for _, document := range bytes.Split(append([]byte("\n"), data...), []byte("\n---")) {
+		// a separator may be followed by a comment or a document name.
+		if newline := bytes.IndexByte(document, '\n'); newline >= 0 {
+			document = document[newline+1:]
+		} else {
+			document = nil
+		}
+
+		if len(bytes.TrimSpace(document)) == 0 {
+			continue
+		}
+		documents = append(documents, document)
+	}
+
+	return documents
+}
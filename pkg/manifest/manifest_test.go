@@ -0,0 +1,47 @@
+package manifest
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSplit(t *testing.T) {
+	documents := Split([]byte("---\na: 1\n--- # second\nb: 2\n---\n\n---\nc: 3"))
+	require.Equal(t, [][]byte{[]byte("a: 1"), []byte("b: 2"), []byte("c: 3")}, documents)
+}
+
+func TestWrite(t *testing.T) {
+	now := metav1.Now()
+	objects := []*corev1.Namespace{
+		{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+			ObjectMeta: metav1.ObjectMeta{Name: "a", CreationTimestamp: now},
+			Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+		},
+		{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+			ObjectMeta: metav1.ObjectMeta{Name: "b"},
+		},
+	}
+
+	buffer := &bytes.Buffer{}
+	require.NoError(t, Write(buffer, objects[0], objects[1]))
+	require.Equal(t, `apiVersion: v1
+kind: Namespace
+metadata:
+  name: a
+spec: {}
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: b
+spec: {}
+`, buffer.String())
+	require.Len(t, Split(buffer.Bytes()), 2)
+}
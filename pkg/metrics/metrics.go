@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	namespace = "clusterresourceoverride"
+)
+
+var (
+	// AdmissionProbeDuration is the latency of the admission probe, a
+	// server-side dry-run pod create through the admission webhook.
+	AdmissionProbeDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "admission_probe_duration_seconds",
+		Help:      "Latency of the dry-run pod create sent through the admission webhook.",
+		Buckets:   []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	})
+
+	// AdmissionProbeTotal counts the admission probes by result and reason.
+	AdmissionProbeTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "admission_probe_total",
+		Help:      "Number of admission probes, by result and reason.",
+	}, []string{"result", "reason"})
+
+	// AdmissionProbeSuccess is 1 if the last admission probe succeeded, 0
+	// otherwise.
+	AdmissionProbeSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "admission_probe_success",
+		Help:      "Whether the last admission probe succeeded.",
+	})
+
+	// ServingCertExpirationTimestamp is the notAfter of the serving certificate
+	// of the admission webhook, in seconds since the epoch.
+	ServingCertExpirationTimestamp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "serving_cert_expiration_timestamp_seconds",
+		Help:      "Expiry of the serving certificate of the admission webhook, in seconds since the epoch.",
+	})
+
+	// Registry holds the metrics of the operator.
+	Registry = prometheus.NewRegistry()
+)
+
+func init() {
+	Registry.MustRegister(
+		AdmissionProbeDuration,
+		AdmissionProbeTotal,
+		AdmissionProbeSuccess,
+		ServingCertExpirationTimestamp,
+	)
+}
+
+// Handler returns the handler serving the metrics of the operator.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
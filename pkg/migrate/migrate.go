@@ -0,0 +1,282 @@
+package migrate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/manifest"
+)
+
+const (
+	// PluginName is the name of the OpenShift 3 admission plugin in the
+	// pluginConfig of the master configuration.
+	PluginName = "ClusterResourceOverride"
+)
+
+// Options holds the input of Migrate.
+type Options struct {
+	// Name is the name of the ClusterResourceOverride to emit.
+	Name string
+
+	// MasterConfig is the content of the OpenShift 3 master-config.yaml.
+	MasterConfig []byte
+
+	// ReadFile reads the plugin configuration file when the pluginConfig
+	// stanza has a location rather than an inline configuration.
+	ReadFile func(path string) ([]byte, error)
+
+	// Namespaces are the namespaces (or projects) of the cluster, they are
+	// used to carry over the per-project setting of the plugin.
+	Namespaces []*corev1.Namespace
+}
+
+// Result holds the manifests equivalent to the OpenShift 3 configuration.
+type Result struct {
+	ClusterResourceOverride *operatorv1.ClusterResourceOverride
+
+	// Namespaces hold the opt-in label of each namespace the plugin applied to.
+	Namespaces []*corev1.Namespace
+
+	// Warnings describe the part(s) of the configuration that could not be
+	// carried over as is.
+	Warnings []string
+}
+
+type masterConfig struct {
+	AdmissionConfig        admissionConfig `json:"admissionConfig"`
+	KubernetesMasterConfig struct {
+		AdmissionConfig admissionConfig `json:"admissionConfig"`
+	} `json:"kubernetesMasterConfig"`
+}
+
+type admissionConfig struct {
+	PluginConfig map[string]pluginConfig `json:"pluginConfig"`
+}
+
+type pluginConfig struct {
+	Location      string                 `json:"location"`
+	Configuration map[string]interface{} `json:"configuration"`
+}
+
+// Migrate converts the ClusterResourceOverride pluginConfig of an OpenShift 3
+// master configuration into a ClusterResourceOverride, and the per-project
+// setting of the plugin into the opt-in label of each namespace.
+func Migrate(options *Options) (*Result, error) {
+	result := &Result{
+		Namespaces: make([]*corev1.Namespace, 0),
+		Warnings:   make([]string, 0),
+	}
+
+	configuration, err := options.pluginConfiguration(result)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := operatorv1.PodResourceOverrideSpec{}
+	keys := make([]string, 0, len(configuration))
+	for key := range configuration {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		var target *int64
+		switch key {
+		case "apiVersion", "kind":
+			continue
+		case "memoryRequestToLimitPercent":
+			target = &spec.MemoryRequestToLimitPercent
+		case "cpuRequestToLimitPercent":
+			target = &spec.CPURequestToLimitPercent
+		case "limitCPUToMemoryPercent":
+			target = &spec.LimitCPUToMemoryPercent
+		default:
+			result.Warnings = append(result.Warnings, fmt.Sprintf("field %q of the plugin configuration has no equivalent and is dropped", key))
+			continue
+		}
+
+		value, err := toInt64(configuration[key])
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s - %s", key, err.Error())
+		}
+		*target = value
+	}
+
+	if err := spec.Validate(); err != nil {
+		return nil, fmt.Errorf("plugin configuration is not a valid podResourceOverride - %s", err.Error())
+	}
+
+	result.ClusterResourceOverride = &operatorv1.ClusterResourceOverride{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: operatorv1.SchemeGroupVersion.String(),
+			Kind:       operatorv1.ClusterResourceOverrideKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: options.Name,
+		},
+		Spec: operatorv1.ClusterResourceOverrideSpec{
+			PodResourceOverride: operatorv1.PodResourceOverride{
+				Spec: spec,
+			},
+		},
+	}
+
+	migrateNamespaces(options.Namespaces, result)
+	return result, nil
+}
+
+// pluginConfiguration returns the configuration of the plugin, either inline
+// or read from its location.
+func (o *Options) pluginConfiguration(result *Result) (map[string]interface{}, error) {
+	config := &masterConfig{}
+	if err := yaml.Unmarshal(o.MasterConfig, config); err != nil {
+		return nil, fmt.Errorf("failed to parse master configuration - %s", err.Error())
+	}
+
+	plugin, ok := config.AdmissionConfig.PluginConfig[PluginName]
+	if !ok {
+		plugin, ok = config.KubernetesMasterConfig.AdmissionConfig.PluginConfig[PluginName]
+		if !ok {
+			return nil, fmt.Errorf("no %s stanza found in admissionConfig.pluginConfig", PluginName)
+		}
+		result.Warnings = append(result.Warnings, "the plugin is configured in the deprecated kubernetesMasterConfig.admissionConfig")
+	}
+
+	if plugin.Configuration != nil {
+		return plugin.Configuration, nil
+	}
+
+	if plugin.Location == "" {
+		return nil, errors.New("the plugin has neither a configuration nor a location")
+	}
+	if o.ReadFile == nil {
+		return nil, fmt.Errorf("the plugin configuration is in %s which cannot be read", plugin.Location)
+	}
+
+	bytes, err := o.ReadFile(plugin.Location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin configuration %s - %s", plugin.Location, err.Error())
+	}
+
+	configuration := map[string]interface{}{}
+	if err := yaml.Unmarshal(bytes, &configuration); err != nil {
+		return nil, fmt.Errorf("failed to parse plugin configuration %s - %s", plugin.Location, err.Error())
+	}
+
+	return configuration, nil
+}
+
+// migrateNamespaces opts in every namespace the plugin applied to. The plugin
+// applied to every project unless it was annotated otherwise, whereas the
+// operator only applies to namespaces with the opt-in label.
+func migrateNamespaces(namespaces []*corev1.Namespace, result *Result) {
+	if len(namespaces) == 0 {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("no namespaces given, label each namespace to override with %s=true", asset.NamespaceOptInLabelKey))
+		return
+	}
+
+	sorted := make([]*corev1.Namespace, len(namespaces))
+	copy(sorted, namespaces)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	for _, ns := range sorted {
+		if value, ok := ns.Annotations[asset.LegacyOverrideEnabledAnnotationKey]; ok {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("namespace %q: annotation %s=%q is not a boolean, the namespace is not opted in", ns.Name, asset.LegacyOverrideEnabledAnnotationKey, value))
+				continue
+			}
+			if !enabled {
+				continue
+			}
+		}
+
+		if isPlatformNamespace(ns.Name) {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("namespace %q is a platform namespace and is not opted in", ns.Name))
+			continue
+		}
+
+		result.Namespaces = append(result.Namespaces, &corev1.Namespace{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "Namespace",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: ns.Name,
+				Labels: map[string]string{
+					asset.NamespaceOptInLabelKey: "true",
+				},
+			},
+		})
+	}
+}
+
+func isPlatformNamespace(name string) bool {
+	return name == "default" || name == "openshift" || strings.HasPrefix(name, "openshift-") || strings.HasPrefix(name, "kube-")
+}
+
+// ReadNamespaces reads the namespaces from a YAML or JSON dump, as written by
+// "oc get namespaces -o yaml" or "oc get projects -o yaml". A dump may hold
+// several documents, each a Namespace, a Project or a List of them.
+func ReadNamespaces(data []byte) ([]*corev1.Namespace, error) {
+	namespaces := make([]*corev1.Namespace, 0)
+	for _, document := range manifest.Split(data) {
+		object := struct {
+			Kind  string            `json:"kind"`
+			Items []json.RawMessage `json:"items"`
+		}{}
+		if err := yaml.Unmarshal(document, &object); err != nil {
+			return nil, fmt.Errorf("failed to parse namespace dump - %s", err.Error())
+		}
+
+		items := [][]byte{document}
+		switch object.Kind {
+		case "Namespace", "Project":
+		case "List", "NamespaceList", "ProjectList":
+			items = make([][]byte, 0, len(object.Items))
+			for _, item := range object.Items {
+				items = append(items, item)
+			}
+		default:
+			return nil, fmt.Errorf("unexpected kind %q in namespace dump", object.Kind)
+		}
+
+		for _, item := range items {
+			ns := &corev1.Namespace{}
+			if err := yaml.Unmarshal(item, ns); err != nil {
+				return nil, fmt.Errorf("failed to parse namespace - %s", err.Error())
+			}
+			namespaces = append(namespaces, ns)
+		}
+	}
+
+	return namespaces, nil
+}
+
+func toInt64(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case float64:
+		if v != float64(int64(v)) {
+			return 0, fmt.Errorf("%v is not an integer", v)
+		}
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	}
+
+	return 0, fmt.Errorf("%v is not an integer", value)
+}
@@ -0,0 +1,175 @@
+package migrate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+)
+
+const inlineMasterConfig = `
+admissionConfig:
+  pluginConfig:
+    ClusterResourceOverride:
+      configuration:
+        apiVersion: v1
+        kind: ClusterResourceOverrideConfig
+        memoryRequestToLimitPercent: 25
+        cpuRequestToLimitPercent: 50
+        limitCPUToMemoryPercent: 200
+`
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name         string
+		masterConfig string
+		files        map[string]string
+		wantSpec     operatorv1.PodResourceOverrideSpec
+		wantWarnings int
+		wantErr      bool
+	}{
+		{
+			name:         "inline configuration",
+			masterConfig: inlineMasterConfig,
+			wantSpec: operatorv1.PodResourceOverrideSpec{
+				MemoryRequestToLimitPercent: 25,
+				CPURequestToLimitPercent:    50,
+				LimitCPUToMemoryPercent:     200,
+			},
+		},
+		{
+			name: "configuration in location",
+			masterConfig: `
+admissionConfig:
+  pluginConfig:
+    ClusterResourceOverride:
+      location: cro.yaml
+`,
+			files: map[string]string{
+				"cro.yaml": "apiVersion: v1\nkind: ClusterResourceOverrideConfig\nmemoryRequestToLimitPercent: 40\n",
+			},
+			wantSpec: operatorv1.PodResourceOverrideSpec{
+				MemoryRequestToLimitPercent: 40,
+			},
+		},
+		{
+			name: "deprecated location and unknown field",
+			masterConfig: `
+kubernetesMasterConfig:
+  admissionConfig:
+    pluginConfig:
+      ClusterResourceOverride:
+        configuration:
+          cpuRequestToLimitPercent: 10
+          unknown: true
+`,
+			wantSpec: operatorv1.PodResourceOverrideSpec{
+				CPURequestToLimitPercent: 10,
+			},
+			wantWarnings: 2,
+		},
+		{
+			name: "invalid value",
+			masterConfig: `
+admissionConfig:
+  pluginConfig:
+    ClusterResourceOverride:
+      configuration:
+        memoryRequestToLimitPercent: 150
+`,
+			wantErr: true,
+		},
+		{
+			name:         "no plugin",
+			masterConfig: "admissionConfig: {}\n",
+			wantErr:      true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := Migrate(&Options{
+				Name:         "cluster",
+				MasterConfig: []byte(test.masterConfig),
+				ReadFile: func(path string) ([]byte, error) {
+					content, ok := test.files[path]
+					if !ok {
+						return nil, errors.New("not found")
+					}
+					return []byte(content), nil
+				},
+				Namespaces: []*corev1.Namespace{
+					{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+				},
+			})
+			if test.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, "cluster", result.ClusterResourceOverride.Name)
+			require.Equal(t, test.wantSpec, result.ClusterResourceOverride.Spec.PodResourceOverride.Spec)
+			require.Len(t, result.Warnings, test.wantWarnings)
+		})
+	}
+}
+
+func TestMigrateNamespaces(t *testing.T) {
+	namespaces, err := ReadNamespaces([]byte(`
+apiVersion: v1
+kind: List
+items:
+- apiVersion: project.openshift.io/v1
+  kind: Project
+  metadata:
+    name: team-b
+- apiVersion: project.openshift.io/v1
+  kind: Project
+  metadata:
+    name: disabled
+    annotations:
+      quota.openshift.io/cluster-resource-override-enabled: "false"
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: team-a
+  annotations:
+    quota.openshift.io/cluster-resource-override-enabled: "true"
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: openshift-monitoring
+`))
+	require.NoError(t, err)
+	require.Len(t, namespaces, 4)
+
+	result, err := Migrate(&Options{
+		Name:         "cluster",
+		MasterConfig: []byte(inlineMasterConfig),
+		Namespaces:   namespaces,
+	})
+	require.NoError(t, err)
+
+	names := make([]string, 0)
+	for _, ns := range result.Namespaces {
+		require.Equal(t, "true", ns.Labels[asset.NamespaceOptInLabelKey])
+		names = append(names, ns.Name)
+	}
+	require.Equal(t, []string{"team-a", "team-b"}, names)
+	require.Len(t, result.Warnings, 1)
+	require.Contains(t, result.Warnings[0], "openshift-monitoring")
+}
+
+func TestReadNamespacesUnexpectedKind(t *testing.T) {
+	_, err := ReadNamespaces([]byte("apiVersion: v1\nkind: Pod\nmetadata:\n  name: foo\n"))
+	require.Error(t, err)
+}
@@ -40,9 +40,33 @@ type Config struct {
 
 	// OperandVersion points to the operand version.
 	OperandVersion string
+
+	// Management runs the operand in the control plane namespace of the
+	// management cluster of a hosted cluster, RestConfig then points to the
+	// hosted cluster. Nil if the operand runs in the cluster of RestConfig.
+	Management *ManagementConfig
+}
+
+// ManagementConfig configures the management cluster the operand runs in.
+type ManagementConfig struct {
+	// RestConfig is the rest.Config of the management cluster.
+	RestConfig *rest.Config
+
+	// Namespace is the control plane namespace of the hosted cluster in the
+	// management cluster.
+	Namespace string
+
+	// HostedKubeconfigSecret is the Secret in Namespace with the kubeconfig
+	// the operand reaches the hosted cluster with.
+	HostedKubeconfigSecret string
 }
 
 func (c *Config) String() string {
+	if c.Management != nil {
+		return fmt.Sprintf("name=%s namespace=%s operand-image=%s operand-version=%s management-namespace=%s hosted-kubeconfig-secret=%s",
+			c.Name, c.Namespace, c.OperandImage, c.OperandVersion, c.Management.Namespace, c.Management.HostedKubeconfigSecret)
+	}
+
 	return fmt.Sprintf("name=%s namespace=%s operand-image=%s operand-version=%s", c.Name, c.Namespace, c.OperandImage, c.OperandVersion)
 }
 
@@ -67,5 +91,19 @@ func (c *Config) Validate() error {
 		return errors.New("no operand version has been specified")
 	}
 
+	if c.Management != nil {
+		if c.Management.RestConfig == nil {
+			return errors.New("no rest.Config has been specified for the management cluster")
+		}
+
+		if c.Management.Namespace == "" {
+			return errors.New("management namespace must be specified")
+		}
+
+		if c.Management.HostedKubeconfigSecret == "" {
+			return errors.New("no hosted kubeconfig Secret has been specified")
+		}
+	}
+
 	return nil
 }
@@ -1,18 +1,24 @@
 package operator
 
 import (
+	"context"
 	"fmt"
+	"math"
 	"net/http"
 	"time"
 
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/infrastructure"
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/secondarywatch"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/klog/v2"
 	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
 
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/clusterresourceoverride"
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/controller"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/metrics"
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/resourceoverride"
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/runtime"
 )
@@ -34,6 +40,16 @@ const (
 	DefaultResyncPeriodSecondaryResource = 15 * time.Hour
 )
 
+// DetectPlatformBackoff is how the platform detection is retried at startup,
+// until it succeeds or the operator shuts down.
+var DetectPlatformBackoff = wait.Backoff{
+	Duration: time.Second,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    math.MaxInt32,
+	Cap:      time.Minute,
+}
+
 func NewRunner() Interface {
 	return &runner{
 		done: make(chan struct{}, 0),
@@ -57,18 +73,49 @@ func (r *runner) Run(config *Config, errorCh chan<- error) {
 	}
 
 	context := runtime.NewOperandContext(config.Name, config.Namespace, DefaultCR, config.OperandImage, config.OperandVersion)
+
+	// the operand runs in the control plane namespace of the management
+	// cluster, the operator manages the hosted cluster.
+	var operandClients *runtime.Client
+	if management := config.Management; management != nil {
+		operandClients, err = runtime.NewClient(management.RestConfig)
+		if err != nil {
+			errorCh <- fmt.Errorf("failed to construct clients for the management cluster - %s", err.Error())
+			return
+		}
+
+		context = runtime.NewHostedOperandContext(config.Name, management.Namespace, DefaultCR, config.OperandImage, config.OperandVersion, management.HostedKubeconfigSecret)
+		klog.V(1).Infof("[operator] operand runs in namespace=%s of the management cluster", management.Namespace)
+	}
 	apiregistrationv1.AddToScheme(scheme.Scheme)
 
-	standalone := infrastructure.IsStandalone(config.ShutdownContext, clients.RawDynamic)
+	// the platform is detected again on every reconcile, it decides here which
+	// OpenShift configuration can be watched.
+	detector := infrastructure.NewDetector(clients.Kubernetes.Discovery(), clients.RawDynamic)
+	platform, err := detectPlatform(config.ShutdownContext, detector)
+	if err != nil {
+		errorCh <- fmt.Errorf("failed to detect the platform - %s", err.Error())
+		return
+	}
+	klog.V(1).Infof("[operator] detected platform type=%s control-plane-topology=%s", platform.Type, platform.ControlPlaneTopology)
 
 	// create lister(s) for secondary resources
 	lister, starter := secondarywatch.New(&secondarywatch.Options{
 		Client:              clients,
 		ResyncPeriod:        DefaultResyncPeriodSecondaryResource,
-		Namespace:           config.Namespace,
+		Namespace:           context.WebhookNamespace(),
 		PrimaryResourceName: DefaultCR,
+		APIServiceName:      asset.New(context).APIService().Name(),
+		OpenShiftConfig:     platform.HasOpenShiftConfig(),
+		Nodes:               config.Management == nil,
+		OperandClient:       operandClients,
 	})
 
+	// the Infrastructure object is read from the cache from now on.
+	if infrastructures := lister.ConfigV1InfrastructureLister(); infrastructures != nil {
+		detector.SetInfrastructureLister(infrastructures)
+	}
+
 	// start the controllers
 	cro, enqueuer, err := clusterresourceoverride.New(&clusterresourceoverride.Options{
 		ResyncPeriod:   DefaultResyncPeriodPrimaryResource,
@@ -76,7 +123,8 @@ func (r *runner) Run(config *Config, errorCh chan<- error) {
 		RuntimeContext: context,
 		Client:         clients,
 		Lister:         lister,
-		IsStandalone:   standalone,
+		Platform:       detector,
+		OperandClient:  operandClients,
 	})
 	if err != nil {
 		errorCh <- fmt.Errorf("failed to create clusterresourceoverride controller - %s", err.Error())
@@ -84,9 +132,10 @@ func (r *runner) Run(config *Config, errorCh chan<- error) {
 	}
 
 	ro, nsWatchStarter, err := resourceoverride.New(&resourceoverride.Options{
-		ResyncPeriod: DefaultResyncPeriodPrimaryResource,
-		Workers:      DefaultWorkerCount,
-		Client:       clients,
+		ResyncPeriod:                DefaultResyncPeriodPrimaryResource,
+		Workers:                     DefaultWorkerCount,
+		Client:                      clients,
+		ClusterResourceOverrideName: DefaultCR,
 	})
 	if err != nil {
 		errorCh <- fmt.Errorf("failed to create resourceoverride controller - %s", err.Error())
@@ -120,11 +169,12 @@ func (r *runner) Run(config *Config, errorCh chan<- error) {
 		return
 	}
 
-	// Serve a simple HTTP health check.
+	// Serve a simple HTTP health check, and the metrics of the operator.
 	healthMux := http.NewServeMux()
 	healthMux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
+	healthMux.Handle("/metrics", metrics.Handler())
 	go http.ListenAndServe(":8080", healthMux)
 
 	errorCh <- nil
@@ -134,6 +184,24 @@ func (r *runner) Run(config *Config, errorCh chan<- error) {
 	<-roRunner.Done()
 }
 
+// detectPlatform retries the detection with DetectPlatformBackoff, the API
+// server may not be reachable yet when the operator starts.
+func detectPlatform(ctx context.Context, detector *infrastructure.Detector) (platform *operatorv1.PlatformStatus, err error) {
+	var detectErr error
+	err = wait.ExponentialBackoffWithContext(ctx, DetectPlatformBackoff, func(ctx context.Context) (bool, error) {
+		platform, detectErr = detector.Detect(ctx)
+		if detectErr != nil {
+			klog.Warningf("[operator] failed to detect the platform, retrying - %s", detectErr.Error())
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil && detectErr != nil {
+		err = detectErr
+	}
+	return
+}
+
 func (r *runner) Done() <-chan struct{} {
 	return r.done
 }
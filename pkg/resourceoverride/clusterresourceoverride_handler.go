@@ -0,0 +1,112 @@
+package resourceoverride
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	controllerreconciler "sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+	listers "github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/listers/autoscaling/v1"
+	operatorruntime "github.com/openshift/cluster-resource-override-admission-operator/pkg/runtime"
+)
+
+// clusterResourceOverrideEventHandler enqueues every ResourceOverride in the
+// cluster when the configuration of the ClusterResourceOverride they fall back
+// to changes, so that their effective policy is recomputed.
+type clusterResourceOverrideEventHandler struct {
+	name     string
+	roLister listers.ResourceOverrideLister
+	queue    workqueue.RateLimitingInterface
+
+	// nsLister, if set, is used to enqueue every opted-in namespace so that
+	// its policy configmap is refreshed.
+	nsLister corev1listers.NamespaceLister
+}
+
+func (h *clusterResourceOverrideEventHandler) OnAdd(obj interface{}, isInInitialList bool) {
+	if isInInitialList {
+		// the initial list of ResourceOverride(s) is enqueued by its own informer.
+		return
+	}
+
+	h.enqueue(obj, "added")
+}
+
+func (h *clusterResourceOverrideEventHandler) OnUpdate(oldObj, newObj interface{}) {
+	oldCRO, ok := oldObj.(*operatorv1.ClusterResourceOverride)
+	if !ok {
+		return
+	}
+	newCRO, ok := newObj.(*operatorv1.ClusterResourceOverride)
+	if !ok {
+		return
+	}
+
+	if configurationHash(oldCRO) == configurationHash(newCRO) {
+		return
+	}
+
+	h.enqueue(newObj, "configuration changed")
+}
+
+func (h *clusterResourceOverrideEventHandler) OnDelete(obj interface{}) {
+	h.enqueue(obj, "deleted")
+}
+
+func (h *clusterResourceOverrideEventHandler) enqueue(obj interface{}, event string) {
+	metaObj, err := operatorruntime.GetMetaObject(obj)
+	if err != nil || metaObj.GetName() != h.name {
+		return
+	}
+
+	ros, err := h.roLister.List(labels.Everything())
+	if err != nil {
+		return
+	}
+
+	for _, ro := range ros {
+		h.queue.Add(controllerreconciler.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: ro.Namespace,
+				Name:      ro.Name,
+			},
+		})
+	}
+
+	klog.V(4).Infof("[resourceoverride] clusterresourceoverride=%s %s, enqueued %d ResourceOverride(s)", metaObj.GetName(), event, len(ros))
+
+	if h.nsLister == nil {
+		return
+	}
+
+	namespaces, err := h.nsLister.List(labels.SelectorFromSet(labels.Set{asset.NamespaceOptInLabelKey: "true"}))
+	if err != nil {
+		return
+	}
+
+	for _, ns := range namespaces {
+		enqueueNamespace(h.queue, ns.Name)
+	}
+
+	klog.V(4).Infof("[resourceoverride] clusterresourceoverride=%s %s, enqueued %d namespace(s)", metaObj.GetName(), event, len(namespaces))
+}
+
+// configurationHash hashes the parts of a ClusterResourceOverride that the
+// status of a ResourceOverride is derived from.
+func configurationHash(cro *operatorv1.ClusterResourceOverride) string {
+	hash := cro.Spec.PodResourceOverride.Spec.Hash()
+	if policy := cro.Spec.ResourceOverridePolicy; policy != nil {
+		hash = fmt.Sprintf("%s,%s", hash, policy.Hash())
+	}
+	if autoOptIn := cro.Spec.AutoOptIn; autoOptIn != nil {
+		hash = fmt.Sprintf("%s,%s", hash, autoOptIn.Hash())
+	}
+
+	return hash
+}
@@ -0,0 +1,131 @@
+package resourceoverride
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	controllerreconciler "sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	autoscalingv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/autoscaling/v1"
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+)
+
+func TestClusterResourceOverrideEventHandler(t *testing.T) {
+	ros := []*autoscalingv1.ResourceOverride{
+		{ObjectMeta: metav1.ObjectMeta{Name: "ro-1", Namespace: "test-ns"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ro-2", Namespace: "test-ns"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ro-3", Namespace: "other-ns"}},
+	}
+	cro := &operatorv1.ClusterResourceOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Generation: 1},
+		Spec: operatorv1.ClusterResourceOverrideSpec{
+			PodResourceOverride: operatorv1.PodResourceOverride{
+				Spec: operatorv1.PodResourceOverrideSpec{
+					MemoryRequestToLimitPercent: 50,
+				},
+			},
+		},
+	}
+	reconfigured := cro.DeepCopy()
+	reconfigured.Spec.PodResourceOverride.Spec.MemoryRequestToLimitPercent = 25
+	restricted := cro.DeepCopy()
+	restricted.Spec.ResourceOverridePolicy = &operatorv1.ResourceOverridePolicy{
+		MemoryRequestToLimitPercent: &operatorv1.PercentRange{Min: 25},
+	}
+	relabeled := cro.DeepCopy()
+	relabeled.Labels = map[string]string{"foo": "bar"}
+	other := cro.DeepCopy()
+	other.Name = "other"
+
+	tests := []struct {
+		name         string
+		invoke       func(h *clusterResourceOverrideEventHandler)
+		wantEnqueued int
+	}{
+		{
+			name:         "added",
+			invoke:       func(h *clusterResourceOverrideEventHandler) { h.OnAdd(cro, false) },
+			wantEnqueued: 3,
+		},
+		{
+			name:         "in initial list",
+			invoke:       func(h *clusterResourceOverrideEventHandler) { h.OnAdd(cro, true) },
+			wantEnqueued: 0,
+		},
+		{
+			name:         "configuration changed",
+			invoke:       func(h *clusterResourceOverrideEventHandler) { h.OnUpdate(cro, reconfigured) },
+			wantEnqueued: 3,
+		},
+		{
+			name:         "policy changed",
+			invoke:       func(h *clusterResourceOverrideEventHandler) { h.OnUpdate(cro, restricted) },
+			wantEnqueued: 3,
+		},
+		{
+			name:         "configuration unchanged",
+			invoke:       func(h *clusterResourceOverrideEventHandler) { h.OnUpdate(cro, relabeled) },
+			wantEnqueued: 0,
+		},
+		{
+			name:         "deleted",
+			invoke:       func(h *clusterResourceOverrideEventHandler) { h.OnDelete(cro) },
+			wantEnqueued: 3,
+		},
+		{
+			name:         "different name",
+			invoke:       func(h *clusterResourceOverrideEventHandler) { h.OnAdd(other, false) },
+			wantEnqueued: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			defer queue.ShutDown()
+
+			handler := &clusterResourceOverrideEventHandler{
+				name:     "cluster",
+				roLister: newTestROLister(ros...),
+				queue:    queue,
+			}
+
+			test.invoke(handler)
+
+			require.Equal(t, test.wantEnqueued, queue.Len())
+		})
+	}
+}
+
+func TestClusterResourceOverrideEventHandlerEnqueuesOptedInNamespaces(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	indexer.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "opted-in",
+		Labels: map[string]string{asset.NamespaceOptInLabelKey: "true"},
+	}})
+	indexer.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "not-opted-in"}})
+
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer queue.ShutDown()
+
+	handler := &clusterResourceOverrideEventHandler{
+		name:     "cluster",
+		roLister: newTestROLister(),
+		queue:    queue,
+		nsLister: corev1listers.NewNamespaceLister(indexer),
+	}
+	handler.OnDelete(&operatorv1.ClusterResourceOverride{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}})
+
+	require.Equal(t, 1, queue.Len())
+	item, _ := queue.Get()
+	require.Equal(t, types.NamespacedName{Namespace: "opted-in"}, item.(controllerreconciler.Request).NamespacedName)
+	queue.Done(item)
+}
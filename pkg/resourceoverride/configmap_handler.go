@@ -0,0 +1,51 @@
+package resourceoverride
+
+import (
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	operatorruntime "github.com/openshift/cluster-resource-override-admission-operator/pkg/runtime"
+)
+
+// configMapEventHandler enqueues the namespace of a policy configmap that is
+// changed or deleted by someone else, so that the operator restores it.
+type configMapEventHandler struct {
+	queue workqueue.RateLimitingInterface
+}
+
+func (h *configMapEventHandler) OnAdd(obj interface{}, isInInitialList bool) {
+	// a configmap left behind in a namespace that is no longer opted in is
+	// removed by the reconciler.
+	h.enqueue(obj, "added")
+}
+
+func (h *configMapEventHandler) OnUpdate(oldObj, newObj interface{}) {
+	oldMetaObj, err := operatorruntime.GetMetaObject(oldObj)
+	if err != nil {
+		return
+	}
+	newMetaObj, err := operatorruntime.GetMetaObject(newObj)
+	if err != nil {
+		return
+	}
+
+	if oldMetaObj.GetResourceVersion() == newMetaObj.GetResourceVersion() {
+		return
+	}
+
+	h.enqueue(newObj, "updated")
+}
+
+func (h *configMapEventHandler) OnDelete(obj interface{}) {
+	h.enqueue(obj, "deleted")
+}
+
+func (h *configMapEventHandler) enqueue(obj interface{}, event string) {
+	metaObj, err := operatorruntime.GetMetaObject(obj)
+	if err != nil {
+		return
+	}
+
+	enqueueNamespace(h.queue, metaObj.GetNamespace())
+	klog.V(4).Infof("[resourceoverride] configmap=%s/%s %s, enqueued namespace", metaObj.GetNamespace(), metaObj.GetName(), event)
+}
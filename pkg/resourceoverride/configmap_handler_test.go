@@ -0,0 +1,70 @@
+package resourceoverride
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	controllerreconciler "sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+)
+
+func TestConfigMapEventHandler(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            asset.NamespacePolicyConfigMapName,
+			Namespace:       "test-ns",
+			ResourceVersion: "1",
+		},
+	}
+	edited := cm.DeepCopy()
+	edited.ResourceVersion = "2"
+
+	tests := []struct {
+		name         string
+		invoke       func(h *configMapEventHandler)
+		wantEnqueued int
+	}{
+		{
+			name:         "added",
+			invoke:       func(h *configMapEventHandler) { h.OnAdd(cm, true) },
+			wantEnqueued: 1,
+		},
+		{
+			name:         "edited",
+			invoke:       func(h *configMapEventHandler) { h.OnUpdate(cm, edited) },
+			wantEnqueued: 1,
+		},
+		{
+			name:         "resync",
+			invoke:       func(h *configMapEventHandler) { h.OnUpdate(cm, cm) },
+			wantEnqueued: 0,
+		},
+		{
+			name:         "deleted",
+			invoke:       func(h *configMapEventHandler) { h.OnDelete(cm) },
+			wantEnqueued: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			defer queue.ShutDown()
+
+			test.invoke(&configMapEventHandler{queue: queue})
+
+			require.Equal(t, test.wantEnqueued, queue.Len())
+			if test.wantEnqueued > 0 {
+				item, _ := queue.Get()
+				require.Equal(t, types.NamespacedName{Namespace: "test-ns"}, item.(controllerreconciler.Request).NamespacedName)
+				queue.Done(item)
+			}
+		})
+	}
+}
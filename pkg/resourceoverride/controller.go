@@ -6,16 +6,24 @@ import (
 	"fmt"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/informers"
+	kubescheme "k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	controllerreconciler "sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	autoscalingv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/autoscaling/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/controller"
+	operatorscheme "github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/clientset/versioned/scheme"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/informers/externalversions"
 	listers "github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/listers/autoscaling/v1"
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/resourceoverride/internal/reconciler"
 	operatorruntime "github.com/openshift/cluster-resource-override-admission-operator/pkg/runtime"
@@ -29,10 +37,14 @@ type Options struct {
 	ResyncPeriod time.Duration
 	Workers      int
 	Client       *operatorruntime.Client
+
+	// ClusterResourceOverrideName is the name of the ClusterResourceOverride
+	// whose configuration a ResourceOverride falls back to.
+	ClusterResourceOverrideName string
 }
 
 func New(options *Options) (c controller.Interface, nsWatchStarter NamespaceWatchStarterFunc, err error) {
-	if options == nil || options.Client == nil || options.Client.Operator == nil || options.Client.Kubernetes == nil {
+	if options == nil || options.Client == nil || options.Client.Operator == nil || options.Client.Kubernetes == nil || options.Client.Metadata == nil {
 		err = errors.New("Invalid input to controller.New")
 		return
 	}
@@ -49,15 +61,19 @@ func New(options *Options) (c controller.Interface, nsWatchStarter NamespaceWatc
 
 	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
 
+	// A change to one ResourceOverride may affect whether its siblings in the
+	// same namespace conflict with it, so the handler enqueues them too.
+	roHandler := newResourceOverrideEventHandler(queue)
 	store, informer := cache.NewInformerWithOptions(cache.InformerOptions{
 		ListerWatcher: watcher,
 		ObjectType:    &autoscalingv1.ResourceOverride{},
-		Handler:       controller.NewEventHandler(queue),
+		Handler:       roHandler,
 		ResyncPeriod:  options.ResyncPeriod,
 		Indexers:      cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
 	})
 
 	lister := listers.NewResourceOverrideLister(store.(cache.Indexer))
+	roHandler.roLister = lister
 
 	nsFactory := informers.NewSharedInformerFactory(options.Client.Kubernetes, options.ResyncPeriod)
 	nsInformer := nsFactory.Core().V1().Namespaces()
@@ -68,18 +84,88 @@ func New(options *Options) (c controller.Interface, nsWatchStarter NamespaceWatc
 		queue:    queue,
 	})
 
+	// Only the metadata of the pods in the namespace(s) where a
+	// ResourceOverride is in effect is cached, the reconciler tells the cache
+	// which namespace(s) to watch.
+	podCache := newPodCache(options.Client.Metadata, options.ResyncPeriod, &podEventHandler{
+		roLister: lister,
+		queue:    queue,
+		delay:    PodEventDelay,
+	}, func(namespace string) {
+		enqueueResourceOverrides(lister, queue, namespace)
+	})
+
+	// Only the policy configmap(s) published by the operator are watched.
+	cmFactory := informers.NewSharedInformerFactoryWithOptions(options.Client.Kubernetes, options.ResyncPeriod,
+		informers.WithTweakListOptions(func(listOptions *metav1.ListOptions) {
+			listOptions.LabelSelector = fmt.Sprintf("%s=true", asset.NamespacePolicyLabelKey)
+		}))
+	cmInformer := cmFactory.Core().V1().ConfigMaps()
+	configMapLister := cmInformer.Lister()
+
+	cmInformer.Informer().AddEventHandler(&configMapEventHandler{
+		queue: queue,
+	})
+
+	croFactory := externalversions.NewSharedInformerFactory(client, options.ResyncPeriod)
+	croInformer := croFactory.Operator().V1().ClusterResourceOverrides()
+	croLister := croInformer.Lister()
+
+	croInformer.Informer().AddEventHandler(&clusterResourceOverrideEventHandler{
+		name:     options.ClusterResourceOverrideName,
+		roLister: lister,
+		queue:    queue,
+		nsLister: namespaceLister,
+	})
+
+	// Events are recorded against ResourceOverride(s) and Namespace(s).
+	eventScheme := runtime.NewScheme()
+	utilruntime.Must(kubescheme.AddToScheme(eventScheme))
+	utilruntime.Must(operatorscheme.AddToScheme(eventScheme))
+	broadcaster := record.NewBroadcaster()
+	recorder := broadcaster.NewRecorder(eventScheme, corev1.EventSource{Component: ControllerName})
+
 	nsWatchStarter = func(ctx context.Context) error {
+		broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{
+			Interface: options.Client.Kubernetes.CoreV1().Events(""),
+		})
+		go func() {
+			<-ctx.Done()
+			broadcaster.Shutdown()
+		}()
+
 		nsFactory.Start(ctx.Done())
+		cmFactory.Start(ctx.Done())
+		croFactory.Start(ctx.Done())
+		podCache.Run(ctx)
+
 		status := nsFactory.WaitForCacheSync(ctx.Done())
+		for objType, synced := range cmFactory.WaitForCacheSync(ctx.Done()) {
+			status[objType] = synced
+		}
+		for objType, synced := range croFactory.WaitForCacheSync(ctx.Done()) {
+			status[objType] = synced
+		}
+
 		for objType, synced := range status {
 			if !synced {
-				return fmt.Errorf("namespace informer cache sync failed for %s", objType.Name())
+				return fmt.Errorf("informer cache sync failed for %s", objType.Name())
 			}
 		}
 		return nil
 	}
 
-	reconciler := reconciler.NewReconciler(client, lister, namespaceLister)
+	reconciler := reconciler.NewReconciler(&reconciler.Options{
+		Client:                        client,
+		KubeClient:                    options.Client.Kubernetes,
+		Recorder:                      recorder,
+		Lister:                        lister,
+		NamespaceLister:               namespaceLister,
+		PodCache:                      podCache,
+		ClusterResourceOverrideLister: croLister,
+		ClusterResourceOverrideName:   options.ClusterResourceOverrideName,
+		ConfigMapLister:               configMapLister,
+	})
 
 	c = &resourceOverrideController{
 		workers:    options.Workers,
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	kubefake "k8s.io/client-go/kubernetes/fake"
+	metadatafake "k8s.io/client-go/metadata/fake"
 
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/clientset/versioned/fake"
 	operatorruntime "github.com/openshift/cluster-resource-override-admission-operator/pkg/runtime"
@@ -45,6 +46,18 @@ func TestNew(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "nil metadata client",
+			options: &Options{
+				ResyncPeriod: 10 * time.Minute,
+				Workers:      1,
+				Client: &operatorruntime.Client{
+					Operator:   fake.NewSimpleClientset(),
+					Kubernetes: kubefake.NewSimpleClientset(),
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "valid options",
 			options: &Options{
@@ -53,6 +66,7 @@ func TestNew(t *testing.T) {
 				Client: &operatorruntime.Client{
 					Operator:   fake.NewSimpleClientset(),
 					Kubernetes: kubefake.NewSimpleClientset(),
+					Metadata:   metadatafake.NewSimpleMetadataClient(metadatafake.NewTestScheme()),
 				},
 			},
 			wantErr:     false,
@@ -81,6 +81,90 @@ func (b *Builder) WithIgnoredCleared() (builder *Builder) {
 	return b
 }
 
+func (b *Builder) WithConflict(reason string, message string) (builder *Builder) {
+	b.init()
+
+	desired := &autoscalingv1.ResourceOverrideCondition{
+		Type:               autoscalingv1.Conflict,
+		Status:             corev1.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: metav1.NewTime(b.clock.Now()),
+	}
+	b.WithCondition(desired)
+
+	return b
+}
+
+func (b *Builder) WithConflictCleared() (builder *Builder) {
+	b.init()
+
+	desired := &autoscalingv1.ResourceOverrideCondition{
+		Type:               autoscalingv1.Conflict,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.NewTime(b.clock.Now()),
+	}
+	b.WithCondition(desired)
+
+	return b
+}
+
+func (b *Builder) WithNoMatchingPods(reason string, message string) (builder *Builder) {
+	b.init()
+
+	desired := &autoscalingv1.ResourceOverrideCondition{
+		Type:               autoscalingv1.NoMatchingPods,
+		Status:             corev1.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: metav1.NewTime(b.clock.Now()),
+	}
+	b.WithCondition(desired)
+
+	return b
+}
+
+func (b *Builder) WithNoMatchingPodsCleared() (builder *Builder) {
+	b.init()
+
+	desired := &autoscalingv1.ResourceOverrideCondition{
+		Type:               autoscalingv1.NoMatchingPods,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.NewTime(b.clock.Now()),
+	}
+	b.WithCondition(desired)
+
+	return b
+}
+
+func (b *Builder) WithPolicyViolation(reason string, message string) (builder *Builder) {
+	b.init()
+
+	desired := &autoscalingv1.ResourceOverrideCondition{
+		Type:               autoscalingv1.PolicyViolation,
+		Status:             corev1.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: metav1.NewTime(b.clock.Now()),
+	}
+	b.WithCondition(desired)
+
+	return b
+}
+
+func (b *Builder) WithPolicyViolationCleared() (builder *Builder) {
+	b.init()
+
+	desired := &autoscalingv1.ResourceOverrideCondition{
+		Type:               autoscalingv1.PolicyViolation,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.NewTime(b.clock.Now()),
+	}
+	b.WithCondition(desired)
+
+	return b
+}
+
 func (b *Builder) WithCondition(desired *autoscalingv1.ResourceOverrideCondition) {
 	if desired == nil {
 		return
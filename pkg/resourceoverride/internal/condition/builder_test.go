@@ -41,6 +41,67 @@ func TestWithValidationCleared(t *testing.T) {
 	require.Empty(t, cond.Message)
 }
 
+func TestWithConflict(t *testing.T) {
+	status := &autoscalingv1.ResourceOverrideStatus{}
+	builder := NewBuilderWithStatus(status)
+
+	builder.WithConflict(autoscalingv1.OverlappingPodSelector, "overlaps")
+
+	require.Len(t, status.Conditions, 1)
+	cond := &status.Conditions[0]
+	require.Equal(t, autoscalingv1.Conflict, cond.Type)
+	require.Equal(t, corev1.ConditionTrue, cond.Status)
+	require.Equal(t, autoscalingv1.OverlappingPodSelector, cond.Reason)
+	require.Equal(t, "overlaps", cond.Message)
+
+	builder.WithConflictCleared()
+
+	require.Len(t, status.Conditions, 1)
+	require.Equal(t, corev1.ConditionFalse, cond.Status)
+	require.Empty(t, cond.Reason)
+	require.Empty(t, cond.Message)
+}
+
+func TestWithNoMatchingPods(t *testing.T) {
+	status := &autoscalingv1.ResourceOverrideStatus{}
+	builder := NewBuilderWithStatus(status)
+
+	builder.WithNoMatchingPods(autoscalingv1.PodSelectorMatchesNone, "no pods")
+
+	require.Len(t, status.Conditions, 1)
+	cond := &status.Conditions[0]
+	require.Equal(t, autoscalingv1.NoMatchingPods, cond.Type)
+	require.Equal(t, corev1.ConditionTrue, cond.Status)
+	require.Equal(t, autoscalingv1.PodSelectorMatchesNone, cond.Reason)
+	require.Equal(t, "no pods", cond.Message)
+
+	builder.WithNoMatchingPodsCleared()
+
+	require.Len(t, status.Conditions, 1)
+	require.Equal(t, corev1.ConditionFalse, cond.Status)
+	require.Empty(t, cond.Reason)
+	require.Empty(t, cond.Message)
+}
+
+func TestWithPolicyViolation(t *testing.T) {
+	status := &autoscalingv1.ResourceOverrideStatus{}
+	builder := NewBuilderWithStatus(status)
+
+	builder.WithPolicyViolation(autoscalingv1.OutsidePolicyBounds, "out of bounds")
+
+	require.Len(t, status.Conditions, 1)
+	cond := &status.Conditions[0]
+	require.Equal(t, autoscalingv1.PolicyViolation, cond.Type)
+	require.Equal(t, corev1.ConditionTrue, cond.Status)
+	require.Equal(t, autoscalingv1.OutsidePolicyBounds, cond.Reason)
+
+	builder.WithPolicyViolationCleared()
+
+	require.Len(t, status.Conditions, 1)
+	require.Equal(t, corev1.ConditionFalse, cond.Status)
+	require.Empty(t, cond.Reason)
+}
+
 func TestWithConditionAppends(t *testing.T) {
 	status := &autoscalingv1.ResourceOverrideStatus{}
 	builder := NewBuilderWithStatus(status)
@@ -0,0 +1,145 @@
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	autoscalingv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/autoscaling/v1"
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/resourceoverride/internal/condition"
+)
+
+const (
+	// NamespaceOptedIn is the reason of the event recorded when a namespace is
+	// opted in automatically.
+	NamespaceOptedIn = "NamespaceOptedIn"
+
+	// NamespaceOptedOut is the reason of the event recorded when a namespace
+	// that was opted in automatically is opted out again.
+	NamespaceOptedOut = "NamespaceOptedOut"
+)
+
+// autoOptIn labels the namespace of the given valid ResourceOverride if the
+// autoOptIn policy of the ClusterResourceOverride allows the namespace to
+// self-enable. It returns true if the namespace has been opted in.
+func (r *reconciler) autoOptIn(current *autoscalingv1.ResourceOverride, ns *corev1.Namespace) (bool, error) {
+	if validation := condition.Find(&current.Status, autoscalingv1.ValidationFailure); validation != nil && validation.Status == corev1.ConditionTrue {
+		return false, nil
+	}
+
+	cro, err := r.getClusterResourceOverride()
+	if err != nil || cro == nil || removed(cro) || !AutoOptInAllowed(cro.Spec.AutoOptIn, ns) {
+		return false, err
+	}
+
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels":      map[string]interface{}{asset.NamespaceOptInLabelKey: "true"},
+			"annotations": map[string]interface{}{asset.NamespaceAutoOptInAnnotationKey: "true"},
+		},
+	}
+	if err := r.patchNamespace(ns.Name, patch); err != nil {
+		return false, err
+	}
+
+	message := fmt.Sprintf("namespace %q opted in to resource overrides by ResourceOverride %s", ns.Name, current.Name)
+	r.recorder.Event(ns, corev1.EventTypeNormal, NamespaceOptedIn, message)
+	r.recorder.Event(current, corev1.EventTypeNormal, NamespaceOptedIn, message)
+
+	klog.V(2).Infof("[reconciler] key=%s/%s %s", current.Namespace, current.Name, message)
+	return true, nil
+}
+
+// AutoOptInAllowed returns true if the given autoOptIn policy allows the
+// namespace to self-enable by creating a ResourceOverride.
+func AutoOptInAllowed(policy *operatorv1.AutoOptInPolicy, ns *corev1.Namespace) bool {
+	if policy == nil {
+		return false
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&policy.NamespaceSelector)
+	if err != nil {
+		// an invalid namespaceSelector is reported on the ClusterResourceOverride.
+		return false
+	}
+
+	// a project opted out with the legacy annotation stays opted out.
+	return selector.Matches(labels.Set(ns.Labels)) && !legacyOptedOut(ns)
+}
+
+// OptedIn returns true if the given namespace is opted in once the operator has
+// reconciled a valid ResourceOverride in it: a boolean legacy annotation wins
+// over the opt-in label, and a namespace without the label may still be opted
+// in by the given autoOptIn policy.
+func OptedIn(ns *corev1.Namespace, policy *operatorv1.AutoOptInPolicy) bool {
+	if value, ok := ns.Annotations[asset.LegacyOverrideEnabledAnnotationKey]; ok {
+		if enabled, err := strconv.ParseBool(value); err == nil {
+			return enabled
+		}
+	}
+
+	return ns.Labels[asset.NamespaceOptInLabelKey] == "true" || AutoOptInAllowed(policy, ns)
+}
+
+// autoOptOut removes the opt-in label from the given namespace if it was added
+// by autoOptIn and there is no ResourceOverride left in the namespace.
+func (r *reconciler) autoOptOut(namespace string) error {
+	ns, err := r.namespaceLister.Get(namespace)
+	if err != nil {
+		// the namespace itself may have been deleted.
+		return nil
+	}
+
+	if ns.Annotations[asset.NamespaceAutoOptInAnnotationKey] != "true" || ns.DeletionTimestamp != nil {
+		return nil
+	}
+
+	ros, err := r.lister.ResourceOverrides(namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	for _, ro := range ros {
+		if ro.DeletionTimestamp == nil {
+			return nil
+		}
+	}
+
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels":      map[string]interface{}{asset.NamespaceOptInLabelKey: nil},
+			"annotations": map[string]interface{}{asset.NamespaceAutoOptInAnnotationKey: nil},
+		},
+	}
+	if err := r.patchNamespace(namespace, patch); err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("namespace %q opted out of resource overrides after its last ResourceOverride was deleted", namespace)
+	r.recorder.Event(ns, corev1.EventTypeNormal, NamespaceOptedOut, message)
+
+	klog.V(2).Infof("[reconciler] namespace=%s %s", namespace, message)
+	return nil
+}
+
+func (r *reconciler) patchNamespace(name string, patch map[string]interface{}) error {
+	bytes, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.kubeClient.CoreV1().Namespaces().Patch(context.TODO(), name, types.MergePatchType, bytes, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to patch namespace %q - %s", name, err.Error())
+	}
+
+	return nil
+}
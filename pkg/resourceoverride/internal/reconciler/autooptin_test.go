@@ -0,0 +1,250 @@
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	controllerreconciler "sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	autoscalingv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/autoscaling/v1"
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/clientset/versioned/fake"
+	autoscalingv1listers "github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/listers/autoscaling/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/resourceoverride/internal/condition"
+)
+
+func TestAutoOptIn(t *testing.T) {
+	cro := &operatorv1.ClusterResourceOverride{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+		},
+		Spec: operatorv1.ClusterResourceOverrideSpec{
+			AutoOptIn: &operatorv1.AutoOptInPolicy{
+				NamespaceSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{"tenant": "true"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		nsLabels      map[string]string
+		nsAnnotations map[string]string
+		percent       int64
+		wantOptedIn   bool
+	}{
+		{
+			name:        "selected namespace is opted in",
+			nsLabels:    map[string]string{"tenant": "true"},
+			percent:     50,
+			wantOptedIn: true,
+		},
+		{
+			name:        "namespace not selected",
+			nsLabels:    map[string]string{"tenant": "false"},
+			percent:     50,
+			wantOptedIn: false,
+		},
+		{
+			name:          "namespace opted out by legacy annotation",
+			nsLabels:      map[string]string{"tenant": "true"},
+			nsAnnotations: map[string]string{asset.LegacyOverrideEnabledAnnotationKey: "false"},
+			percent:       50,
+			wantOptedIn:   false,
+		},
+		{
+			name:        "invalid ResourceOverride does not opt in",
+			nsLabels:    map[string]string{"tenant": "true"},
+			percent:     200,
+			wantOptedIn: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ro := &autoscalingv1.ResourceOverride{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-ro",
+					Namespace: "tenant-a",
+				},
+				Spec: autoscalingv1.ResourceOverrideSpec{
+					PodResourceOverride: autoscalingv1.PodResourceOverrideSpec{
+						MemoryRequestToLimitPercent: test.percent,
+					},
+				},
+			}
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "tenant-a",
+					Labels:      test.nsLabels,
+					Annotations: test.nsAnnotations,
+				},
+			}
+
+			fakeClient := fake.NewSimpleClientset(ro)
+			kubeClient := kubefake.NewSimpleClientset(ns)
+			recorder := record.NewFakeRecorder(10)
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			indexer.Add(ro)
+
+			r := NewReconciler(&Options{
+				Client:                        fakeClient,
+				KubeClient:                    kubeClient,
+				Recorder:                      recorder,
+				Lister:                        autoscalingv1listers.NewResourceOverrideLister(indexer),
+				NamespaceLister:               newNamespaceLister(ns),
+				PodCache:                      newPodCache(),
+				ClusterResourceOverrideLister: newClusterResourceOverrideLister(cro),
+				ClusterResourceOverrideName:   "cluster",
+			})
+			_, err := r.Reconcile(t.Context(), controllerreconciler.Request{
+				NamespacedName: types.NamespacedName{Namespace: "tenant-a", Name: "test-ro"},
+			})
+			require.NoError(t, err)
+
+			updatedNS, err := kubeClient.CoreV1().Namespaces().Get(t.Context(), "tenant-a", metav1.GetOptions{})
+			require.NoError(t, err)
+
+			updated, err := fakeClient.AutoscalingV1().ResourceOverrides("tenant-a").Get(t.Context(), "test-ro", metav1.GetOptions{})
+			require.NoError(t, err)
+			ignored := condition.Find(&updated.Status, autoscalingv1.Ignored)
+			require.NotNil(t, ignored)
+
+			if !test.wantOptedIn {
+				require.Empty(t, updatedNS.Labels[asset.NamespaceOptInLabelKey])
+				require.Equal(t, corev1.ConditionTrue, ignored.Status)
+				require.Len(t, recorder.Events, 0)
+				return
+			}
+
+			require.Equal(t, "true", updatedNS.Labels[asset.NamespaceOptInLabelKey])
+			require.Equal(t, "true", updatedNS.Annotations[asset.NamespaceAutoOptInAnnotationKey])
+			require.Equal(t, corev1.ConditionFalse, ignored.Status)
+			require.Len(t, recorder.Events, 2)
+			require.Contains(t, <-recorder.Events, NamespaceOptedIn)
+		})
+	}
+}
+
+func TestAutoOptOut(t *testing.T) {
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		remaining    []*autoscalingv1.ResourceOverride
+		wantOptedOut bool
+	}{
+		{
+			name:         "last ResourceOverride deleted",
+			annotations:  map[string]string{asset.NamespaceAutoOptInAnnotationKey: "true"},
+			wantOptedOut: true,
+		},
+		{
+			name:        "ResourceOverride(s) remain",
+			annotations: map[string]string{asset.NamespaceAutoOptInAnnotationKey: "true"},
+			remaining: []*autoscalingv1.ResourceOverride{
+				{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "tenant-a"}},
+			},
+			wantOptedOut: false,
+		},
+		{
+			name:         "namespace opted in by an administrator",
+			wantOptedOut: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "tenant-a",
+					Labels:      map[string]string{asset.NamespaceOptInLabelKey: "true"},
+					Annotations: test.annotations,
+				},
+			}
+
+			kubeClient := kubefake.NewSimpleClientset(ns)
+			recorder := record.NewFakeRecorder(10)
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			for _, ro := range test.remaining {
+				indexer.Add(ro)
+			}
+
+			r := NewReconciler(&Options{
+				Client:                        fake.NewSimpleClientset(),
+				KubeClient:                    kubeClient,
+				Recorder:                      recorder,
+				Lister:                        autoscalingv1listers.NewResourceOverrideLister(indexer),
+				NamespaceLister:               newNamespaceLister(ns),
+				PodCache:                      newPodCache(),
+				ClusterResourceOverrideLister: newClusterResourceOverrideLister(),
+				ClusterResourceOverrideName:   "cluster",
+			})
+			_, err := r.Reconcile(t.Context(), controllerreconciler.Request{
+				NamespacedName: types.NamespacedName{Namespace: "tenant-a", Name: "deleted"},
+			})
+			require.NoError(t, err)
+
+			updatedNS, err := kubeClient.CoreV1().Namespaces().Get(t.Context(), "tenant-a", metav1.GetOptions{})
+			require.NoError(t, err)
+
+			if !test.wantOptedOut {
+				require.Equal(t, "true", updatedNS.Labels[asset.NamespaceOptInLabelKey])
+				require.Len(t, recorder.Events, 0)
+				return
+			}
+
+			require.NotContains(t, updatedNS.Labels, asset.NamespaceOptInLabelKey)
+			require.NotContains(t, updatedNS.Annotations, asset.NamespaceAutoOptInAnnotationKey)
+			require.Contains(t, <-recorder.Events, NamespaceOptedOut)
+		})
+	}
+}
+
+func TestOptedIn(t *testing.T) {
+	policy := &operatorv1.AutoOptInPolicy{
+		NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+	}
+
+	tests := []struct {
+		name        string
+		labels      map[string]string
+		annotations map[string]string
+		policy      *operatorv1.AutoOptInPolicy
+		want        bool
+	}{
+		{name: "labeled", labels: map[string]string{asset.NamespaceOptInLabelKey: "true"}, want: true},
+		{name: "not labeled", want: false},
+		{name: "selected by autoOptIn", labels: map[string]string{"team": "a"}, policy: policy, want: true},
+		{name: "not selected by autoOptIn", labels: map[string]string{"team": "b"}, policy: policy, want: false},
+		{name: "legacy annotation opts in", annotations: map[string]string{asset.LegacyOverrideEnabledAnnotationKey: "true"}, want: true},
+		{
+			name:        "legacy annotation opts out",
+			labels:      map[string]string{asset.NamespaceOptInLabelKey: "true", "team": "a"},
+			annotations: map[string]string{asset.LegacyOverrideEnabledAnnotationKey: "false"},
+			policy:      policy,
+			want:        false,
+		},
+		{
+			name:        "invalid legacy annotation is ignored",
+			labels:      map[string]string{asset.NamespaceOptInLabelKey: "true"},
+			annotations: map[string]string{asset.LegacyOverrideEnabledAnnotationKey: "maybe"},
+			want:        true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test", Labels: test.labels, Annotations: test.annotations}}
+			require.Equal(t, test.want, OptedIn(ns, test.policy))
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package reconciler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	autoscalingv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/autoscaling/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/resourceoverride/internal/condition"
+)
+
+// checkConflicts compares the given pods selected by the given ResourceOverride
+// with those of every other valid ResourceOverride in the same namespace and
+// sets the Conflict condition, naming the ResourceOverride(s) it overlaps with.
+func (r *reconciler) checkConflicts(current *autoscalingv1.ResourceOverride, pods []metav1.Object) error {
+	builder := condition.NewBuilderWithStatus(&current.Status)
+
+	matcher, err := NewMatcher(&current.Spec)
+	if err != nil || current.Spec.PodResourceOverride.Validate() != nil {
+		// an invalid spec is already reported as a validation failure.
+		builder.WithConflictCleared()
+		return nil
+	}
+
+	siblings, err := r.lister.ResourceOverrides(current.Namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	conflicts := make([]*autoscalingv1.ResourceOverride, 0)
+	for _, sibling := range siblings {
+		if sibling.Name == current.Name || sibling.DeletionTimestamp != nil {
+			continue
+		}
+
+		siblingMatcher, err := NewMatcher(&sibling.Spec)
+		if err != nil || sibling.Spec.PodResourceOverride.Validate() != nil {
+			continue
+		}
+
+		if Overlaps(matcher, siblingMatcher, pods) {
+			conflicts = append(conflicts, sibling)
+		}
+	}
+
+	if len(conflicts) == 0 {
+		builder.WithConflictCleared()
+		return nil
+	}
+
+	builder.WithConflict(autoscalingv1.OverlappingPodSelector, conflictMessage(current, conflicts))
+	return nil
+}
+
+// PodSelectorAsSelector converts the podSelector of a ResourceOverride into a
+// labels.Selector. A nil podSelector selects all pods in the namespace.
+func PodSelectorAsSelector(podSelector *metav1.LabelSelector) (labels.Selector, error) {
+	if podSelector == nil {
+		return labels.Everything(), nil
+	}
+
+	return metav1.LabelSelectorAsSelector(podSelector)
+}
+
+// Overlaps returns true if the two matchers both select at least one of the
+// given pods, or if one matcher provably selects every pod the other does.
+func Overlaps(this, that *Matcher, pods []metav1.Object) bool {
+	if this.Covers(that) || that.Covers(this) {
+		return true
+	}
+
+	for _, pod := range pods {
+		if this.Matches(pod) && that.Matches(pod) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// covers returns true if every pod matched by inner is also matched by outer,
+// which holds when each requirement of outer is also a requirement of inner.
+func covers(outer, inner labels.Selector) bool {
+	outerRequirements, selectable := outer.Requirements()
+	if !selectable {
+		return false
+	}
+
+	innerRequirements, selectable := inner.Requirements()
+	if !selectable {
+		return false
+	}
+
+	for _, o := range outerRequirements {
+		found := false
+		for _, i := range innerRequirements {
+			if o.Equal(i) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func conflictMessage(current *autoscalingv1.ResourceOverride, conflicts []*autoscalingv1.ResourceOverride) string {
+	names := make([]string, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		names = append(names, conflict.Name)
+	}
+	sort.Strings(names)
+
+	return fmt.Sprintf("podSelector overlaps with ResourceOverride(s) %s in namespace %q, the admission webhook applies only one of them to a pod", strings.Join(names, ", "), current.Namespace)
+}
@@ -0,0 +1,84 @@
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	autoscalingv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/autoscaling/v1"
+)
+
+func TestOverlaps(t *testing.T) {
+	tests := []struct {
+		name string
+		this *metav1.LabelSelector
+		that *metav1.LabelSelector
+		pods []metav1.Object
+		want bool
+	}{
+		{
+			name: "nil selector overlaps with any selector",
+			this: nil,
+			that: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			want: true,
+		},
+		{
+			name: "identical selectors overlap",
+			this: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			that: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			want: true,
+		},
+		{
+			name: "more specific selector is covered by a broader one",
+			this: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			that: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "web"},
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "tier", Operator: metav1.LabelSelectorOpIn, Values: []string{"frontend", "backend"}},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "disjoint selectors without pods do not overlap",
+			this: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			that: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "frontend"}},
+			want: false,
+		},
+		{
+			name: "selectors matching the same pod overlap",
+			this: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			that: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "frontend"}},
+			pods: []metav1.Object{
+				&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "db", Labels: map[string]string{"app": "db", "tier": "frontend"}}},
+				&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Labels: map[string]string{"app": "web", "tier": "frontend"}}},
+			},
+			want: true,
+		},
+		{
+			name: "selectors matching different pods do not overlap",
+			this: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			that: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+			pods: []metav1.Object{
+				&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "db", Labels: map[string]string{"app": "db"}}},
+				&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Labels: map[string]string{"app": "web"}}},
+			},
+			want: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			this, err := NewMatcher(&autoscalingv1.ResourceOverrideSpec{PodSelector: test.this})
+			require.NoError(t, err)
+			that, err := NewMatcher(&autoscalingv1.ResourceOverrideSpec{PodSelector: test.that})
+			require.NoError(t, err)
+
+			require.Equal(t, test.want, Overlaps(this, that, test.pods))
+			require.Equal(t, test.want, Overlaps(that, this, test.pods))
+		})
+	}
+}
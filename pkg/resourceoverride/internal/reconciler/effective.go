@@ -0,0 +1,48 @@
+package reconciler
+
+import (
+	autoscalingv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/autoscaling/v1"
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+)
+
+// Effective merges the given ResourceOverride spec with the ClusterResourceOverride
+// spec the same way the admission webhook does: a field the ResourceOverride
+// leaves at zero falls back to the ClusterResourceOverride.
+// cluster may be nil if there is no ClusterResourceOverride.
+func Effective(ro *autoscalingv1.PodResourceOverrideSpec, cluster *operatorv1.PodResourceOverrideSpec) *autoscalingv1.EffectivePodResourceOverride {
+	if cluster == nil {
+		cluster = &operatorv1.PodResourceOverrideSpec{}
+	}
+
+	effective := &autoscalingv1.EffectivePodResourceOverride{
+		ForceSelinuxRelabel:         effectiveBool(ro.ForceSelinuxRelabel, cluster.ForceSelinuxRelabel),
+		LimitCPUToMemoryPercent:     effectiveInt64(ro.LimitCPUToMemoryPercent, cluster.LimitCPUToMemoryPercent),
+		CPURequestToLimitPercent:    effectiveInt64(ro.CPURequestToLimitPercent, cluster.CPURequestToLimitPercent),
+		MemoryRequestToLimitPercent: effectiveInt64(ro.MemoryRequestToLimitPercent, cluster.MemoryRequestToLimitPercent),
+		CPURequestToRequestPercent:  effectiveInt64(ro.CPURequestToRequestPercent, cluster.CPURequestToRequestPercent),
+	}
+
+	return effective
+}
+
+func effectiveBool(ro, cluster bool) autoscalingv1.EffectiveBoolValue {
+	switch {
+	case ro:
+		return autoscalingv1.EffectiveBoolValue{Value: ro, Source: autoscalingv1.SourceResourceOverride}
+	case cluster:
+		return autoscalingv1.EffectiveBoolValue{Value: cluster, Source: autoscalingv1.SourceClusterResourceOverride}
+	}
+
+	return autoscalingv1.EffectiveBoolValue{Source: autoscalingv1.SourceUnset}
+}
+
+func effectiveInt64(ro, cluster int64) autoscalingv1.EffectiveInt64Value {
+	switch {
+	case ro != 0:
+		return autoscalingv1.EffectiveInt64Value{Value: ro, Source: autoscalingv1.SourceResourceOverride}
+	case cluster != 0:
+		return autoscalingv1.EffectiveInt64Value{Value: cluster, Source: autoscalingv1.SourceClusterResourceOverride}
+	}
+
+	return autoscalingv1.EffectiveInt64Value{Source: autoscalingv1.SourceUnset}
+}
@@ -0,0 +1,58 @@
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	autoscalingv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/autoscaling/v1"
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+)
+
+func TestEffective(t *testing.T) {
+	tests := []struct {
+		name    string
+		ro      autoscalingv1.PodResourceOverrideSpec
+		cluster *operatorv1.PodResourceOverrideSpec
+		want    *autoscalingv1.EffectivePodResourceOverride
+	}{
+		{
+			name: "no ClusterResourceOverride",
+			ro: autoscalingv1.PodResourceOverrideSpec{
+				MemoryRequestToLimitPercent: 50,
+			},
+			want: &autoscalingv1.EffectivePodResourceOverride{
+				ForceSelinuxRelabel:         autoscalingv1.EffectiveBoolValue{Source: autoscalingv1.SourceUnset},
+				LimitCPUToMemoryPercent:     autoscalingv1.EffectiveInt64Value{Source: autoscalingv1.SourceUnset},
+				CPURequestToLimitPercent:    autoscalingv1.EffectiveInt64Value{Source: autoscalingv1.SourceUnset},
+				MemoryRequestToLimitPercent: autoscalingv1.EffectiveInt64Value{Value: 50, Source: autoscalingv1.SourceResourceOverride},
+				CPURequestToRequestPercent:  autoscalingv1.EffectiveInt64Value{Source: autoscalingv1.SourceUnset},
+			},
+		},
+		{
+			name: "ResourceOverride wins over ClusterResourceOverride",
+			ro: autoscalingv1.PodResourceOverrideSpec{
+				MemoryRequestToLimitPercent: 50,
+			},
+			cluster: &operatorv1.PodResourceOverrideSpec{
+				ForceSelinuxRelabel:         true,
+				MemoryRequestToLimitPercent: 25,
+				CPURequestToLimitPercent:    10,
+			},
+			want: &autoscalingv1.EffectivePodResourceOverride{
+				ForceSelinuxRelabel:         autoscalingv1.EffectiveBoolValue{Value: true, Source: autoscalingv1.SourceClusterResourceOverride},
+				LimitCPUToMemoryPercent:     autoscalingv1.EffectiveInt64Value{Source: autoscalingv1.SourceUnset},
+				CPURequestToLimitPercent:    autoscalingv1.EffectiveInt64Value{Value: 10, Source: autoscalingv1.SourceClusterResourceOverride},
+				MemoryRequestToLimitPercent: autoscalingv1.EffectiveInt64Value{Value: 50, Source: autoscalingv1.SourceResourceOverride},
+				CPURequestToRequestPercent:  autoscalingv1.EffectiveInt64Value{Source: autoscalingv1.SourceUnset},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := Effective(&test.ro, test.cluster)
+			require.Equal(t, test.want, got)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package reconciler
+
+import (
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+)
+
+const (
+	// LegacyAnnotationTranslated is the reason of the event recorded when the
+	// opt-in label of a namespace is changed to match its legacy annotation.
+	LegacyAnnotationTranslated = "LegacyAnnotationTranslated"
+
+	// LegacyAnnotationInvalid is the reason of the event recorded when the
+	// legacy annotation of a namespace is not a boolean.
+	LegacyAnnotationInvalid = "LegacyAnnotationInvalid"
+)
+
+// translateLegacyAnnotation carries the per-project setting of the OpenShift 3
+// ClusterResourceOverride admission plugin over to the opt-in label: a project
+// annotated "true" is opted in and one annotated "false" is opted out. Once the
+// label matches, the annotation may be removed, the label is left as is.
+// Nothing is translated while the admission webhook is being removed.
+func (r *reconciler) translateLegacyAnnotation(namespace string) error {
+	ns, err := r.namespaceLister.Get(namespace)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	value, ok := ns.Annotations[asset.LegacyOverrideEnabledAnnotationKey]
+	if !ok || ns.DeletionTimestamp != nil {
+		return nil
+	}
+
+	cro, err := r.getClusterResourceOverride()
+	if err != nil || removed(cro) {
+		return err
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		r.recorder.Event(ns, corev1.EventTypeWarning, LegacyAnnotationInvalid,
+			fmt.Sprintf("annotation %s=%q is not a boolean, ignoring it", asset.LegacyOverrideEnabledAnnotationKey, value))
+		return nil
+	}
+
+	_, labeled := ns.Labels[asset.NamespaceOptInLabelKey]
+	var patch map[string]interface{}
+	switch {
+	case enabled && ns.Labels[asset.NamespaceOptInLabelKey] != "true":
+		patch = map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"labels":      map[string]interface{}{asset.NamespaceOptInLabelKey: "true"},
+				"annotations": map[string]interface{}{asset.NamespaceLegacyOptInAnnotationKey: "true"},
+			},
+		}
+	case !enabled && labeled:
+		patch = map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"labels":      map[string]interface{}{asset.NamespaceOptInLabelKey: nil},
+				"annotations": map[string]interface{}{asset.NamespaceAutoOptInAnnotationKey: nil, asset.NamespaceLegacyOptInAnnotationKey: nil},
+			},
+		}
+	default:
+		return nil
+	}
+
+	if err := r.patchNamespace(namespace, patch); err != nil {
+		return err
+	}
+
+	action := "opted in to"
+	if !enabled {
+		action = "opted out of"
+	}
+	message := fmt.Sprintf("namespace %q %s resource overrides as set by annotation %s=%q", namespace, action, asset.LegacyOverrideEnabledAnnotationKey, value)
+	r.recorder.Event(ns, corev1.EventTypeNormal, LegacyAnnotationTranslated, message)
+
+	klog.V(2).Infof("[reconciler] namespace=%s %s", namespace, message)
+	return nil
+}
+
+// legacyOptedOut returns true if the given namespace carries the legacy
+// annotation that disables overrides.
+func legacyOptedOut(ns *corev1.Namespace) bool {
+	value, ok := ns.Annotations[asset.LegacyOverrideEnabledAnnotationKey]
+	if !ok {
+		return false
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	return err == nil && !enabled
+}
@@ -0,0 +1,152 @@
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	controllerreconciler "sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/clientset/versioned/fake"
+	autoscalingv1listers "github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/listers/autoscaling/v1"
+)
+
+func TestTranslateLegacyAnnotation(t *testing.T) {
+	tests := []struct {
+		name        string
+		labels      map[string]string
+		annotations map[string]string
+		cro         *operatorv1.ClusterResourceOverride
+		wantLabel   string
+		wantMarked  bool
+		wantEvent   string
+	}{
+		{
+			name:        "enabled opts in",
+			annotations: map[string]string{asset.LegacyOverrideEnabledAnnotationKey: "true"},
+			wantLabel:   "true",
+			wantMarked:  true,
+			wantEvent:   LegacyAnnotationTranslated,
+		},
+		{
+			name:        "not translated once the admission webhook is removed",
+			annotations: map[string]string{asset.LegacyOverrideEnabledAnnotationKey: "true"},
+			cro: &operatorv1.ClusterResourceOverride{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec:       operatorv1.ClusterResourceOverrideSpec{ManagementState: operatorv1.Removed},
+			},
+		},
+		{
+			name:   "disabled opts out",
+			labels: map[string]string{asset.NamespaceOptInLabelKey: "true"},
+			annotations: map[string]string{
+				asset.LegacyOverrideEnabledAnnotationKey: "false",
+				asset.NamespaceAutoOptInAnnotationKey:    "true",
+			},
+			wantEvent: LegacyAnnotationTranslated,
+		},
+		{
+			name:        "already translated",
+			labels:      map[string]string{asset.NamespaceOptInLabelKey: "true"},
+			annotations: map[string]string{asset.LegacyOverrideEnabledAnnotationKey: "true"},
+			wantLabel:   "true",
+		},
+		{
+			name:        "invalid value is ignored",
+			annotations: map[string]string{asset.LegacyOverrideEnabledAnnotationKey: "maybe"},
+			wantEvent:   LegacyAnnotationInvalid,
+		},
+		{
+			name:      "no annotation",
+			labels:    map[string]string{asset.NamespaceOptInLabelKey: "true"},
+			wantLabel: "true",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cros := []*operatorv1.ClusterResourceOverride{}
+			if test.cro != nil {
+				cros = append(cros, test.cro)
+			}
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "legacy",
+					Labels:      test.labels,
+					Annotations: test.annotations,
+				},
+			}
+
+			kubeClient := kubefake.NewSimpleClientset(ns)
+			recorder := record.NewFakeRecorder(10)
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+
+			r := NewReconciler(&Options{
+				Client:                        fake.NewSimpleClientset(),
+				KubeClient:                    kubeClient,
+				Recorder:                      recorder,
+				Lister:                        autoscalingv1listers.NewResourceOverrideLister(indexer),
+				NamespaceLister:               newNamespaceLister(ns),
+				PodCache:                      newPodCache(),
+				ClusterResourceOverrideLister: newClusterResourceOverrideLister(cros...),
+				ClusterResourceOverrideName:   "cluster",
+				ConfigMapLister:               newConfigMapLister(),
+			})
+			require.NoError(t, r.translateLegacyAnnotation("legacy"))
+
+			updated, err := kubeClient.CoreV1().Namespaces().Get(t.Context(), "legacy", metav1.GetOptions{})
+			require.NoError(t, err)
+			require.Equal(t, test.wantLabel, updated.Labels[asset.NamespaceOptInLabelKey])
+			if test.wantLabel == "" {
+				require.Empty(t, updated.Annotations[asset.NamespaceAutoOptInAnnotationKey])
+			}
+			require.Equal(t, test.wantMarked, updated.Annotations[asset.NamespaceLegacyOptInAnnotationKey] == "true")
+
+			if test.wantEvent == "" {
+				require.Len(t, recorder.Events, 0)
+				return
+			}
+			require.Len(t, recorder.Events, 1)
+			require.Contains(t, <-recorder.Events, test.wantEvent)
+		})
+	}
+}
+
+func TestReconcileNamespaceTranslatesLegacyAnnotation(t *testing.T) {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "legacy",
+			Annotations: map[string]string{asset.LegacyOverrideEnabledAnnotationKey: "true"},
+		},
+	}
+
+	kubeClient := kubefake.NewSimpleClientset(ns)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	r := NewReconciler(&Options{
+		Client:                        fake.NewSimpleClientset(),
+		KubeClient:                    kubeClient,
+		Recorder:                      record.NewFakeRecorder(10),
+		Lister:                        autoscalingv1listers.NewResourceOverrideLister(indexer),
+		NamespaceLister:               newNamespaceLister(ns),
+		PodCache:                      newPodCache(),
+		ClusterResourceOverrideLister: newClusterResourceOverrideLister(),
+		ClusterResourceOverrideName:   "cluster",
+		ConfigMapLister:               newConfigMapLister(),
+	})
+	_, err := r.Reconcile(t.Context(), controllerreconciler.Request{
+		NamespacedName: types.NamespacedName{Namespace: "legacy"},
+	})
+	require.NoError(t, err)
+
+	updated, err := kubeClient.CoreV1().Namespaces().Get(t.Context(), "legacy", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "true", updated.Labels[asset.NamespaceOptInLabelKey])
+}
@@ -0,0 +1,118 @@
+package reconciler
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	autoscalingv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/autoscaling/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/resourceoverride/internal/condition"
+)
+
+const (
+	// MaxMatchedPodSamples is the maximum number of pod names reported in
+	// the status of a ResourceOverride.
+	MaxMatchedPodSamples = 5
+)
+
+// watchPods caches the pods in the namespace of the given ResourceOverride
+// while it is in effect and returns them, synced is false until they are
+// cached. In a namespace that is not opted in, the pods are not cached and the
+// matched pods and conflicts are cleared.
+func (r *reconciler) watchPods(current *autoscalingv1.ResourceOverride) (pods []metav1.Object, synced bool, err error) {
+	ignored := condition.Find(&current.Status, autoscalingv1.Ignored)
+	if ignored != nil && ignored.Status == corev1.ConditionTrue {
+		r.podCache.Forget(current.Namespace)
+
+		builder := condition.NewBuilderWithStatus(&current.Status)
+		builder.WithConflictCleared()
+		builder.WithNoMatchingPodsCleared()
+		current.Status.MatchedPods = 0
+		current.Status.MatchedPodSamples = nil
+		return
+	}
+
+	r.podCache.Watch(current.Namespace)
+	return r.podCache.Pods(current.Namespace)
+}
+
+// forgetPods stops caching the pods in the given namespace once there is no
+// ResourceOverride left in it.
+func (r *reconciler) forgetPods(namespace string) error {
+	ros, err := r.lister.ResourceOverrides(namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	for _, ro := range ros {
+		if ro.DeletionTimestamp == nil {
+			return nil
+		}
+	}
+
+	r.podCache.Forget(namespace)
+	return nil
+}
+
+// countMatchedPods records the number of the given pods selected by the given
+// ResourceOverride, along with a sample of their names, and sets the
+// NoMatchingPods condition if it selects none. lastMatchedTime is moved only
+// when the count or the sample changes, so that an unchanged match does not
+// rewrite the status.
+func (r *reconciler) countMatchedPods(current *autoscalingv1.ResourceOverride, pods []metav1.Object) {
+	builder := condition.NewBuilderWithStatus(&current.Status)
+
+	matcher, err := NewMatcher(&current.Spec)
+	if err != nil {
+		// an invalid podSelector is already reported as a validation failure.
+		current.Status.MatchedPods = 0
+		current.Status.MatchedPodSamples = nil
+		builder.WithNoMatchingPodsCleared()
+		return
+	}
+
+	names := MatchedPodNames(matcher, pods)
+	samples := names
+	if len(samples) > MaxMatchedPodSamples {
+		samples = samples[:MaxMatchedPodSamples]
+	}
+	if len(samples) == 0 {
+		samples = nil
+	}
+
+	matched := int32(len(names))
+	if matched > 0 && (current.Status.LastMatchedTime == nil ||
+		current.Status.MatchedPods != matched ||
+		!reflect.DeepEqual(current.Status.MatchedPodSamples, samples)) {
+		now := metav1.Now()
+		current.Status.LastMatchedTime = &now
+	}
+
+	current.Status.MatchedPods = matched
+	current.Status.MatchedPodSamples = samples
+
+	if matched == 0 {
+		builder.WithNoMatchingPods(autoscalingv1.PodSelectorMatchesNone, fmt.Sprintf("podSelector does not match any pod in namespace %q", current.Namespace))
+		return
+	}
+
+	builder.WithNoMatchingPodsCleared()
+}
+
+// MatchedPodNames returns the names of the given pods that are selected by the
+// matcher, in lexicographical order.
+func MatchedPodNames(matcher *Matcher, pods []metav1.Object) []string {
+	names := make([]string, 0)
+	for _, pod := range pods {
+		if matcher.Matches(pod) {
+			names = append(names, pod.GetName())
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,273 @@
+package reconciler
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	controllerreconciler "sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	autoscalingv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/autoscaling/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/clientset/versioned/fake"
+	autoscalingv1listers "github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/listers/autoscaling/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/resourceoverride/internal/condition"
+)
+
+func newPod(namespace, name string, labels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+	}
+}
+
+func TestCountMatchedPods(t *testing.T) {
+	web := make([]*corev1.Pod, 0)
+	for i := 6; i >= 0; i-- {
+		web = append(web, newPod("default", fmt.Sprintf("web-%d", i), map[string]string{"app": "web"}))
+	}
+	db := newPod("default", "db-0", map[string]string{"app": "db"})
+	elsewhere := newPod("other", "web-x", map[string]string{"app": "web"})
+
+	tests := []struct {
+		name           string
+		podSelector    *metav1.LabelSelector
+		pods           []*corev1.Pod
+		wantMatched    int32
+		wantSamples    []string
+		wantNoMatching corev1.ConditionStatus
+	}{
+		{
+			name:           "selector matches pods",
+			podSelector:    &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			pods:           append([]*corev1.Pod{db, elsewhere}, web...),
+			wantMatched:    7,
+			wantSamples:    []string{"web-0", "web-1", "web-2", "web-3", "web-4"},
+			wantNoMatching: corev1.ConditionFalse,
+		},
+		{
+			name:           "nil selector matches every pod in the namespace",
+			pods:           []*corev1.Pod{db, elsewhere},
+			wantMatched:    1,
+			wantSamples:    []string{"db-0"},
+			wantNoMatching: corev1.ConditionFalse,
+		},
+		{
+			name:           "selector matches no pods",
+			podSelector:    &metav1.LabelSelector{MatchLabels: map[string]string{"app": "wbe"}},
+			pods:           append([]*corev1.Pod{db, elsewhere}, web...),
+			wantMatched:    0,
+			wantNoMatching: corev1.ConditionTrue,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ro := &autoscalingv1.ResourceOverride{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-ro",
+					Namespace: "default",
+				},
+				Spec: autoscalingv1.ResourceOverrideSpec{
+					PodResourceOverride: autoscalingv1.PodResourceOverrideSpec{
+						MemoryRequestToLimitPercent: 50,
+					},
+					PodSelector: test.podSelector,
+				},
+			}
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "default",
+					Labels: map[string]string{asset.NamespaceOptInLabelKey: "true"},
+				},
+			}
+
+			fakeClient := fake.NewSimpleClientset(ro)
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			indexer.Add(ro)
+
+			r := NewReconciler(&Options{
+				Client:                        fakeClient,
+				Lister:                        autoscalingv1listers.NewResourceOverrideLister(indexer),
+				NamespaceLister:               newNamespaceLister(ns),
+				PodCache:                      newPodCache(test.pods...),
+				ClusterResourceOverrideLister: newClusterResourceOverrideLister(),
+				ClusterResourceOverrideName:   "cluster",
+			})
+			_, err := r.Reconcile(t.Context(), controllerreconciler.Request{
+				NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-ro"},
+			})
+			require.NoError(t, err)
+
+			updated, err := fakeClient.AutoscalingV1().ResourceOverrides("default").Get(t.Context(), "test-ro", metav1.GetOptions{})
+			require.NoError(t, err)
+			require.Equal(t, test.wantMatched, updated.Status.MatchedPods)
+			require.Equal(t, test.wantSamples, updated.Status.MatchedPodSamples)
+			if test.wantMatched > 0 {
+				require.NotNil(t, updated.Status.LastMatchedTime)
+			} else {
+				require.Nil(t, updated.Status.LastMatchedTime)
+			}
+
+			cond := condition.Find(&updated.Status, autoscalingv1.NoMatchingPods)
+			require.NotNil(t, cond)
+			require.Equal(t, test.wantNoMatching, cond.Status)
+		})
+	}
+}
+
+func TestCountMatchedPodsLastMatchedTime(t *testing.T) {
+	before := metav1.NewTime(metav1.Now().Add(-time.Hour))
+	pods := []metav1.Object{
+		newPod("default", "web-0", map[string]string{"app": "web"}),
+		newPod("default", "web-1", map[string]string{"app": "web"}),
+	}
+
+	tests := []struct {
+		name        string
+		matched     int32
+		samples     []string
+		wantUpdated bool
+	}{
+		{
+			name:        "unchanged matched pods keep the time",
+			matched:     2,
+			samples:     []string{"web-0", "web-1"},
+			wantUpdated: false,
+		},
+		{
+			name:        "a new matched pod moves the time",
+			matched:     1,
+			samples:     []string{"web-0"},
+			wantUpdated: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ro := &autoscalingv1.ResourceOverride{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-ro", Namespace: "default"},
+				Status: autoscalingv1.ResourceOverrideStatus{
+					MatchedPods:       test.matched,
+					MatchedPodSamples: test.samples,
+					LastMatchedTime:   before.DeepCopy(),
+				},
+			}
+
+			(&reconciler{}).countMatchedPods(ro, pods)
+
+			require.Equal(t, int32(2), ro.Status.MatchedPods)
+			require.Equal(t, test.wantUpdated, !ro.Status.LastMatchedTime.Equal(&before))
+		})
+	}
+}
+
+func TestWatchPods(t *testing.T) {
+	pod := newPod("default", "web-0", map[string]string{"app": "web"})
+	optedIn := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "default",
+			Labels: map[string]string{asset.NamespaceOptInLabelKey: "true"},
+		},
+	}
+	notOptedIn := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "default",
+		},
+	}
+
+	tests := []struct {
+		name        string
+		ns          *corev1.Namespace
+		unsynced    bool
+		deleted     bool
+		wantWatched bool
+		wantMatched int32
+	}{
+		{
+			name:        "pods are watched in an opted-in namespace",
+			ns:          optedIn,
+			wantWatched: true,
+			wantMatched: 1,
+		},
+		{
+			name:        "matched pods are left as is until the pods are cached",
+			ns:          optedIn,
+			unsynced:    true,
+			wantWatched: true,
+			wantMatched: 3,
+		},
+		{
+			name:        "pods are not watched in a namespace that is not opted in",
+			ns:          notOptedIn,
+			wantWatched: false,
+			wantMatched: 0,
+		},
+		{
+			name:        "pods are no longer watched once the last ResourceOverride is deleted",
+			ns:          optedIn,
+			deleted:     true,
+			wantWatched: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ro := &autoscalingv1.ResourceOverride{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-ro",
+					Namespace: "default",
+				},
+				Spec: autoscalingv1.ResourceOverrideSpec{
+					PodResourceOverride: autoscalingv1.PodResourceOverrideSpec{
+						MemoryRequestToLimitPercent: 50,
+					},
+				},
+				Status: autoscalingv1.ResourceOverrideStatus{
+					MatchedPods: 3,
+				},
+			}
+
+			fakeClient := fake.NewSimpleClientset(ro)
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if !test.deleted {
+				indexer.Add(ro)
+			}
+
+			podCache := newPodCache(pod)
+			podCache.unsynced = test.unsynced
+			podCache.Watch("default")
+
+			r := NewReconciler(&Options{
+				Client:                        fakeClient,
+				Lister:                        autoscalingv1listers.NewResourceOverrideLister(indexer),
+				NamespaceLister:               newNamespaceLister(test.ns),
+				PodCache:                      podCache,
+				ClusterResourceOverrideLister: newClusterResourceOverrideLister(),
+				ClusterResourceOverrideName:   "cluster",
+			})
+			_, err := r.Reconcile(t.Context(), controllerreconciler.Request{
+				NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-ro"},
+			})
+			require.NoError(t, err)
+			require.Equal(t, test.wantWatched, podCache.watched["default"])
+
+			if test.deleted {
+				return
+			}
+
+			updated, err := fakeClient.AutoscalingV1().ResourceOverrides("default").Get(t.Context(), "test-ro", metav1.GetOptions{})
+			require.NoError(t, err)
+			require.Equal(t, test.wantMatched, updated.Status.MatchedPods)
+		})
+	}
+}
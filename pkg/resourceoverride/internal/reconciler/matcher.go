@@ -0,0 +1,38 @@
+package reconciler
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	autoscalingv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/autoscaling/v1"
+)
+
+// Matcher decides whether a ResourceOverride applies to a pod, based on its
+// podSelector.
+type Matcher struct {
+	selector labels.Selector
+}
+
+// NewMatcher returns a Matcher for the given ResourceOverride spec, or an error
+// if the podSelector is invalid.
+func NewMatcher(spec *autoscalingv1.ResourceOverrideSpec) (*Matcher, error) {
+	selector, err := PodSelectorAsSelector(spec.PodSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Matcher{
+		selector: selector,
+	}, nil
+}
+
+// Matches returns true if the pod is selected. Only the metadata of the pod is
+// needed.
+func (m *Matcher) Matches(pod metav1.Object) bool {
+	return m.selector.Matches(labels.Set(pod.GetLabels()))
+}
+
+// Covers returns true if every pod selected by other is also selected by m.
+func (m *Matcher) Covers(other *Matcher) bool {
+	return covers(m.selector, other.selector)
+}
@@ -0,0 +1,59 @@
+package reconciler
+
+import (
+	"fmt"
+	"strings"
+
+	autoscalingv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/autoscaling/v1"
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/resourceoverride/internal/condition"
+)
+
+// checkPolicy sets the PolicyViolation condition if the given ResourceOverride
+// sets a ratio outside the bounds of the resourceOverridePolicy of the
+// ClusterResourceOverride. This reports ResourceOverride(s) that were admitted
+// before the policy was put in place or with a Warn or Audit action.
+func (r *reconciler) checkPolicy(current *autoscalingv1.ResourceOverride) error {
+	builder := condition.NewBuilderWithStatus(&current.Status)
+
+	cro, err := r.getClusterResourceOverride()
+	if err != nil {
+		return err
+	}
+
+	if cro == nil || cro.Spec.ResourceOverridePolicy == nil {
+		builder.WithPolicyViolationCleared()
+		return nil
+	}
+
+	violations := PolicyViolations(&current.Spec.PodResourceOverride, cro.Spec.ResourceOverridePolicy)
+	if len(violations) == 0 {
+		builder.WithPolicyViolationCleared()
+		return nil
+	}
+
+	builder.WithPolicyViolation(autoscalingv1.OutsidePolicyBounds, fmt.Sprintf("%s (action=%s)", strings.Join(violations, "; "), cro.Spec.ResourceOverridePolicy.GetAction()))
+	return nil
+}
+
+// PolicyViolations returns a description of each ratio of the given spec that
+// is outside the bounds set by the policy.
+func PolicyViolations(spec *autoscalingv1.PodResourceOverrideSpec, policy *operatorv1.ResourceOverridePolicy) []string {
+	violations := make([]string, 0)
+	for _, field := range []struct {
+		name   string
+		value  int64
+		bounds *operatorv1.PercentRange
+	}{
+		{name: "limitCPUToMemoryPercent", value: spec.LimitCPUToMemoryPercent, bounds: policy.LimitCPUToMemoryPercent},
+		{name: "cpuRequestToLimitPercent", value: spec.CPURequestToLimitPercent, bounds: policy.CPURequestToLimitPercent},
+		{name: "memoryRequestToLimitPercent", value: spec.MemoryRequestToLimitPercent, bounds: policy.MemoryRequestToLimitPercent},
+		{name: "cpuRequestToRequestPercent", value: spec.CPURequestToRequestPercent, bounds: policy.CPURequestToRequestPercent},
+	} {
+		if !field.bounds.Contains(field.value) {
+			violations = append(violations, fmt.Sprintf("%s=%d is outside %s", field.name, field.value, field.bounds.String()))
+		}
+	}
+
+	return violations
+}
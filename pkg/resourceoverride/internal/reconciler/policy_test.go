@@ -0,0 +1,145 @@
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	controllerreconciler "sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	autoscalingv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/autoscaling/v1"
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/clientset/versioned/fake"
+	autoscalingv1listers "github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/listers/autoscaling/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/resourceoverride/internal/condition"
+)
+
+func TestPolicyViolations(t *testing.T) {
+	policy := &operatorv1.ResourceOverridePolicy{
+		MemoryRequestToLimitPercent: &operatorv1.PercentRange{Min: 25, Max: 100},
+		CPURequestToLimitPercent:    &operatorv1.PercentRange{Max: 50},
+	}
+
+	tests := []struct {
+		name string
+		spec autoscalingv1.PodResourceOverrideSpec
+		want []string
+	}{
+		{
+			name: "within bounds",
+			spec: autoscalingv1.PodResourceOverrideSpec{
+				MemoryRequestToLimitPercent: 50,
+				CPURequestToLimitPercent:    50,
+				LimitCPUToMemoryPercent:     400,
+			},
+			want: []string{},
+		},
+		{
+			name: "unset ratios are allowed",
+			spec: autoscalingv1.PodResourceOverrideSpec{},
+			want: []string{},
+		},
+		{
+			name: "outside bounds",
+			spec: autoscalingv1.PodResourceOverrideSpec{
+				MemoryRequestToLimitPercent: 1,
+				CPURequestToLimitPercent:    75,
+			},
+			want: []string{
+				"cpuRequestToLimitPercent=75 is outside [0, 50]",
+				"memoryRequestToLimitPercent=1 is outside [25, 100]",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.want, PolicyViolations(&test.spec, policy))
+		})
+	}
+}
+
+func TestCheckPolicy(t *testing.T) {
+	ro := &autoscalingv1.ResourceOverride{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-ro",
+			Namespace: "default",
+		},
+		Spec: autoscalingv1.ResourceOverrideSpec{
+			PodResourceOverride: autoscalingv1.PodResourceOverrideSpec{
+				MemoryRequestToLimitPercent: 1,
+			},
+		},
+	}
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "default",
+			Labels: map[string]string{asset.NamespaceOptInLabelKey: "true"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		policy     *operatorv1.ResourceOverridePolicy
+		wantStatus corev1.ConditionStatus
+	}{
+		{
+			name:       "no policy",
+			wantStatus: corev1.ConditionFalse,
+		},
+		{
+			name: "violates policy",
+			policy: &operatorv1.ResourceOverridePolicy{
+				Action:                      operatorv1.PolicyActionWarn,
+				MemoryRequestToLimitPercent: &operatorv1.PercentRange{Min: 25},
+			},
+			wantStatus: corev1.ConditionTrue,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cro := &operatorv1.ClusterResourceOverride{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster",
+				},
+				Spec: operatorv1.ClusterResourceOverrideSpec{
+					ResourceOverridePolicy: test.policy,
+				},
+			}
+
+			fakeClient := fake.NewSimpleClientset(ro)
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			indexer.Add(ro)
+
+			r := NewReconciler(&Options{
+				Client:                        fakeClient,
+				Lister:                        autoscalingv1listers.NewResourceOverrideLister(indexer),
+				NamespaceLister:               newNamespaceLister(ns),
+				PodCache:                      newPodCache(),
+				ClusterResourceOverrideLister: newClusterResourceOverrideLister(cro),
+				ClusterResourceOverrideName:   "cluster",
+			})
+			_, err := r.Reconcile(t.Context(), controllerreconciler.Request{
+				NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-ro"},
+			})
+			require.NoError(t, err)
+
+			updated, err := fakeClient.AutoscalingV1().ResourceOverrides("default").Get(t.Context(), "test-ro", metav1.GetOptions{})
+			require.NoError(t, err)
+
+			cond := condition.Find(&updated.Status, autoscalingv1.PolicyViolation)
+			require.NotNil(t, cond)
+			require.Equal(t, test.wantStatus, cond.Status)
+			if test.wantStatus == corev1.ConditionTrue {
+				require.Equal(t, autoscalingv1.OutsidePolicyBounds, cond.Reason)
+				require.Equal(t, "memoryRequestToLimitPercent=1 is outside [25, unbounded] (action=Warn)", cond.Message)
+			}
+		})
+	}
+}
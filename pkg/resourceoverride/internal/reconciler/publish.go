@@ -0,0 +1,176 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	autoscalingv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/autoscaling/v1"
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+)
+
+const (
+	// OptInLabel and OptInAutoOptIn tell tenants how their namespace was
+	// opted in to resource overrides.
+	OptInLabel     = "Label"
+	OptInAutoOptIn = "AutoOptIn"
+
+	// PolicyConfigMapConflict is the reason of the warning event recorded when
+	// a ConfigMap the operator does not own already has the name of the policy
+	// ConfigMap.
+	PolicyConfigMapConflict = "PolicyConfigMapConflict"
+)
+
+// publishPolicy maintains the policy ConfigMap in the given namespace so that
+// tenants, who cannot read the ClusterResourceOverride, can see the overrides
+// applied to their pods. The ConfigMap is removed once the namespace is no
+// longer opted in. A ConfigMap of the same name that is not labeled as the
+// policy ConfigMap belongs to the tenant and is left alone. No policy is
+// published while the admission webhook is being removed.
+func (r *reconciler) publishPolicy(namespace string) error {
+	ns, err := r.namespaceLister.Get(namespace)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if ns.DeletionTimestamp != nil {
+		return nil
+	}
+
+	existing, err := r.configMapLister.ConfigMaps(namespace).Get(asset.NamespacePolicyConfigMapName)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+		existing = nil
+	}
+
+	cro, err := r.getClusterResourceOverride()
+	if err != nil {
+		return err
+	}
+
+	if ns.Labels[asset.NamespaceOptInLabelKey] != "true" || removed(cro) {
+		if existing == nil || existing.Labels[asset.NamespacePolicyLabelKey] != "true" {
+			return nil
+		}
+
+		deleteErr := r.kubeClient.CoreV1().ConfigMaps(namespace).Delete(context.TODO(), existing.Name, metav1.DeleteOptions{})
+		if deleteErr != nil && !k8serrors.IsNotFound(deleteErr) {
+			return fmt.Errorf("failed to delete policy configmap in namespace %q - %s", namespace, deleteErr.Error())
+		}
+
+		klog.V(2).Infof("[reconciler] namespace=%s not opted in, deleted policy configmap", namespace)
+		return nil
+	}
+
+	ros, err := r.lister.ResourceOverrides(namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	desired := PolicyConfigMap(ns, cro, ros)
+	if existing == nil {
+		if _, err := r.kubeClient.CoreV1().ConfigMaps(namespace).Create(context.TODO(), desired, metav1.CreateOptions{}); err != nil {
+			if k8serrors.IsAlreadyExists(err) {
+				// only the labeled ConfigMap(s) are cached, so this one is not ours.
+				r.warnPolicyConfigMapConflict(ns)
+				return nil
+			}
+			return fmt.Errorf("failed to create policy configmap in namespace %q - %s", namespace, err.Error())
+		}
+
+		klog.V(2).Infof("[reconciler] namespace=%s created policy configmap", namespace)
+		return nil
+	}
+
+	if existing.Labels[asset.NamespacePolicyLabelKey] != "true" {
+		r.warnPolicyConfigMapConflict(ns)
+		return nil
+	}
+
+	if reflect.DeepEqual(existing.Data, desired.Data) {
+		return nil
+	}
+
+	updated := existing.DeepCopy()
+	updated.Data = desired.Data
+	if _, err := r.kubeClient.CoreV1().ConfigMaps(namespace).Update(context.TODO(), updated, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update policy configmap in namespace %q - %s", namespace, err.Error())
+	}
+
+	klog.V(2).Infof("[reconciler] namespace=%s updated policy configmap", namespace)
+	return nil
+}
+
+// warnPolicyConfigMapConflict records that the policy ConfigMap cannot be
+// published in the given namespace.
+func (r *reconciler) warnPolicyConfigMapConflict(ns *corev1.Namespace) {
+	message := fmt.Sprintf("configmap %q in namespace %q is not labeled %s=true, the policy is not published", asset.NamespacePolicyConfigMapName, ns.Name, asset.NamespacePolicyLabelKey)
+	r.recorder.Event(ns, corev1.EventTypeWarning, PolicyConfigMapConflict, message)
+
+	klog.V(2).Infof("[reconciler] namespace=%s %s", ns.Name, message)
+}
+
+// PolicyConfigMap returns the policy ConfigMap of the given opted-in namespace.
+// It holds the ratios of the ClusterResourceOverride, how the namespace was
+// opted in and the name of every ResourceOverride in the namespace.
+// cro may be nil if there is no ClusterResourceOverride.
+func PolicyConfigMap(ns *corev1.Namespace, cro *operatorv1.ClusterResourceOverride, ros []*autoscalingv1.ResourceOverride) *corev1.ConfigMap {
+	data := map[string]string{
+		"optIn": OptInLabel,
+	}
+	if ns.Annotations[asset.NamespaceAutoOptInAnnotationKey] == "true" {
+		data["optIn"] = OptInAutoOptIn
+	}
+
+	if cro != nil {
+		spec := &cro.Spec.PodResourceOverride.Spec
+		data["clusterResourceOverrideHash"] = spec.Hash()
+		data["forceSelinuxRelabel"] = strconv.FormatBool(spec.ForceSelinuxRelabel)
+		data["limitCPUToMemoryPercent"] = strconv.FormatInt(spec.LimitCPUToMemoryPercent, 10)
+		data["cpuRequestToLimitPercent"] = strconv.FormatInt(spec.CPURequestToLimitPercent, 10)
+		data["memoryRequestToLimitPercent"] = strconv.FormatInt(spec.MemoryRequestToLimitPercent, 10)
+		data["cpuRequestToRequestPercent"] = strconv.FormatInt(spec.CPURequestToRequestPercent, 10)
+
+		if policy := cro.Spec.ResourceOverridePolicy; policy != nil {
+			data["resourceOverridePolicy"] = policy.String()
+		}
+	}
+
+	names := make([]string, 0, len(ros))
+	for _, ro := range ros {
+		if ro.DeletionTimestamp != nil {
+			continue
+		}
+		names = append(names, fmt.Sprintf("resourceoverride/%s", ro.Name))
+	}
+	if len(names) > 0 {
+		sort.Strings(names)
+		data["resourceOverrides"] = strings.Join(names, "\n")
+	}
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      asset.NamespacePolicyConfigMapName,
+			Namespace: ns.Name,
+			Labels: map[string]string{
+				asset.NamespacePolicyLabelKey: "true",
+			},
+		},
+		Data: data,
+	}
+}
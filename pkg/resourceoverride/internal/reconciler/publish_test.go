@@ -0,0 +1,237 @@
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	controllerreconciler "sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	autoscalingv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/autoscaling/v1"
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/clientset/versioned/fake"
+	autoscalingv1listers "github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/listers/autoscaling/v1"
+)
+
+func newConfigMapLister(configMaps ...*corev1.ConfigMap) corev1listers.ConfigMapLister {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, cm := range configMaps {
+		indexer.Add(cm)
+	}
+	return corev1listers.NewConfigMapLister(indexer)
+}
+
+func TestPolicyConfigMap(t *testing.T) {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "tenant-a",
+			Labels:      map[string]string{asset.NamespaceOptInLabelKey: "true"},
+			Annotations: map[string]string{asset.NamespaceAutoOptInAnnotationKey: "true"},
+		},
+	}
+	cro := &operatorv1.ClusterResourceOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: operatorv1.ClusterResourceOverrideSpec{
+			PodResourceOverride: operatorv1.PodResourceOverride{
+				Spec: operatorv1.PodResourceOverrideSpec{
+					MemoryRequestToLimitPercent: 50,
+				},
+			},
+		},
+	}
+	deleting := metav1.Now()
+	ros := []*autoscalingv1.ResourceOverride{
+		{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "tenant-a"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "tenant-a"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "c", Namespace: "tenant-a", DeletionTimestamp: &deleting}},
+	}
+
+	cm := PolicyConfigMap(ns, cro, ros)
+	require.Equal(t, asset.NamespacePolicyConfigMapName, cm.Name)
+	require.Equal(t, "tenant-a", cm.Namespace)
+	require.Equal(t, "true", cm.Labels[asset.NamespacePolicyLabelKey])
+	require.Equal(t, map[string]string{
+		"optIn":                       OptInAutoOptIn,
+		"clusterResourceOverrideHash": cro.Spec.PodResourceOverride.Spec.Hash(),
+		"forceSelinuxRelabel":         "false",
+		"limitCPUToMemoryPercent":     "0",
+		"cpuRequestToLimitPercent":    "0",
+		"memoryRequestToLimitPercent": "50",
+		"cpuRequestToRequestPercent":  "0",
+		"resourceOverrides":           "resourceoverride/a\nresourceoverride/b",
+	}, cm.Data)
+
+	cm = PolicyConfigMap(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-b"}}, nil, nil)
+	require.Equal(t, map[string]string{"optIn": OptInLabel}, cm.Data)
+}
+
+func TestPublishPolicy(t *testing.T) {
+	cro := &operatorv1.ClusterResourceOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: operatorv1.ClusterResourceOverrideSpec{
+			PodResourceOverride: operatorv1.PodResourceOverride{
+				Spec: operatorv1.PodResourceOverrideSpec{
+					MemoryRequestToLimitPercent: 50,
+				},
+			},
+		},
+	}
+	optedIn := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "tenant-a",
+			Labels: map[string]string{asset.NamespaceOptInLabelKey: "true"},
+		},
+	}
+	notOptedIn := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant-a"},
+	}
+	stale := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      asset.NamespacePolicyConfigMapName,
+			Namespace: "tenant-a",
+			Labels:    map[string]string{asset.NamespacePolicyLabelKey: "true"},
+		},
+		Data: map[string]string{"memoryRequestToLimitPercent": "100"},
+	}
+	tenant := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      asset.NamespacePolicyConfigMapName,
+			Namespace: "tenant-a",
+		},
+		Data: map[string]string{"memoryRequestToLimitPercent": "100"},
+	}
+
+	tests := []struct {
+		name      string
+		ns        *corev1.Namespace
+		existing  *corev1.ConfigMap
+		removed   bool
+		wantGone  bool
+		wantCalls int
+		wantEvent bool
+	}{
+		{
+			name:      "created in opted-in namespace",
+			ns:        optedIn,
+			wantCalls: 1,
+		},
+		{
+			name:      "stale configmap is updated",
+			ns:        optedIn,
+			existing:  stale,
+			wantCalls: 1,
+		},
+		{
+			name:      "up to date configmap is left alone",
+			ns:        optedIn,
+			existing:  PolicyConfigMap(optedIn, cro, nil),
+			wantCalls: 0,
+		},
+		{
+			name:      "configmap of the tenant is left alone",
+			ns:        optedIn,
+			existing:  tenant,
+			wantCalls: 1,
+			wantEvent: true,
+		},
+		{
+			name:      "configmap of the tenant is not deleted once namespace is opted out",
+			ns:        notOptedIn,
+			existing:  tenant,
+			wantCalls: 0,
+		},
+		{
+			name:      "deleted once namespace is opted out",
+			ns:        notOptedIn,
+			existing:  stale,
+			wantGone:  true,
+			wantCalls: 1,
+		},
+		{
+			name:      "deleted once the admission webhook is removed",
+			ns:        optedIn,
+			existing:  stale,
+			removed:   true,
+			wantGone:  true,
+			wantCalls: 1,
+		},
+		{
+			name:      "nothing to do in namespace not opted in",
+			ns:        notOptedIn,
+			wantGone:  true,
+			wantCalls: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			objects := []runtime.Object{test.ns}
+			configMaps := []*corev1.ConfigMap{}
+			if test.existing != nil {
+				objects = append(objects, test.existing)
+				// only the labeled ConfigMap(s) are cached.
+				if test.existing.Labels[asset.NamespacePolicyLabelKey] == "true" {
+					configMaps = append(configMaps, test.existing)
+				}
+			}
+			kubeClient := kubefake.NewSimpleClientset(objects...)
+			kubeClient.ClearActions()
+
+			current := cro
+			if test.removed {
+				current = cro.DeepCopy()
+				current.Spec.ManagementState = operatorv1.Removed
+			}
+
+			recorder := record.NewFakeRecorder(10)
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			r := NewReconciler(&Options{
+				Client:                        fake.NewSimpleClientset(),
+				KubeClient:                    kubeClient,
+				Recorder:                      recorder,
+				Lister:                        autoscalingv1listers.NewResourceOverrideLister(indexer),
+				NamespaceLister:               newNamespaceLister(test.ns),
+				PodCache:                      newPodCache(),
+				ClusterResourceOverrideLister: newClusterResourceOverrideLister(current),
+				ClusterResourceOverrideName:   "cluster",
+				ConfigMapLister:               newConfigMapLister(configMaps...),
+			})
+			_, err := r.Reconcile(t.Context(), controllerreconciler.Request{
+				NamespacedName: types.NamespacedName{Namespace: "tenant-a"},
+			})
+			require.NoError(t, err)
+			require.Len(t, kubeClient.Actions(), test.wantCalls)
+			if test.wantEvent {
+				require.Len(t, recorder.Events, 1)
+				require.Contains(t, <-recorder.Events, PolicyConfigMapConflict)
+			} else {
+				require.Len(t, recorder.Events, 0)
+			}
+
+			cm, err := kubeClient.CoreV1().ConfigMaps("tenant-a").Get(t.Context(), asset.NamespacePolicyConfigMapName, metav1.GetOptions{})
+			if test.wantGone {
+				require.True(t, k8serrors.IsNotFound(err))
+				return
+			}
+
+			require.NoError(t, err)
+			if test.existing == tenant {
+				require.Equal(t, tenant.Data, cm.Data)
+				require.Empty(t, cm.Labels)
+				return
+			}
+			require.Equal(t, "50", cm.Data["memoryRequestToLimitPercent"])
+			require.Equal(t, OptInLabel, cm.Data["optIn"])
+		})
+	}
+}
@@ -5,14 +5,19 @@ import (
 	"fmt"
 
 	autoscalingv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/autoscaling/v1"
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/clientset/versioned"
 	autoscalingv1listers "github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/listers/autoscaling/v1"
+	operatorv1listers "github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/listers/operator/v1"
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/resourceoverride/internal/condition"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
 	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	controllerreconciler "sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -25,20 +30,69 @@ var (
 	}
 )
 
+// PodCache caches the metadata of the pods in the namespace(s) it is told to
+// watch. Only the namespace(s) where a ResourceOverride is in effect are
+// watched, so the operator does not hold every pod in the cluster.
+type PodCache interface {
+	// Watch starts caching the pods in the given namespace, if not already.
+	Watch(namespace string)
+
+	// Forget stops caching the pods in the given namespace.
+	Forget(namespace string)
+
+	// Pods returns the cached pods in the given namespace, synced is false
+	// if the cache of the namespace has not synced yet.
+	Pods(namespace string) (pods []metav1.Object, synced bool, err error)
+}
+
+// Options holds the dependencies of the ResourceOverride reconciler.
+type Options struct {
+	Client          versioned.Interface
+	KubeClient      kubernetes.Interface
+	Recorder        record.EventRecorder
+	Lister          autoscalingv1listers.ResourceOverrideLister
+	NamespaceLister corev1listers.NamespaceLister
+
+	// PodCache holds the metadata of the pods in the namespace(s) where a
+	// ResourceOverride is in effect.
+	PodCache PodCache
+
+	// ClusterResourceOverrideLister and ClusterResourceOverrideName are used to
+	// look up the cluster-wide configuration the ResourceOverride falls back to.
+	ClusterResourceOverrideLister operatorv1listers.ClusterResourceOverrideLister
+	ClusterResourceOverrideName   string
+
+	// ConfigMapLister lists the policy ConfigMap(s) published in opted-in
+	// namespaces.
+	ConfigMapLister corev1listers.ConfigMapLister
+}
+
 type reconciler struct {
 	client          versioned.Interface
+	kubeClient      kubernetes.Interface
+	recorder        record.EventRecorder
 	lister          autoscalingv1listers.ResourceOverrideLister
 	namespaceLister corev1listers.NamespaceLister
+	podCache        PodCache
+	croLister       operatorv1listers.ClusterResourceOverrideLister
+	croName         string
+	configMapLister corev1listers.ConfigMapLister
 	updater         *StatusUpdater
 }
 
-func NewReconciler(client versioned.Interface, lister autoscalingv1listers.ResourceOverrideLister, namespaceLister corev1listers.NamespaceLister) *reconciler {
+func NewReconciler(options *Options) *reconciler {
 	return &reconciler{
-		client:          client,
-		lister:          lister,
-		namespaceLister: namespaceLister,
+		client:          options.Client,
+		kubeClient:      options.KubeClient,
+		recorder:        options.Recorder,
+		lister:          options.Lister,
+		namespaceLister: options.NamespaceLister,
+		podCache:        options.PodCache,
+		croLister:       options.ClusterResourceOverrideLister,
+		croName:         options.ClusterResourceOverrideName,
+		configMapLister: options.ConfigMapLister,
 		updater: &StatusUpdater{
-			client: client,
+			client: options.Client,
 		},
 	}
 }
@@ -46,10 +100,28 @@ func NewReconciler(client versioned.Interface, lister autoscalingv1listers.Resou
 func (r *reconciler) Reconcile(ctx context.Context, request controllerreconciler.Request) (result controllerreconciler.Result, err error) {
 	klog.V(4).Infof("key=%s new request for reconcile", request.Name)
 
+	// a request without a name is for the namespace itself.
+	if request.Name == "" {
+		if err = r.translateLegacyAnnotation(request.Namespace); err != nil {
+			klog.Errorf("[reconciler] namespace=%s failed to translate legacy annotation - %s", request.Namespace, err.Error())
+			return
+		}
+
+		err = r.publishPolicy(request.Namespace)
+		if err != nil {
+			klog.Errorf("[reconciler] namespace=%s failed to publish policy configmap - %s", request.Namespace, err.Error())
+		}
+		return
+	}
+
 	original, getErr := r.lister.ResourceOverrides(request.Namespace).Get(request.Name)
 	if getErr != nil {
 		if k8serrors.IsNotFound(getErr) {
 			klog.V(4).Infof("[reconciler] key=%s object has been deleted - %s", request.Name, getErr.Error())
+			err = r.autoOptOut(request.Namespace)
+			if err == nil {
+				err = r.forgetPods(request.Namespace)
+			}
 			return
 		}
 
@@ -70,6 +142,39 @@ func (r *reconciler) Reconcile(ctx context.Context, request controllerreconciler
 		return
 	}
 
+	pods, synced, podsErr := r.watchPods(copy)
+	if podsErr != nil {
+		klog.Errorf("[reconciler] key=%s failed to list pods - %s", request.Name, podsErr.Error())
+		err = podsErr
+		return
+	}
+
+	// until the pods in the namespace are cached, the conflicts and matched
+	// pods are left as is, the ResourceOverride is enqueued again once synced.
+	if synced {
+		if conflictErr := r.checkConflicts(copy, pods); conflictErr != nil {
+			klog.Errorf("[reconciler] key=%s failed to check for conflicting ResourceOverride(s) - %s", request.Name, conflictErr.Error())
+			err = conflictErr
+			return
+		}
+	}
+
+	if policyErr := r.checkPolicy(copy); policyErr != nil {
+		klog.Errorf("[reconciler] key=%s failed to check resourceOverridePolicy - %s", request.Name, policyErr.Error())
+		err = policyErr
+		return
+	}
+
+	if synced {
+		r.countMatchedPods(copy, pods)
+	}
+
+	if effectiveErr := r.computeEffective(copy); effectiveErr != nil {
+		klog.Errorf("[reconciler] key=%s failed to compute effective policy - %s", request.Name, effectiveErr.Error())
+		err = effectiveErr
+		return
+	}
+
 	err = r.updater.Update(original, copy)
 	if err != nil {
 		klog.Errorf("[reconciler] key=%s failed to update status - %s", request.Name, err.Error())
@@ -105,6 +210,16 @@ func (r *reconciler) checkNamespaceOptIn(current *autoscalingv1.ResourceOverride
 
 	builder := condition.NewBuilderWithStatus(&current.Status)
 	if ns.Labels[asset.NamespaceOptInLabelKey] != "true" {
+		optedIn, err := r.autoOptIn(current, ns)
+		if err != nil {
+			return err
+		}
+
+		if optedIn {
+			builder.WithIgnoredCleared()
+			return nil
+		}
+
 		builder.WithIgnored(autoscalingv1.NamespaceNotOptedIn, fmt.Sprintf("namespace %q does not have the %s=true label", current.Namespace, asset.NamespaceOptInLabelKey))
 		return nil
 	}
@@ -112,3 +227,53 @@ func (r *reconciler) checkNamespaceOptIn(current *autoscalingv1.ResourceOverride
 	builder.WithIgnoredCleared()
 	return nil
 }
+
+// computeEffective publishes the merged ResourceOverride and ClusterResourceOverride
+// policy in the status. It is cleared if the ResourceOverride is not in effect.
+func (r *reconciler) computeEffective(current *autoscalingv1.ResourceOverride) error {
+	validation := condition.Find(&current.Status, autoscalingv1.ValidationFailure)
+	ignored := condition.Find(&current.Status, autoscalingv1.Ignored)
+	if (validation != nil && validation.Status == corev1.ConditionTrue) || (ignored != nil && ignored.Status == corev1.ConditionTrue) {
+		current.Status.Effective = nil
+		return nil
+	}
+
+	var cluster *operatorv1.PodResourceOverrideSpec
+	cro, err := r.getClusterResourceOverride()
+	if err != nil {
+		return err
+	}
+	if cro != nil {
+		cluster = &cro.Spec.PodResourceOverride.Spec
+	}
+
+	effective := Effective(&current.Spec.PodResourceOverride, cluster)
+	if cluster != nil {
+		effective.ClusterResourceOverrideHash = cluster.Hash()
+	}
+
+	current.Status.Effective = effective
+	return nil
+}
+
+// getClusterResourceOverride returns the ClusterResourceOverride the
+// ResourceOverride falls back to, nil if it does not exist.
+func (r *reconciler) getClusterResourceOverride() (*operatorv1.ClusterResourceOverride, error) {
+	cro, err := r.croLister.Get(r.croName)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return cro, nil
+}
+
+// removed returns true if the admission webhook of the given
+// ClusterResourceOverride is being torn down, in which case the operator stops
+// labeling namespaces and publishing the policy so that the teardown can clean
+// them up.
+func removed(cro *operatorv1.ClusterResourceOverride) bool {
+	return cro != nil && (cro.DeletionTimestamp != nil || cro.Spec.GetManagementState() == operatorv1.Removed)
+}
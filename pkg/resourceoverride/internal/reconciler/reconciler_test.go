@@ -13,9 +13,11 @@ import (
 	controllerreconciler "sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	autoscalingv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/autoscaling/v1"
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/clientset/versioned/fake"
 	autoscalingv1listers "github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/listers/autoscaling/v1"
+	operatorv1listers "github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/listers/operator/v1"
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/resourceoverride/internal/condition"
 )
 
@@ -27,6 +29,51 @@ func newNamespaceLister(namespaces ...*corev1.Namespace) corev1listers.Namespace
 	return corev1listers.NewNamespaceLister(indexer)
 }
 
+// fakePodCache is a PodCache over a fixed set of pods, a namespace is synced
+// as soon as it is watched unless the cache is told otherwise.
+type fakePodCache struct {
+	pods     []*corev1.Pod
+	watched  map[string]bool
+	unsynced bool
+}
+
+func newPodCache(pods ...*corev1.Pod) *fakePodCache {
+	return &fakePodCache{
+		pods:    pods,
+		watched: map[string]bool{},
+	}
+}
+
+func (c *fakePodCache) Watch(namespace string) {
+	c.watched[namespace] = true
+}
+
+func (c *fakePodCache) Forget(namespace string) {
+	delete(c.watched, namespace)
+}
+
+func (c *fakePodCache) Pods(namespace string) ([]metav1.Object, bool, error) {
+	if !c.watched[namespace] || c.unsynced {
+		return nil, false, nil
+	}
+
+	pods := make([]metav1.Object, 0)
+	for _, pod := range c.pods {
+		if pod.Namespace == namespace {
+			pods = append(pods, pod)
+		}
+	}
+	return pods, true, nil
+}
+
+func newClusterResourceOverrideLister(cros ...*operatorv1.ClusterResourceOverride) operatorv1listers.ClusterResourceOverrideLister {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, cro := range cros {
+		indexer.Add(cro)
+	}
+	return operatorv1listers.NewClusterResourceOverrideLister(indexer)
+}
+
 func TestValidate(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -149,7 +196,14 @@ func TestReconcile(t *testing.T) {
 		lister := autoscalingv1listers.NewResourceOverrideLister(indexer)
 		nsLister := newNamespaceLister(ns)
 
-		r := NewReconciler(fakeClient, lister, nsLister)
+		r := NewReconciler(&Options{
+			Client:                        fakeClient,
+			Lister:                        lister,
+			NamespaceLister:               nsLister,
+			PodCache:                      newPodCache(),
+			ClusterResourceOverrideLister: newClusterResourceOverrideLister(),
+			ClusterResourceOverrideName:   "cluster",
+		})
 		result, err := r.Reconcile(t.Context(), controllerreconciler.Request{
 			NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-ro"},
 		})
@@ -194,7 +248,14 @@ func TestReconcile(t *testing.T) {
 		lister := autoscalingv1listers.NewResourceOverrideLister(indexer)
 		nsLister := newNamespaceLister(ns)
 
-		r := NewReconciler(fakeClient, lister, nsLister)
+		r := NewReconciler(&Options{
+			Client:                        fakeClient,
+			Lister:                        lister,
+			NamespaceLister:               nsLister,
+			PodCache:                      newPodCache(),
+			ClusterResourceOverrideLister: newClusterResourceOverrideLister(),
+			ClusterResourceOverrideName:   "cluster",
+		})
 		result, err := r.Reconcile(t.Context(), controllerreconciler.Request{
 			NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-ro"},
 		})
@@ -217,7 +278,14 @@ func TestReconcile(t *testing.T) {
 		lister := autoscalingv1listers.NewResourceOverrideLister(indexer)
 		nsLister := newNamespaceLister()
 
-		r := NewReconciler(fakeClient, lister, nsLister)
+		r := NewReconciler(&Options{
+			Client:                        fakeClient,
+			Lister:                        lister,
+			NamespaceLister:               nsLister,
+			PodCache:                      newPodCache(),
+			ClusterResourceOverrideLister: newClusterResourceOverrideLister(),
+			ClusterResourceOverrideName:   "cluster",
+		})
 		_, err := r.Reconcile(t.Context(), controllerreconciler.Request{
 			NamespacedName: types.NamespacedName{Namespace: "default", Name: "nonexistent"},
 		})
@@ -251,7 +319,14 @@ func TestReconcile(t *testing.T) {
 		lister := autoscalingv1listers.NewResourceOverrideLister(indexer)
 		nsLister := newNamespaceLister(ns)
 
-		r := NewReconciler(fakeClient, lister, nsLister)
+		r := NewReconciler(&Options{
+			Client:                        fakeClient,
+			Lister:                        lister,
+			NamespaceLister:               nsLister,
+			PodCache:                      newPodCache(),
+			ClusterResourceOverrideLister: newClusterResourceOverrideLister(),
+			ClusterResourceOverrideName:   "cluster",
+		})
 		result, err := r.Reconcile(t.Context(), controllerreconciler.Request{
 			NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-ro-invalid"},
 		})
@@ -267,4 +342,166 @@ func TestReconcile(t *testing.T) {
 		require.Equal(t, corev1.ConditionTrue, cond.Status)
 		require.Equal(t, autoscalingv1.InvalidParameters, cond.Reason)
 	})
+	t.Run("overlapping ROs report conflict on both sides", func(t *testing.T) {
+		roA := &autoscalingv1.ResourceOverride{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ro-a",
+				Namespace: "default",
+			},
+			Spec: autoscalingv1.ResourceOverrideSpec{
+				PodResourceOverride: autoscalingv1.PodResourceOverrideSpec{
+					MemoryRequestToLimitPercent: 50,
+				},
+				PodSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "web"},
+				},
+			},
+		}
+		roB := &autoscalingv1.ResourceOverride{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ro-b",
+				Namespace: "default",
+			},
+			Spec: autoscalingv1.ResourceOverrideSpec{
+				PodResourceOverride: autoscalingv1.PodResourceOverrideSpec{
+					MemoryRequestToLimitPercent: 75,
+				},
+				PodSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"tier": "frontend"},
+				},
+			},
+		}
+		roC := &autoscalingv1.ResourceOverride{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ro-c",
+				Namespace: "default",
+			},
+			Spec: autoscalingv1.ResourceOverrideSpec{
+				PodResourceOverride: autoscalingv1.PodResourceOverrideSpec{
+					MemoryRequestToLimitPercent: 90,
+				},
+				PodSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "batch"},
+				},
+			},
+		}
+
+		ns := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "default",
+				Labels: map[string]string{asset.NamespaceOptInLabelKey: "true"},
+			},
+		}
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "web-0",
+				Namespace: "default",
+				Labels:    map[string]string{"app": "web", "tier": "frontend"},
+			},
+		}
+
+		fakeClient := fake.NewSimpleClientset(roA, roB, roC)
+		indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		indexer.Add(roA)
+		indexer.Add(roB)
+		indexer.Add(roC)
+		lister := autoscalingv1listers.NewResourceOverrideLister(indexer)
+
+		r := NewReconciler(&Options{
+			Client:                        fakeClient,
+			Lister:                        lister,
+			NamespaceLister:               newNamespaceLister(ns),
+			PodCache:                      newPodCache(pod),
+			ClusterResourceOverrideLister: newClusterResourceOverrideLister(),
+			ClusterResourceOverrideName:   "cluster",
+		})
+		for _, name := range []string{"ro-a", "ro-b", "ro-c"} {
+			_, err := r.Reconcile(t.Context(), controllerreconciler.Request{
+				NamespacedName: types.NamespacedName{Namespace: "default", Name: name},
+			})
+			require.NoError(t, err)
+		}
+
+		updatedA, err := fakeClient.AutoscalingV1().ResourceOverrides("default").Get(t.Context(), "ro-a", metav1.GetOptions{})
+		require.NoError(t, err)
+		cond := condition.Find(&updatedA.Status, autoscalingv1.Conflict)
+		require.NotNil(t, cond)
+		require.Equal(t, corev1.ConditionTrue, cond.Status)
+		require.Equal(t, autoscalingv1.OverlappingPodSelector, cond.Reason)
+		require.Contains(t, cond.Message, "ro-b")
+		require.Contains(t, cond.Message, "applies only one of them")
+
+		updatedB, err := fakeClient.AutoscalingV1().ResourceOverrides("default").Get(t.Context(), "ro-b", metav1.GetOptions{})
+		require.NoError(t, err)
+		cond = condition.Find(&updatedB.Status, autoscalingv1.Conflict)
+		require.NotNil(t, cond)
+		require.Equal(t, corev1.ConditionTrue, cond.Status)
+		require.Contains(t, cond.Message, "ro-a")
+
+		updatedC, err := fakeClient.AutoscalingV1().ResourceOverrides("default").Get(t.Context(), "ro-c", metav1.GetOptions{})
+		require.NoError(t, err)
+		cond = condition.Find(&updatedC.Status, autoscalingv1.Conflict)
+		require.NotNil(t, cond)
+		require.Equal(t, corev1.ConditionFalse, cond.Status)
+	})
+
+	t.Run("effective policy merges ClusterResourceOverride", func(t *testing.T) {
+		ro := &autoscalingv1.ResourceOverride{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-ro",
+				Namespace: "default",
+			},
+			Spec: autoscalingv1.ResourceOverrideSpec{
+				PodResourceOverride: autoscalingv1.PodResourceOverrideSpec{
+					MemoryRequestToLimitPercent: 50,
+				},
+			},
+		}
+		cro := &operatorv1.ClusterResourceOverride{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster",
+			},
+			Spec: operatorv1.ClusterResourceOverrideSpec{
+				PodResourceOverride: operatorv1.PodResourceOverride{
+					Spec: operatorv1.PodResourceOverrideSpec{
+						MemoryRequestToLimitPercent: 25,
+						CPURequestToLimitPercent:    10,
+					},
+				},
+			},
+		}
+
+		ns := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "default",
+				Labels: map[string]string{asset.NamespaceOptInLabelKey: "true"},
+			},
+		}
+
+		fakeClient := fake.NewSimpleClientset(ro)
+		indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		indexer.Add(ro)
+		lister := autoscalingv1listers.NewResourceOverrideLister(indexer)
+
+		r := NewReconciler(&Options{
+			Client:                        fakeClient,
+			Lister:                        lister,
+			NamespaceLister:               newNamespaceLister(ns),
+			PodCache:                      newPodCache(),
+			ClusterResourceOverrideLister: newClusterResourceOverrideLister(cro),
+			ClusterResourceOverrideName:   "cluster",
+		})
+		_, err := r.Reconcile(t.Context(), controllerreconciler.Request{
+			NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-ro"},
+		})
+		require.NoError(t, err)
+
+		updated, err := fakeClient.AutoscalingV1().ResourceOverrides("default").Get(t.Context(), "test-ro", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.NotNil(t, updated.Status.Effective)
+		require.Equal(t, autoscalingv1.EffectiveInt64Value{Value: 50, Source: autoscalingv1.SourceResourceOverride}, updated.Status.Effective.MemoryRequestToLimitPercent)
+		require.Equal(t, autoscalingv1.EffectiveInt64Value{Value: 10, Source: autoscalingv1.SourceClusterResourceOverride}, updated.Status.Effective.CPURequestToLimitPercent)
+		require.Equal(t, autoscalingv1.SourceUnset, updated.Status.Effective.LimitCPUToMemoryPercent.Source)
+		require.Equal(t, cro.Spec.PodResourceOverride.Spec.Hash(), updated.Status.Effective.ClusterResourceOverrideHash)
+	})
 }
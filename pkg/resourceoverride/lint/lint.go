@@ -0,0 +1,253 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	autoscalingv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/autoscaling/v1"
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/clusterresourceoverride/render"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/resourceoverride/internal/condition"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/resourceoverride/internal/reconciler"
+)
+
+// Severity is the severity of a Finding, named after the SARIF result levels.
+type Severity string
+
+const (
+	// SeverityError is a spec the operator rejects or that is never applied.
+	SeverityError Severity = "error"
+
+	// SeverityWarning is a spec that is applied, maybe not the way intended.
+	SeverityWarning Severity = "warning"
+)
+
+// Rule is a check run by Lint.
+type Rule struct {
+	ID          string
+	Severity    Severity
+	Description string
+}
+
+var (
+	InvalidManifest = Rule{
+		ID:          "InvalidManifest",
+		Severity:    SeverityError,
+		Description: "The manifest cannot be parsed.",
+	}
+	InvalidClusterResourceOverride = Rule{
+		ID:          "InvalidClusterResourceOverride",
+		Severity:    SeverityError,
+		Description: "The ClusterResourceOverride has invalid parameters, the operator reports it with the InvalidParameters reason.",
+	}
+	IgnoredClusterResourceOverride = Rule{
+		ID:          "IgnoredClusterResourceOverride",
+		Severity:    SeverityWarning,
+		Description: "The operator only reconciles the ClusterResourceOverride named cluster.",
+	}
+	InvalidResourceOverride = Rule{
+		ID:          "InvalidResourceOverride",
+		Severity:    SeverityError,
+		Description: "The ResourceOverride has invalid parameters, the operator reports it with the InvalidParameters reason.",
+	}
+	DuplicateObject = Rule{
+		ID:          "DuplicateObject",
+		Severity:    SeverityError,
+		Description: "The object is defined more than once, one definition overwrites the other.",
+	}
+	OverlappingResourceOverride = Rule{
+		ID:          "OverlappingResourceOverride",
+		Severity:    SeverityWarning,
+		Description: "The ResourceOverride selects pods that another ResourceOverride also selects, only one of them is applied to a pod.",
+	}
+	ExemptNamespace = Rule{
+		ID:          "ExemptNamespace",
+		Severity:    SeverityError,
+		Description: "ResourceOverride objects cannot be created in system namespaces (openshift, openshift-*, kube, kube-*, kubernetes, kubernetes-*).",
+	}
+	NamespaceNotOptedIn = Rule{
+		ID:          "NamespaceNotOptedIn",
+		Severity:    SeverityWarning,
+		Description: fmt.Sprintf("The ResourceOverride is ignored, its namespace does not have the %s=true label.", asset.NamespaceOptInLabelKey),
+	}
+
+	// Rules lists every Rule, in the order they are run.
+	Rules = []Rule{
+		InvalidManifest,
+		InvalidClusterResourceOverride,
+		IgnoredClusterResourceOverride,
+		InvalidResourceOverride,
+		DuplicateObject,
+		OverlappingResourceOverride,
+		ExemptNamespace,
+		NamespaceNotOptedIn,
+	}
+)
+
+// Finding is a failed check.
+type Finding struct {
+	Rule      string   `json:"rule"`
+	Severity  Severity `json:"severity"`
+	Message   string   `json:"message"`
+	Kind      string   `json:"kind,omitempty"`
+	Namespace string   `json:"namespace,omitempty"`
+	Name      string   `json:"name,omitempty"`
+	Location  Location `json:"location"`
+}
+
+// Lint runs the checks the operator runs on the ClusterResourceOverride and
+// ResourceOverride object(s) of the input, and the checks that span objects:
+// duplicate and overlapping ResourceOverride(s), ResourceOverride(s) in exempt
+// namespaces and, for the namespaces given, in namespaces that are not opted in.
+// The operator reconciles the ClusterResourceOverride of the given name. The
+// findings are sorted by location.
+func Lint(in *Input, clusterResourceOverrideName string) []Finding {
+	l := &linter{
+		in:       in,
+		croName:  clusterResourceOverrideName,
+		findings: append([]Finding{}, in.findings...),
+	}
+
+	cro := l.clusterResourceOverrides()
+	ros := l.resourceOverrides()
+	l.overlaps(ros)
+	l.namespaces(ros, cro)
+
+	sort.SliceStable(l.findings, func(i, j int) bool {
+		this, that := l.findings[i].Location, l.findings[j].Location
+		if this.File != that.File {
+			return this.File < that.File
+		}
+		return this.Line < that.Line
+	})
+
+	return l.findings
+}
+
+type linter struct {
+	in       *Input
+	croName  string
+	findings []Finding
+}
+
+func (l *linter) report(rule Rule, kind string, object metav1.Object, format string, args ...interface{}) {
+	l.findings = append(l.findings, Finding{
+		Rule:      rule.ID,
+		Severity:  rule.Severity,
+		Message:   fmt.Sprintf(format, args...),
+		Kind:      kind,
+		Namespace: object.GetNamespace(),
+		Name:      object.GetName(),
+		Location:  l.in.location(object),
+	})
+}
+
+// clusterResourceOverrides checks each ClusterResourceOverride, and returns the
+// one the operator reconciles, nil if none is given.
+func (l *linter) clusterResourceOverrides() *operatorv1.ClusterResourceOverride {
+	var reconciled *operatorv1.ClusterResourceOverride
+	seen := map[string]*operatorv1.ClusterResourceOverride{}
+	for _, cro := range l.in.ClusterResourceOverrides {
+		if first, ok := seen[cro.Name]; ok {
+			l.report(DuplicateObject, "ClusterResourceOverride", cro, "ClusterResourceOverride %q is already defined at %s", cro.Name, l.in.location(first))
+			continue
+		}
+		seen[cro.Name] = cro
+
+		if cro.Name != l.croName {
+			l.report(IgnoredClusterResourceOverride, "ClusterResourceOverride", cro, "ClusterResourceOverride %q is ignored, the operator only reconciles %q", cro.Name, l.croName)
+		} else {
+			reconciled = cro
+		}
+
+		if err := render.Validate(cro); err != nil {
+			l.report(InvalidClusterResourceOverride, "ClusterResourceOverride", cro, "ClusterResourceOverride %q has invalid parameters: %s", cro.Name, err.Error())
+		}
+	}
+
+	return reconciled
+}
+
+// resourceOverrides checks each ResourceOverride, and returns those that are
+// valid, without duplicates.
+func (l *linter) resourceOverrides() []*autoscalingv1.ResourceOverride {
+	valid := make([]*autoscalingv1.ResourceOverride, 0, len(l.in.ResourceOverrides))
+	seen := map[string]*autoscalingv1.ResourceOverride{}
+	for _, ro := range l.in.ResourceOverrides {
+		key := ro.Namespace + "/" + ro.Name
+		if first, ok := seen[key]; ok {
+			l.report(DuplicateObject, autoscalingv1.ResourceOverrideKind, ro, "ResourceOverride %s is already defined at %s", key, l.in.location(first))
+			continue
+		}
+		seen[key] = ro
+
+		if ro.Namespace != "" && asset.IsExemptNamespace(ro.Namespace) {
+			l.report(ExemptNamespace, autoscalingv1.ResourceOverrideKind, ro, "ResourceOverride %s is rejected, ResourceOverride objects cannot be created in namespace %q", key, ro.Namespace)
+		}
+
+		copy := ro.DeepCopy()
+		reconciler.Validate(copy)
+		if failure := condition.Find(&copy.Status, autoscalingv1.ValidationFailure); failure != nil && failure.Status == corev1.ConditionTrue {
+			l.report(InvalidResourceOverride, autoscalingv1.ResourceOverrideKind, ro, "%s", failure.Message)
+			continue
+		}
+
+		valid = append(valid, ro)
+	}
+
+	return valid
+}
+
+// overlaps reports each valid ResourceOverride whose podSelector provably
+// selects pods that another one in the same namespace also selects. The
+// finding is reported on the one that comes last by name.
+func (l *linter) overlaps(ros []*autoscalingv1.ResourceOverride) {
+	matchers := make([]*reconciler.Matcher, len(ros))
+	for i, ro := range ros {
+		// a valid ResourceOverride has a valid matcher.
+		matchers[i], _ = reconciler.NewMatcher(&ro.Spec)
+	}
+
+	for i := range ros {
+		for j := i + 1; j < len(ros); j++ {
+			if ros[i].Namespace != ros[j].Namespace || !reconciler.Overlaps(matchers[i], matchers[j], nil) {
+				continue
+			}
+
+			first, second := ros[i], ros[j]
+			if second.Name < first.Name {
+				first, second = second, first
+			}
+			l.report(OverlappingResourceOverride, autoscalingv1.ResourceOverrideKind, second,
+				"podSelector of ResourceOverride %s/%s overlaps with ResourceOverride %s, the admission webhook applies only one of them", second.Namespace, second.Name, first.Name)
+		}
+	}
+}
+
+// namespaces reports each valid ResourceOverride in a given namespace that is
+// not opted in. One in an exempt namespace is already reported as rejected.
+func (l *linter) namespaces(ros []*autoscalingv1.ResourceOverride, cro *operatorv1.ClusterResourceOverride) {
+	var policy *operatorv1.AutoOptInPolicy
+	if cro != nil {
+		policy = cro.Spec.AutoOptIn
+	}
+
+	namespaces := map[string]*corev1.Namespace{}
+	for _, ns := range l.in.Namespaces {
+		namespaces[ns.Name] = ns
+	}
+
+	for _, ro := range ros {
+		ns, ok := namespaces[ro.Namespace]
+		if !ok || asset.IsExemptNamespace(ro.Namespace) || reconciler.OptedIn(ns, policy) {
+			continue
+		}
+
+		l.report(NamespaceNotOptedIn, autoscalingv1.ResourceOverrideKind, ro,
+			"ResourceOverride %s/%s is ignored, namespace %q does not have the %s=true label", ro.Namespace, ro.Name, ro.Namespace, asset.NamespaceOptInLabelKey)
+	}
+}
@@ -0,0 +1,332 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const clusterResourceOverride = `apiVersion: operator.autoscaling.openshift.io/v1
+kind: ClusterResourceOverride
+metadata:
+  name: cluster
+spec:
+  podResourceOverride:
+    spec:
+      memoryRequestToLimitPercent: 50
+`
+
+func lint(manifests string) []Finding {
+	in := &Input{}
+	in.Read("manifests.yaml", []byte(manifests))
+	return Lint(in, "cluster")
+}
+
+func rules(findings []Finding) []string {
+	ids := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		ids = append(ids, finding.Rule)
+	}
+	return ids
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name      string
+		manifests string
+		want      []string
+	}{
+		{
+			name: "valid",
+			manifests: clusterResourceOverride + `---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: test
+  labels:
+    clusterresourceoverrides.admission.autoscaling.openshift.io/enabled: "true"
+---
+apiVersion: autoscaling.openshift.io/v1
+kind: ResourceOverride
+metadata:
+  name: web
+  namespace: test
+spec:
+  podSelector:
+    matchLabels:
+      app: web
+  podResourceOverride:
+    memoryRequestToLimitPercent: 75
+---
+apiVersion: autoscaling.openshift.io/v1
+kind: ResourceOverride
+metadata:
+  name: db
+  namespace: test
+spec:
+  podSelector:
+    matchLabels:
+      app: db
+  podResourceOverride:
+    memoryRequestToLimitPercent: 75
+`,
+			want: []string{},
+		},
+		{
+			name: "invalid ClusterResourceOverride",
+			manifests: `apiVersion: operator.autoscaling.openshift.io/v1
+kind: ClusterResourceOverride
+metadata:
+  name: other
+spec:
+  podResourceOverride:
+    spec:
+      memoryRequestToLimitPercent: 150
+`,
+			want: []string{IgnoredClusterResourceOverride.ID, InvalidClusterResourceOverride.ID},
+		},
+		{
+			name: "invalid ResourceOverride",
+			manifests: `apiVersion: autoscaling.openshift.io/v1
+kind: ResourceOverride
+metadata:
+  name: ratio
+  namespace: test
+spec:
+  podResourceOverride:
+    cpuRequestToLimitPercent: 200
+---
+apiVersion: autoscaling.openshift.io/v1
+kind: ResourceOverride
+metadata:
+  name: selector
+  namespace: test
+spec:
+  podSelector:
+    matchExpressions:
+    - key: app
+      operator: Equals
+      values: [web]
+  podResourceOverride:
+    cpuRequestToLimitPercent: 20
+`,
+			want: []string{InvalidResourceOverride.ID, InvalidResourceOverride.ID},
+		},
+		{
+			name: "duplicate and overlapping ResourceOverride(s)",
+			manifests: `apiVersion: v1
+kind: List
+items:
+- apiVersion: autoscaling.openshift.io/v1
+  kind: ResourceOverride
+  metadata:
+    name: all
+    namespace: test
+  spec:
+    podResourceOverride:
+      cpuRequestToLimitPercent: 20
+- apiVersion: autoscaling.openshift.io/v1
+  kind: ResourceOverride
+  metadata:
+    name: all
+    namespace: test
+  spec:
+    podResourceOverride:
+      cpuRequestToLimitPercent: 30
+- apiVersion: autoscaling.openshift.io/v1
+  kind: ResourceOverride
+  metadata:
+    name: web
+    namespace: test
+  spec:
+    podSelector:
+      matchLabels:
+        app: web
+    podResourceOverride:
+      cpuRequestToLimitPercent: 40
+`,
+			want: []string{DuplicateObject.ID, OverlappingResourceOverride.ID},
+		},
+		{
+			name: "exempt and not opted-in namespaces",
+			manifests: clusterResourceOverride + `---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: test
+---
+apiVersion: autoscaling.openshift.io/v1
+kind: ResourceOverride
+metadata:
+  name: system
+  namespace: openshift-monitoring
+spec:
+  podResourceOverride:
+    cpuRequestToLimitPercent: 20
+---
+apiVersion: autoscaling.openshift.io/v1
+kind: ResourceOverride
+metadata:
+  name: test
+  namespace: test
+spec:
+  podResourceOverride:
+    cpuRequestToLimitPercent: 20
+---
+apiVersion: autoscaling.openshift.io/v1
+kind: ResourceOverride
+metadata:
+  name: unknown
+  namespace: unknown
+spec:
+  podResourceOverride:
+    cpuRequestToLimitPercent: 20
+`,
+			want: []string{ExemptNamespace.ID, NamespaceNotOptedIn.ID},
+		},
+		{
+			name: "namespace opted in by autoOptIn",
+			manifests: `apiVersion: operator.autoscaling.openshift.io/v1
+kind: ClusterResourceOverride
+metadata:
+  name: cluster
+spec:
+  podResourceOverride:
+    spec:
+      memoryRequestToLimitPercent: 50
+  autoOptIn:
+    namespaceSelector:
+      matchLabels:
+        team: a
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: test
+  labels:
+    team: a
+---
+apiVersion: autoscaling.openshift.io/v1
+kind: ResourceOverride
+metadata:
+  name: test
+  namespace: test
+spec:
+  podResourceOverride:
+    cpuRequestToLimitPercent: 20
+`,
+			want: []string{},
+		},
+		{
+			name:      "invalid manifest",
+			manifests: "kind: ResourceOverride\nspec: [\n",
+			want:      []string{InvalidManifest.ID},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.want, rules(lint(test.manifests)))
+		})
+	}
+}
+
+func TestLintLocationAndMessage(t *testing.T) {
+	findings := lint(clusterResourceOverride + `---
+apiVersion: autoscaling.openshift.io/v1
+kind: ResourceOverride
+metadata:
+  name: all
+  namespace: test
+spec:
+  podResourceOverride:
+    cpuRequestToLimitPercent: 20
+---
+apiVersion: autoscaling.openshift.io/v1
+kind: ResourceOverride
+metadata:
+  name: web
+  namespace: test
+spec:
+  podSelector:
+    matchLabels:
+      app: web
+  podResourceOverride:
+    cpuRequestToLimitPercent: 40
+`)
+
+	require.Equal(t, []Finding{
+		{
+			Rule:      OverlappingResourceOverride.ID,
+			Severity:  SeverityWarning,
+			Message:   "podSelector of ResourceOverride test/web overlaps with ResourceOverride all, the admission webhook applies only one of them",
+			Kind:      "ResourceOverride",
+			Namespace: "test",
+			Name:      "web",
+			Location:  Location{File: "manifests.yaml", Line: 19},
+		},
+	}, findings)
+}
+
+func TestLoad(t *testing.T) {
+	directory := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(directory, "overlays"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "cro.yaml"), []byte(clusterResourceOverride), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "overlays", "ro.yml"), []byte(`apiVersion: autoscaling.openshift.io/v1
+kind: ResourceOverride
+metadata:
+  name: test
+  namespace: test
+spec:
+  podResourceOverride:
+    cpuRequestToLimitPercent: 20
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "README.md"), []byte("kind: ResourceOverride"), 0644))
+
+	in, err := Load(directory)
+	require.NoError(t, err)
+	require.Len(t, in.ClusterResourceOverrides, 1)
+	require.Len(t, in.ResourceOverrides, 1)
+
+	_, err = Load(filepath.Join(directory, "missing.yaml"))
+	require.Error(t, err)
+}
+
+func TestWriteSARIF(t *testing.T) {
+	findings := lint(`apiVersion: autoscaling.openshift.io/v1
+kind: ResourceOverride
+metadata:
+  name: system
+  namespace: kube-system
+spec:
+  podResourceOverride:
+    cpuRequestToLimitPercent: 20
+`)
+
+	buffer := &bytes.Buffer{}
+	require.NoError(t, WriteSARIF(buffer, findings))
+
+	log := sarifLog{}
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &log))
+	require.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	require.Len(t, log.Runs[0].Tool.Driver.Rules, len(Rules))
+	require.Len(t, log.Runs[0].Results, 1)
+
+	result := log.Runs[0].Results[0]
+	require.Equal(t, ExemptNamespace.ID, result.RuleID)
+	require.Equal(t, ExemptNamespace.ID, log.Runs[0].Tool.Driver.Rules[result.RuleIndex].ID)
+	require.Equal(t, SeverityError, result.Level)
+	require.Equal(t, "manifests.yaml", result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	require.Equal(t, 1, result.Locations[0].PhysicalLocation.Region.StartLine)
+}
+
+func TestWriteJSON(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	require.NoError(t, WriteJSON(buffer, nil))
+	require.JSONEq(t, `{"findings": []}`, buffer.String())
+}
@@ -0,0 +1,159 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	autoscalingv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/autoscaling/v1"
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/manifest"
+)
+
+// Location is where an object is defined.
+type Location struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+func (l Location) String() string {
+	return fmt.Sprintf("%s:%d", l.File, l.Line)
+}
+
+// Input holds the object(s) to lint.
+type Input struct {
+	ClusterResourceOverrides []*operatorv1.ClusterResourceOverride
+	ResourceOverrides        []*autoscalingv1.ResourceOverride
+	Namespaces               []*corev1.Namespace
+
+	locations map[metav1.Object]Location
+	findings  []Finding
+}
+
+// Load reads the given manifest files, and the .yaml, .yml and .json files in
+// the given directories and their subdirectories.
+func Load(paths ...string) (*Input, error) {
+	in := &Input{}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			if err := in.ReadFile(path); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return err
+			}
+
+			switch filepath.Ext(file) {
+			case ".yaml", ".yml", ".json":
+				return in.ReadFile(file)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return in, nil
+}
+
+// ReadFile adds the object(s) of the given manifest file to the input.
+func (in *Input) ReadFile(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	in.Read(file, data)
+	return nil
+}
+
+// Read adds the ClusterResourceOverride, ResourceOverride and Namespace
+// object(s) of the given multi-document YAML stream to the input. A List is
+// expanded into its items, other kinds are skipped. A document that cannot be
+// parsed is reported as a finding.
+func (in *Input) Read(file string, data []byte) {
+	offset := 0
+	for _, document := range manifest.Split(data) {
+		// a document is a slice of the stream, its line is where it is found.
+		if index := bytes.Index(data[offset:], document); index >= 0 {
+			offset += index
+		}
+		location := Location{File: file, Line: bytes.Count(data[:offset], []byte("\n")) + 1}
+		offset += len(document)
+
+		if err := in.read(document, location); err != nil {
+			in.findings = append(in.findings, Finding{
+				Rule:     InvalidManifest.ID,
+				Severity: InvalidManifest.Severity,
+				Message:  err.Error(),
+				Location: location,
+			})
+		}
+	}
+}
+
+func (in *Input) read(document []byte, location Location) error {
+	object := struct {
+		Kind  string            `json:"kind"`
+		Items []json.RawMessage `json:"items"`
+	}{}
+	if err := yaml.Unmarshal(document, &object); err != nil {
+		return fmt.Errorf("failed to parse manifest - %s", err.Error())
+	}
+
+	var target metav1.Object
+	switch object.Kind {
+	case "List":
+		for _, item := range object.Items {
+			if err := in.read(item, location); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "ClusterResourceOverride":
+		cro := &operatorv1.ClusterResourceOverride{}
+		in.ClusterResourceOverrides = append(in.ClusterResourceOverrides, cro)
+		target = cro
+	case autoscalingv1.ResourceOverrideKind:
+		ro := &autoscalingv1.ResourceOverride{}
+		in.ResourceOverrides = append(in.ResourceOverrides, ro)
+		target = ro
+	case "Namespace":
+		ns := &corev1.Namespace{}
+		in.Namespaces = append(in.Namespaces, ns)
+		target = ns
+	default:
+		return nil
+	}
+
+	if err := yaml.Unmarshal(document, target); err != nil {
+		return fmt.Errorf("failed to parse %s - %s", object.Kind, err.Error())
+	}
+
+	if in.locations == nil {
+		in.locations = map[metav1.Object]Location{}
+	}
+	in.locations[target] = location
+	return nil
+}
+
+func (in *Input) location(object metav1.Object) Location {
+	return in.locations[object]
+}
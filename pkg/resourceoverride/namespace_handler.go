@@ -3,6 +3,8 @@ package resourceoverride
 import (
 	"context"
 	"reflect"
+	"sort"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -11,10 +13,13 @@ import (
 	"k8s.io/klog/v2"
 	controllerreconciler "sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
 	listers "github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/listers/autoscaling/v1"
 )
 
-// NamespaceWatchStarterFunc starts the namespace informer and waits for cache sync.
+// NamespaceWatchStarterFunc starts the secondary informers of the resourceoverride
+// controller (namespaces and the ClusterResourceOverride among others) and
+// waits for cache sync. It also starts recording events and the pod cache.
 type NamespaceWatchStarterFunc func(ctx context.Context) error
 
 type namespaceEventHandler struct {
@@ -22,7 +27,19 @@ type namespaceEventHandler struct {
 	queue    workqueue.RateLimitingInterface
 }
 
-func (h *namespaceEventHandler) OnAdd(obj interface{}, isInInitialList bool) {}
+func (h *namespaceEventHandler) OnAdd(obj interface{}, isInInitialList bool) {
+	ns, ok := obj.(*corev1.Namespace)
+	if !ok {
+		return
+	}
+
+	// publish the policy configmap of every opted-in namespace, including
+	// those without a ResourceOverride, and translate the legacy annotation.
+	_, legacy := ns.Annotations[asset.LegacyOverrideEnabledAnnotationKey]
+	if ns.Labels[asset.NamespaceOptInLabelKey] == "true" || legacy {
+		enqueueNamespace(h.queue, ns.Name)
+	}
+}
 
 func (h *namespaceEventHandler) OnUpdate(oldObj, newObj interface{}) {
 	oldNs, ok := oldObj.(*corev1.Namespace)
@@ -34,25 +51,65 @@ func (h *namespaceEventHandler) OnUpdate(oldObj, newObj interface{}) {
 		return
 	}
 
+	if oldNs.Labels[asset.NamespaceOptInLabelKey] != newNs.Labels[asset.NamespaceOptInLabelKey] ||
+		oldNs.Annotations[asset.NamespaceAutoOptInAnnotationKey] != newNs.Annotations[asset.NamespaceAutoOptInAnnotationKey] ||
+		oldNs.Annotations[asset.LegacyOverrideEnabledAnnotationKey] != newNs.Annotations[asset.LegacyOverrideEnabledAnnotationKey] {
+		enqueueNamespace(h.queue, newNs.Name)
+		klog.V(4).Infof("[resourceoverride] namespace=%s opt-in changed, enqueued namespace", newNs.Name)
+	}
+
 	if reflect.DeepEqual(oldNs.Labels, newNs.Labels) {
 		return
 	}
 
-	ros, err := h.roLister.ResourceOverrides(newNs.Name).List(labels.Everything())
-	if err != nil || len(ros) == 0 {
+	count := enqueueResourceOverrides(h.roLister, h.queue, newNs.Name)
+	if count == 0 {
 		return
 	}
 
+	klog.V(4).Infof("[resourceoverride] namespace=%s labels changed, enqueued %d ResourceOverride(s)", newNs.Name, count)
+}
+
+func (h *namespaceEventHandler) OnDelete(obj interface{}) {}
+
+// enqueueNamespace adds a request without a name for the given namespace to
+// the work queue, the reconciler then publishes its policy configmap.
+func enqueueNamespace(queue workqueue.RateLimitingInterface, namespace string) {
+	queue.Add(controllerreconciler.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: namespace,
+		},
+	})
+}
+
+// enqueueResourceOverrides adds every ResourceOverride in the given namespace
+// to the work queue and returns the number of object(s) enqueued.
+func enqueueResourceOverrides(roLister listers.ResourceOverrideLister, queue workqueue.RateLimitingInterface, namespace string) int {
+	return enqueueResourceOverridesAfter(roLister, queue, namespace, 0)
+}
+
+// enqueueResourceOverridesAfter is enqueueResourceOverrides with the requests
+// added after the given delay. A request already waiting keeps its earlier
+// deadline, so repeated calls within the delay result in a single reconcile.
+func enqueueResourceOverridesAfter(roLister listers.ResourceOverrideLister, queue workqueue.RateLimitingInterface, namespace string, delay time.Duration) int {
+	ros, err := roLister.ResourceOverrides(namespace).List(labels.Everything())
+	if err != nil {
+		return 0
+	}
+
+	// enqueue in a stable order, the lister does not guarantee one.
+	sort.Slice(ros, func(i, j int) bool {
+		return ros[i].Name < ros[j].Name
+	})
+
 	for _, ro := range ros {
-		h.queue.Add(controllerreconciler.Request{
+		queue.AddAfter(controllerreconciler.Request{
 			NamespacedName: types.NamespacedName{
 				Namespace: ro.Namespace,
 				Name:      ro.Name,
 			},
-		})
+		}, delay)
 	}
 
-	klog.V(4).Infof("[resourceoverride] namespace=%s labels changed, enqueued %d ResourceOverride(s)", newNs.Name, len(ros))
+	return len(ros)
 }
-
-func (h *namespaceEventHandler) OnDelete(obj interface{}) {}
@@ -13,6 +13,7 @@ import (
 	controllerreconciler "sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	autoscalingv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/autoscaling/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
 	listers "github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/listers/autoscaling/v1"
 )
 
@@ -107,3 +108,73 @@ func TestNamespaceEventHandlerOnUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestNamespaceEventHandlerEnqueuesNamespace(t *testing.T) {
+	optedIn := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-ns",
+			Labels: map[string]string{asset.NamespaceOptInLabelKey: "true"},
+		},
+	}
+	notOptedIn := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ns"},
+	}
+	legacy := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-ns",
+			Annotations: map[string]string{asset.LegacyOverrideEnabledAnnotationKey: "false"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		invoke func(h *namespaceEventHandler)
+		want   []types.NamespacedName
+	}{
+		{
+			name:   "opted-in namespace added",
+			invoke: func(h *namespaceEventHandler) { h.OnAdd(optedIn, true) },
+			want:   []types.NamespacedName{{Namespace: "test-ns"}},
+		},
+		{
+			name:   "namespace with legacy annotation added",
+			invoke: func(h *namespaceEventHandler) { h.OnAdd(legacy, true) },
+			want:   []types.NamespacedName{{Namespace: "test-ns"}},
+		},
+		{
+			name:   "namespace not opted in added",
+			invoke: func(h *namespaceEventHandler) { h.OnAdd(notOptedIn, true) },
+		},
+		{
+			name:   "namespace opted out",
+			invoke: func(h *namespaceEventHandler) { h.OnUpdate(optedIn, notOptedIn) },
+			want: []types.NamespacedName{
+				{Namespace: "test-ns"},
+				{Namespace: "test-ns", Name: "ro-1"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			defer queue.ShutDown()
+
+			handler := &namespaceEventHandler{
+				roLister: newTestROLister(&autoscalingv1.ResourceOverride{
+					ObjectMeta: metav1.ObjectMeta{Name: "ro-1", Namespace: "test-ns"},
+				}),
+				queue: queue,
+			}
+
+			test.invoke(handler)
+
+			require.Equal(t, len(test.want), queue.Len())
+			for _, want := range test.want {
+				item, _ := queue.Get()
+				require.Equal(t, want, item.(controllerreconciler.Request).NamespacedName)
+				queue.Done(item)
+			}
+		})
+	}
+}
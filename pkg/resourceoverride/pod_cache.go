@@ -0,0 +1,161 @@
+package resourceoverride
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+var (
+	podsResource = corev1.SchemeGroupVersion.WithResource("pods")
+
+	// activePodsSelector leaves out the pods that have terminated, they are
+	// not counted as matched by a ResourceOverride. A pod that terminates is
+	// removed from the cache.
+	activePodsSelector = fields.AndSelectors(
+		fields.OneTermNotEqualSelector("status.phase", string(corev1.PodSucceeded)),
+		fields.OneTermNotEqualSelector("status.phase", string(corev1.PodFailed)),
+	)
+)
+
+// podCache caches the metadata of the pods, not their spec or status, in the
+// namespace(s) where a ResourceOverride is in effect. Pods that have
+// terminated are not cached. Each namespace has its own informer, started by
+// Watch and stopped by Forget.
+type podCache struct {
+	client  metadata.Interface
+	resync  time.Duration
+	handler cache.ResourceEventHandler
+
+	// onSynced is called once the pods in a namespace are cached.
+	onSynced func(namespace string)
+
+	lock       sync.Mutex
+	ctx        context.Context
+	namespaces map[string]*namespacePods
+}
+
+type namespacePods struct {
+	informer cache.SharedIndexInformer
+	cancel   context.CancelFunc
+}
+
+func newPodCache(client metadata.Interface, resync time.Duration, handler cache.ResourceEventHandler, onSynced func(namespace string)) *podCache {
+	return &podCache{
+		client:     client,
+		resync:     resync,
+		handler:    handler,
+		onSynced:   onSynced,
+		namespaces: map[string]*namespacePods{},
+	}
+}
+
+// Run starts the informer of every namespace watched so far, those watched
+// later are started right away. They all stop when the given context is done.
+func (c *podCache) Run(ctx context.Context) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.ctx = ctx
+	for namespace, pods := range c.namespaces {
+		c.start(namespace, pods)
+	}
+}
+
+func (c *podCache) Watch(namespace string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, ok := c.namespaces[namespace]; ok {
+		return
+	}
+
+	informer := metadatainformer.NewFilteredMetadataInformer(c.client, podsResource, namespace, c.resync, cache.Indexers{}, func(options *metav1.ListOptions) {
+		options.FieldSelector = activePodsSelector.String()
+	}).Informer()
+	informer.SetTransform(func(obj interface{}) (interface{}, error) {
+		// the managed fields are not needed to match a pod and take up most
+		// of its metadata.
+		if accessor, err := meta.Accessor(obj); err == nil {
+			accessor.SetManagedFields(nil)
+		}
+		return obj, nil
+	})
+	informer.AddEventHandler(c.handler)
+
+	pods := &namespacePods{
+		informer: informer,
+	}
+	c.namespaces[namespace] = pods
+
+	if c.ctx != nil {
+		c.start(namespace, pods)
+	}
+}
+
+func (c *podCache) Forget(namespace string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	pods, ok := c.namespaces[namespace]
+	if !ok {
+		return
+	}
+
+	if pods.cancel != nil {
+		pods.cancel()
+	}
+	delete(c.namespaces, namespace)
+
+	klog.V(2).Infof("[resourceoverride] namespace=%s stopped watching pods", namespace)
+}
+
+func (c *podCache) Pods(namespace string) (pods []metav1.Object, synced bool, err error) {
+	c.lock.Lock()
+	cached, ok := c.namespaces[namespace]
+	c.lock.Unlock()
+
+	if !ok || !cached.informer.HasSynced() {
+		return
+	}
+
+	objs := cached.informer.GetStore().List()
+	pods = make([]metav1.Object, 0, len(objs))
+	for _, obj := range objs {
+		pod, accessorErr := meta.Accessor(obj)
+		if accessorErr != nil {
+			err = accessorErr
+			return
+		}
+
+		pods = append(pods, pod)
+	}
+
+	synced = true
+	return
+}
+
+// start runs the informer of the given namespace, the lock must be held.
+func (c *podCache) start(namespace string, pods *namespacePods) {
+	ctx, cancel := context.WithCancel(c.ctx)
+	pods.cancel = cancel
+
+	go pods.informer.Run(ctx.Done())
+	go func() {
+		if !cache.WaitForCacheSync(ctx.Done(), pods.informer.HasSynced) {
+			return
+		}
+
+		klog.V(2).Infof("[resourceoverride] namespace=%s started watching pods", namespace)
+		c.onSynced(namespace)
+	}()
+}
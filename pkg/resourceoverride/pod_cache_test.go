@@ -0,0 +1,106 @@
+package resourceoverride
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	metadatafake "k8s.io/client-go/metadata/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newPodMetadata(namespace, name string) *metav1.PartialObjectMetadata {
+	return &metav1.PartialObjectMetadata{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:          name,
+			Namespace:     namespace,
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubelet"}},
+		},
+	}
+}
+
+func TestPodCache(t *testing.T) {
+	scheme := metadatafake.NewTestScheme()
+	require.NoError(t, metav1.AddMetaToScheme(scheme))
+	client := metadatafake.NewSimpleMetadataClient(scheme,
+		newPodMetadata("test-ns", "web-0"),
+		newPodMetadata("test-ns", "web-1"),
+		newPodMetadata("other-ns", "db-0"),
+	)
+
+	synced := make(chan string, 2)
+	c := newPodCache(client, 0, cache.ResourceEventHandlerFuncs{}, func(namespace string) {
+		synced <- namespace
+	})
+
+	// a namespace watched before Run is started along with the cache.
+	c.Watch("test-ns")
+	_, ok, err := c.Pods("test-ns")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	c.Run(t.Context())
+	select {
+	case namespace := <-synced:
+		require.Equal(t, "test-ns", namespace)
+	case <-time.After(5 * time.Second):
+		t.Fatal("pods in namespace test-ns were not cached")
+	}
+
+	pods, ok, err := c.Pods("test-ns")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Len(t, pods, 2)
+	for _, pod := range pods {
+		require.Equal(t, "test-ns", pod.GetNamespace())
+		require.Nil(t, pod.GetManagedFields())
+	}
+
+	// a namespace that is not watched is never synced.
+	_, ok, err = c.Pods("other-ns")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// terminated pods are left out by the apiserver.
+	listed := false
+	for _, action := range client.Actions() {
+		if list, ok := action.(clienttesting.ListAction); ok {
+			listed = true
+			selector := list.GetListRestrictions().Fields
+			require.True(t, selector.Matches(fields.Set{"status.phase": string(corev1.PodRunning)}))
+			require.False(t, selector.Matches(fields.Set{"status.phase": string(corev1.PodSucceeded)}))
+			require.False(t, selector.Matches(fields.Set{"status.phase": string(corev1.PodFailed)}))
+		}
+	}
+	require.True(t, listed)
+
+	c.Forget("test-ns")
+	_, ok, err = c.Pods("test-ns")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestActivePodsSelector(t *testing.T) {
+	tests := []struct {
+		phase corev1.PodPhase
+		want  bool
+	}{
+		{phase: corev1.PodPending, want: true},
+		{phase: corev1.PodRunning, want: true},
+		{phase: corev1.PodUnknown, want: true},
+		{phase: corev1.PodSucceeded, want: false},
+		{phase: corev1.PodFailed, want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.phase), func(t *testing.T) {
+			require.Equal(t, test.want, activePodsSelector.Matches(fields.Set{"status.phase": string(test.phase)}))
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package resourceoverride
+
+import (
+	"reflect"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	listers "github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/listers/autoscaling/v1"
+	operatorruntime "github.com/openshift/cluster-resource-override-admission-operator/pkg/runtime"
+)
+
+const (
+	// PodEventDelay is how long the ResourceOverride(s) in a namespace wait
+	// after a pod event before they are reconciled, the events in between
+	// are folded into a single status update.
+	PodEventDelay = 30 * time.Second
+)
+
+// podEventHandler enqueues the ResourceOverride(s) in the namespace of a pod
+// that was added, deleted or relabeled, since the set of pods selected by a
+// ResourceOverride determines how many pods it matches and whether it
+// conflicts with another one.
+type podEventHandler struct {
+	roLister listers.ResourceOverrideLister
+	queue    workqueue.RateLimitingInterface
+	delay    time.Duration
+}
+
+func (h *podEventHandler) OnAdd(obj interface{}, isInInitialList bool) {
+	if isInInitialList {
+		// the initial list of ResourceOverride(s) is enqueued by its own informer.
+		return
+	}
+
+	pod, ok := obj.(metav1.Object)
+	if !ok {
+		return
+	}
+
+	h.enqueue(pod.GetNamespace(), "added")
+}
+
+func (h *podEventHandler) OnUpdate(oldObj, newObj interface{}) {
+	oldPod, ok := oldObj.(metav1.Object)
+	if !ok {
+		return
+	}
+	newPod, ok := newObj.(metav1.Object)
+	if !ok {
+		return
+	}
+
+	if reflect.DeepEqual(oldPod.GetLabels(), newPod.GetLabels()) {
+		return
+	}
+
+	h.enqueue(newPod.GetNamespace(), "labels changed")
+}
+
+func (h *podEventHandler) OnDelete(obj interface{}) {
+	metaObj, err := operatorruntime.GetMetaObject(obj)
+	if err != nil {
+		return
+	}
+
+	h.enqueue(metaObj.GetNamespace(), "deleted")
+}
+
+func (h *podEventHandler) enqueue(namespace, event string) {
+	count := enqueueResourceOverridesAfter(h.roLister, h.queue, namespace, h.delay)
+	if count == 0 {
+		return
+	}
+
+	klog.V(4).Infof("[resourceoverride] namespace=%s pod %s, enqueued %d ResourceOverride(s)", namespace, event, count)
+}
@@ -0,0 +1,107 @@
+package resourceoverride
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+
+	autoscalingv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/autoscaling/v1"
+)
+
+func TestPodEventHandler(t *testing.T) {
+	ros := []*autoscalingv1.ResourceOverride{
+		{ObjectMeta: metav1.ObjectMeta{Name: "ro-1", Namespace: "test-ns"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ro-2", Namespace: "test-ns"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ro-3", Namespace: "other-ns"}},
+	}
+	// the pod cache holds the metadata of the pods only.
+	pod := &metav1.PartialObjectMetadata{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod",
+			Namespace: "test-ns",
+			Labels:    map[string]string{"app": "web"},
+		},
+	}
+	relabeled := pod.DeepCopy()
+	relabeled.Labels["app"] = "db"
+
+	tests := []struct {
+		name         string
+		invoke       func(h *podEventHandler)
+		wantEnqueued int
+	}{
+		{
+			name:         "pod added",
+			invoke:       func(h *podEventHandler) { h.OnAdd(pod, false) },
+			wantEnqueued: 2,
+		},
+		{
+			name:         "pod in initial list",
+			invoke:       func(h *podEventHandler) { h.OnAdd(pod, true) },
+			wantEnqueued: 0,
+		},
+		{
+			name:         "pod labels changed",
+			invoke:       func(h *podEventHandler) { h.OnUpdate(pod, relabeled) },
+			wantEnqueued: 2,
+		},
+		{
+			name:         "pod labels unchanged",
+			invoke:       func(h *podEventHandler) { h.OnUpdate(pod, pod.DeepCopy()) },
+			wantEnqueued: 0,
+		},
+		{
+			name:         "pod deleted",
+			invoke:       func(h *podEventHandler) { h.OnDelete(pod) },
+			wantEnqueued: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			defer queue.ShutDown()
+
+			handler := &podEventHandler{
+				roLister: newTestROLister(ros...),
+				queue:    queue,
+			}
+
+			test.invoke(handler)
+
+			require.Equal(t, test.wantEnqueued, queue.Len())
+		})
+	}
+}
+
+func TestPodEventHandlerDelay(t *testing.T) {
+	ros := []*autoscalingv1.ResourceOverride{
+		{ObjectMeta: metav1.ObjectMeta{Name: "ro-1", Namespace: "test-ns"}},
+	}
+	pod := &metav1.PartialObjectMetadata{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "test-ns"},
+	}
+
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer queue.ShutDown()
+
+	handler := &podEventHandler{
+		roLister: newTestROLister(ros...),
+		queue:    queue,
+		delay:    100 * time.Millisecond,
+	}
+
+	// a burst of pod events results in a single request, once the delay is up.
+	for i := 0; i < 10; i++ {
+		handler.OnAdd(pod, false)
+		handler.OnDelete(pod)
+	}
+	require.Equal(t, 0, queue.Len())
+
+	require.Eventually(t, func() bool { return queue.Len() == 1 }, 5*time.Second, 10*time.Millisecond)
+	require.Never(t, func() bool { return queue.Len() > 1 }, 200*time.Millisecond, 10*time.Millisecond)
+}
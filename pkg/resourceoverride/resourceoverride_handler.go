@@ -0,0 +1,88 @@
+package resourceoverride
+
+import (
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/controller"
+	listers "github.com/openshift/cluster-resource-override-admission-operator/pkg/generated/listers/autoscaling/v1"
+	operatorruntime "github.com/openshift/cluster-resource-override-admission-operator/pkg/runtime"
+)
+
+// resourceOverrideEventHandler enqueues the ResourceOverride that changed and,
+// when it is added, deleted or its spec changes, every other ResourceOverride
+// in the same namespace so that conflicts are re-evaluated on both sides.
+// The namespace itself is enqueued when a ResourceOverride is added or deleted.
+type resourceOverrideEventHandler struct {
+	controller.EventHandler
+	roLister listers.ResourceOverrideLister
+	queue    workqueue.RateLimitingInterface
+}
+
+func newResourceOverrideEventHandler(queue workqueue.RateLimitingInterface) *resourceOverrideEventHandler {
+	return &resourceOverrideEventHandler{
+		EventHandler: controller.NewEventHandler(queue),
+		queue:        queue,
+	}
+}
+
+func (h *resourceOverrideEventHandler) OnAdd(obj interface{}, isInInitialList bool) {
+	h.EventHandler.OnAdd(obj, isInInitialList)
+	if isInInitialList {
+		return
+	}
+
+	h.enqueueSiblings(obj, "added")
+	h.enqueueNamespace(obj)
+}
+
+func (h *resourceOverrideEventHandler) OnUpdate(oldObj, newObj interface{}) {
+	h.EventHandler.OnUpdate(oldObj, newObj)
+
+	oldMetaObj, err := operatorruntime.GetMetaObject(oldObj)
+	if err != nil {
+		return
+	}
+	newMetaObj, err := operatorruntime.GetMetaObject(newObj)
+	if err != nil {
+		return
+	}
+
+	// status updates do not bump the generation, only spec changes do.
+	if oldMetaObj.GetGeneration() == newMetaObj.GetGeneration() {
+		return
+	}
+
+	h.enqueueSiblings(newObj, "spec changed")
+}
+
+func (h *resourceOverrideEventHandler) OnDelete(obj interface{}) {
+	h.EventHandler.OnDelete(obj)
+	h.enqueueSiblings(obj, "deleted")
+	h.enqueueNamespace(obj)
+}
+
+// enqueueNamespace enqueues the namespace of the given ResourceOverride so
+// that the list of ResourceOverride(s) in its policy configmap is refreshed.
+func (h *resourceOverrideEventHandler) enqueueNamespace(obj interface{}) {
+	metaObj, err := operatorruntime.GetMetaObject(obj)
+	if err != nil {
+		return
+	}
+
+	enqueueNamespace(h.queue, metaObj.GetNamespace())
+}
+
+func (h *resourceOverrideEventHandler) enqueueSiblings(obj interface{}, event string) {
+	if h.roLister == nil {
+		return
+	}
+
+	metaObj, err := operatorruntime.GetMetaObject(obj)
+	if err != nil {
+		return
+	}
+
+	count := enqueueResourceOverrides(h.roLister, h.queue, metaObj.GetNamespace())
+	klog.V(4).Infof("[resourceoverride] key=%s/%s %s, enqueued %d ResourceOverride(s) in namespace", metaObj.GetNamespace(), metaObj.GetName(), event, count)
+}
@@ -0,0 +1,53 @@
+package resourceoverride
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+
+	autoscalingv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/autoscaling/v1"
+)
+
+func TestResourceOverrideEventHandlerOnUpdate(t *testing.T) {
+	ros := []*autoscalingv1.ResourceOverride{
+		{ObjectMeta: metav1.ObjectMeta{Name: "ro-1", Namespace: "test-ns", Generation: 1}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ro-2", Namespace: "test-ns", Generation: 1}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ro-3", Namespace: "test-ns", Generation: 1}},
+	}
+
+	tests := []struct {
+		name          string
+		newGeneration int64
+		wantEnqueued  int
+	}{
+		{
+			name:          "status update only enqueues the object",
+			newGeneration: 1,
+			wantEnqueued:  1,
+		},
+		{
+			name:          "spec update enqueues all ResourceOverrides in namespace",
+			newGeneration: 2,
+			wantEnqueued:  3,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			defer queue.ShutDown()
+
+			handler := newResourceOverrideEventHandler(queue)
+			handler.roLister = newTestROLister(ros...)
+
+			updated := ros[0].DeepCopy()
+			updated.Generation = test.newGeneration
+			handler.OnUpdate(ros[0], updated)
+
+			require.Equal(t, test.wantEnqueued, queue.Len())
+		})
+	}
+}
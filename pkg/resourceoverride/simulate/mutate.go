@@ -0,0 +1,289 @@
+package simulate
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	autoscalingv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/autoscaling/v1"
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+)
+
+// The ratio arithmetic below is a port of the admission webhook, it must be
+// kept in sync with it.
+
+const (
+	// cpuBaseScaleFactor converts bytes of memory into millicores, 1GiB is 1 core.
+	cpuBaseScaleFactor = 1000.0 / (1024.0 * 1024.0 * 1024.0)
+
+	// spcType is the SELinux type set on a pod by forceSelinuxRelabel.
+	spcType = "spc_t"
+)
+
+var (
+	defaultCPUFloor    = resource.MustParse("1m")
+	defaultMemoryFloor = resource.MustParse("1Mi")
+)
+
+type mutator struct {
+	config  *autoscalingv1.EffectivePodResourceOverride
+	sources map[autoscalingv1.EffectiveValueSource]string
+	limits  *limits
+	values  *tracker
+}
+
+func (m *mutator) mutate() {
+	for _, entry := range m.values.entries {
+		m.override(entry)
+	}
+}
+
+// override applies the configured ratios to a container in the same order as
+// the admission webhook does.
+func (m *mutator) override(e *entry) {
+	memoryLimit, memoryFound := e.container.Resources.Limits[corev1.ResourceMemory]
+
+	if percent := m.config.MemoryRequestToLimitPercent; memoryFound && percent.Value != 0 {
+		ratio := float64(percent.Value) / 100
+
+		// memory is measured in whole bytes, the request is rounded down to the
+		// nearest MiB (or MB) to improve ease of use for end users.
+		amount := memoryLimit.Value() * int64(ratio*100) / 100
+		var mod int64
+		switch memoryLimit.Format {
+		case resource.BinarySI:
+			mod = 1024 * 1024
+		default:
+			mod = 1000 * 1000
+		}
+
+		rule := fmt.Sprintf("%s of limits.memory %s", m.describe("memoryRequestToLimitPercent", percent), memoryLimit.String())
+		if rem := amount % mod; rem != 0 {
+			amount = amount - rem
+			rule += ", rounded down"
+		}
+
+		q := resource.NewQuantity(amount, memoryLimit.Format)
+		q, rule = m.limits.raiseToMemoryFloor(q, rule)
+		e.setRequest(corev1.ResourceMemory, *q, rule)
+	}
+
+	if percent := m.config.LimitCPUToMemoryPercent; memoryFound && percent.Value != 0 {
+		ratio := float64(percent.Value) / 100
+
+		amount := float64(memoryLimit.Value()) * ratio * cpuBaseScaleFactor
+		q := resource.NewMilliQuantity(int64(amount), resource.DecimalSI)
+
+		rule := fmt.Sprintf("%s of limits.memory %s", m.describe("limitCPUToMemoryPercent", percent), memoryLimit.String())
+		q, rule = m.limits.raiseToCPUFloor(q, rule)
+		q, rule = m.limits.lowerToCPUCeiling(q, rule)
+		e.setLimit(corev1.ResourceCPU, *q, rule)
+	}
+
+	cpuLimit, cpuFound := e.container.Resources.Limits[corev1.ResourceCPU]
+	if percent := m.config.CPURequestToLimitPercent; cpuFound && percent.Value != 0 {
+		ratio := float64(percent.Value) / 100
+
+		amount := float64(cpuLimit.MilliValue()) * ratio
+		q := resource.NewMilliQuantity(int64(amount), cpuLimit.Format)
+
+		rule := fmt.Sprintf("%s of limits.cpu %s", m.describe("cpuRequestToLimitPercent", percent), cpuLimit.String())
+		q, rule = m.limits.raiseToCPUFloor(q, rule)
+		e.setRequest(corev1.ResourceCPU, *q, rule)
+	}
+
+	// cpuRequestToRequestPercent is processed after all other overrides.
+	cpuRequest, cpuRequestFound := e.container.Resources.Requests[corev1.ResourceCPU]
+	if percent := m.config.CPURequestToRequestPercent; cpuRequestFound && percent.Value != 0 {
+		ratio := float64(percent.Value) / 100
+
+		amount := float64(cpuRequest.MilliValue()) * ratio
+		q := resource.NewMilliQuantity(int64(amount), cpuRequest.Format)
+
+		rule := fmt.Sprintf("%s of requests.cpu %s", m.describe("cpuRequestToRequestPercent", percent), cpuRequest.String())
+		q, rule = m.limits.raiseToCPUFloor(q, rule)
+		e.setRequest(corev1.ResourceCPU, *q, rule)
+	}
+}
+
+func (m *mutator) describe(name string, value autoscalingv1.EffectiveInt64Value) string {
+	return fmt.Sprintf("%s=%d from %s", name, value.Value, m.sources[value.Source])
+}
+
+// newSources returns the description of each object an effective value may be
+// taken from.
+func newSources(cro *operatorv1.ClusterResourceOverride, ro *autoscalingv1.ResourceOverride) map[autoscalingv1.EffectiveValueSource]string {
+	sources := map[autoscalingv1.EffectiveValueSource]string{
+		autoscalingv1.SourceClusterResourceOverride: fmt.Sprintf("ClusterResourceOverride %q", cro.Name),
+	}
+
+	if ro != nil {
+		sources[autoscalingv1.SourceResourceOverride] = fmt.Sprintf("ResourceOverride %q", ro.Name)
+	}
+
+	return sources
+}
+
+// limits holds the bounds a LimitRange puts on the values computed from the
+// configured ratios.
+type limits struct {
+	cpuFloor          resource.Quantity
+	cpuFloorSource    string
+	memoryFloor       resource.Quantity
+	memoryFloorSource string
+	cpuCeiling        *resource.Quantity
+	cpuCeilingSource  string
+}
+
+// newLimits returns the floors and ceiling of the given LimitRange(s). A floor
+// is the largest container minimum, and never lower than the default floor.
+// The ceiling is the smallest container maximum.
+func newLimits(limitRanges []*corev1.LimitRange) *limits {
+	l := &limits{
+		cpuFloor:          defaultCPUFloor,
+		cpuFloorSource:    "the minimum",
+		memoryFloor:       defaultMemoryFloor,
+		memoryFloorSource: "the minimum",
+	}
+
+	for _, limitRange := range limitRanges {
+		for _, item := range limitRange.Spec.Limits {
+			if item.Type != corev1.LimitTypeContainer {
+				continue
+			}
+
+			source := fmt.Sprintf("the LimitRange %q", limitRange.Name)
+			if min, ok := item.Min[corev1.ResourceCPU]; ok && min.Cmp(l.cpuFloor) > 0 {
+				l.cpuFloor, l.cpuFloorSource = min, source+" minimum"
+			}
+			if min, ok := item.Min[corev1.ResourceMemory]; ok && min.Cmp(l.memoryFloor) > 0 {
+				l.memoryFloor, l.memoryFloorSource = min, source+" minimum"
+			}
+			if max, ok := item.Max[corev1.ResourceCPU]; ok && (l.cpuCeiling == nil || max.Cmp(*l.cpuCeiling) < 0) {
+				ceiling := max.DeepCopy()
+				l.cpuCeiling, l.cpuCeilingSource = &ceiling, source+" maximum"
+			}
+		}
+	}
+
+	return l
+}
+
+func (l *limits) raiseToCPUFloor(q *resource.Quantity, rule string) (*resource.Quantity, string) {
+	if l.cpuFloor.Cmp(*q) <= 0 {
+		return q, rule
+	}
+
+	floor := l.cpuFloor.DeepCopy()
+	return &floor, fmt.Sprintf("%s, raised to %s %s", rule, l.cpuFloorSource, floor.String())
+}
+
+func (l *limits) raiseToMemoryFloor(q *resource.Quantity, rule string) (*resource.Quantity, string) {
+	if l.memoryFloor.Cmp(*q) <= 0 {
+		return q, rule
+	}
+
+	floor := l.memoryFloor.DeepCopy()
+	return &floor, fmt.Sprintf("%s, raised to %s %s", rule, l.memoryFloorSource, floor.String())
+}
+
+func (l *limits) lowerToCPUCeiling(q *resource.Quantity, rule string) (*resource.Quantity, string) {
+	if l.cpuCeiling == nil || l.cpuCeiling.Cmp(*q) >= 0 {
+		return q, rule
+	}
+
+	ceiling := l.cpuCeiling.DeepCopy()
+	return &ceiling, fmt.Sprintf("%s, lowered to %s %s", rule, l.cpuCeilingSource, ceiling.String())
+}
+
+// applyPodDefaults defaults the request of each resource that has a limit but
+// no request to the limit, as the API server does when a pod is created.
+func applyPodDefaults(values *tracker) {
+	for _, e := range values.entries {
+		for name, limit := range e.container.Resources.Limits {
+			if _, ok := e.container.Resources.Requests[name]; ok {
+				continue
+			}
+			e.setRequest(name, limit.DeepCopy(), fmt.Sprintf("defaulted to limits.%s by the API server", name))
+		}
+	}
+}
+
+// applyLimitRangeDefaults sets the default limits and requests of the given
+// LimitRange(s) on the containers, as the LimitRanger admission plugin does.
+func applyLimitRangeDefaults(limitRanges []*corev1.LimitRange, values *tracker) {
+	for _, limitRange := range limitRanges {
+		for _, item := range limitRange.Spec.Limits {
+			if item.Type != corev1.LimitTypeContainer {
+				continue
+			}
+
+			// a LimitRange defaults the default limit to the max, and the
+			// default request to the default limit, then to the min.
+			defaults := item.Default.DeepCopy()
+			if defaults == nil {
+				defaults = corev1.ResourceList{}
+			}
+			for name, max := range item.Max {
+				if _, ok := defaults[name]; !ok {
+					defaults[name] = max.DeepCopy()
+				}
+			}
+
+			defaultRequests := item.DefaultRequest.DeepCopy()
+			if defaultRequests == nil {
+				defaultRequests = corev1.ResourceList{}
+			}
+			for _, from := range []corev1.ResourceList{defaults, item.Min} {
+				for name, value := range from {
+					if _, ok := defaultRequests[name]; !ok {
+						defaultRequests[name] = value.DeepCopy()
+					}
+				}
+			}
+
+			for _, e := range values.entries {
+				for name, value := range defaultRequests {
+					if _, ok := e.container.Resources.Requests[name]; !ok {
+						e.setRequest(name, value.DeepCopy(), fmt.Sprintf("default request of the LimitRange %q", limitRange.Name))
+					}
+				}
+				for name, value := range defaults {
+					if _, ok := e.container.Resources.Limits[name]; !ok {
+						e.setLimit(name, value.DeepCopy(), fmt.Sprintf("default limit of the LimitRange %q", limitRange.Name))
+					}
+				}
+			}
+		}
+	}
+}
+
+// forceSelinuxRelabel sets the spc_t SELinux type on a pod that mounts a
+// persistent volume claim, and returns a note describing what it did.
+func forceSelinuxRelabel(pod *corev1.Pod, force bool) string {
+	if !force {
+		return ""
+	}
+
+	hasPVC := false
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			hasPVC = true
+			break
+		}
+	}
+	if !hasPVC {
+		return "forceSelinuxRelabel is set, but the pod has no persistentVolumeClaim volume"
+	}
+
+	if pod.Spec.SecurityContext == nil {
+		pod.Spec.SecurityContext = &corev1.PodSecurityContext{}
+	}
+	if pod.Spec.SecurityContext.SELinuxOptions == nil {
+		pod.Spec.SecurityContext.SELinuxOptions = &corev1.SELinuxOptions{}
+	}
+	pod.Spec.SecurityContext.SELinuxOptions.Type = spcType
+
+	return fmt.Sprintf("forceSelinuxRelabel is set and the pod has a persistentVolumeClaim volume, securityContext.seLinuxOptions.type is set to %s", spcType)
+}
@@ -0,0 +1,162 @@
+package simulate
+
+import (
+	"encoding/json"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	autoscalingv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/autoscaling/v1"
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/manifest"
+)
+
+// Read parses the given multi-document YAML stream and adds the object(s) it
+// holds to the input. A List is expanded into its items. A workload is
+// converted into the pod its pod template would create.
+func (in *Input) Read(data []byte) error {
+	for _, document := range manifest.Split(data) {
+		if err := in.read(document); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (in *Input) read(document []byte) error {
+	object := struct {
+		Kind  string            `json:"kind"`
+		Items []json.RawMessage `json:"items"`
+	}{}
+	if err := yaml.Unmarshal(document, &object); err != nil {
+		return fmt.Errorf("failed to parse manifest - %s", err.Error())
+	}
+
+	switch object.Kind {
+	case "List":
+		for _, item := range object.Items {
+			if err := in.read(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "ClusterResourceOverride":
+		if in.ClusterResourceOverride != nil {
+			return fmt.Errorf("more than one %s given", object.Kind)
+		}
+		in.ClusterResourceOverride = &operatorv1.ClusterResourceOverride{}
+		return unmarshal(document, in.ClusterResourceOverride)
+	case autoscalingv1.ResourceOverrideKind:
+		ro := &autoscalingv1.ResourceOverride{}
+		in.ResourceOverrides = append(in.ResourceOverrides, ro)
+		return unmarshal(document, ro)
+	case "LimitRange":
+		limitRange := &corev1.LimitRange{}
+		in.LimitRanges = append(in.LimitRanges, limitRange)
+		return unmarshal(document, limitRange)
+	case "Namespace":
+		ns := &corev1.Namespace{}
+		in.Namespaces = append(in.Namespaces, ns)
+		return unmarshal(document, ns)
+	}
+
+	if in.Pod != nil {
+		return fmt.Errorf("more than one pod or workload given, found another %s", object.Kind)
+	}
+
+	pod, err := toPod(object.Kind, document)
+	if err != nil {
+		return err
+	}
+
+	in.Pod = pod
+	return nil
+}
+
+// toPod returns the pod described by the given Pod or workload manifest.
+func toPod(kind string, document []byte) (*corev1.Pod, error) {
+	var (
+		meta     metav1.ObjectMeta
+		template *corev1.PodTemplateSpec
+	)
+
+	switch kind {
+	case "Pod":
+		pod := &corev1.Pod{}
+		if err := unmarshal(document, pod); err != nil {
+			return nil, err
+		}
+		return pod, nil
+	case "Deployment":
+		workload := &appsv1.Deployment{}
+		if err := unmarshal(document, workload); err != nil {
+			return nil, err
+		}
+		meta, template = workload.ObjectMeta, &workload.Spec.Template
+	case "StatefulSet":
+		workload := &appsv1.StatefulSet{}
+		if err := unmarshal(document, workload); err != nil {
+			return nil, err
+		}
+		meta, template = workload.ObjectMeta, &workload.Spec.Template
+	case "DaemonSet":
+		workload := &appsv1.DaemonSet{}
+		if err := unmarshal(document, workload); err != nil {
+			return nil, err
+		}
+		meta, template = workload.ObjectMeta, &workload.Spec.Template
+	case "ReplicaSet":
+		workload := &appsv1.ReplicaSet{}
+		if err := unmarshal(document, workload); err != nil {
+			return nil, err
+		}
+		meta, template = workload.ObjectMeta, &workload.Spec.Template
+	case "ReplicationController":
+		workload := &corev1.ReplicationController{}
+		if err := unmarshal(document, workload); err != nil {
+			return nil, err
+		}
+		if workload.Spec.Template == nil {
+			return nil, fmt.Errorf("%s %q has no pod template", kind, workload.Name)
+		}
+		meta, template = workload.ObjectMeta, workload.Spec.Template
+	case "Job":
+		workload := &batchv1.Job{}
+		if err := unmarshal(document, workload); err != nil {
+			return nil, err
+		}
+		meta, template = workload.ObjectMeta, &workload.Spec.Template
+	case "CronJob":
+		workload := &batchv1.CronJob{}
+		if err := unmarshal(document, workload); err != nil {
+			return nil, err
+		}
+		meta, template = workload.ObjectMeta, &workload.Spec.JobTemplate.Spec.Template
+	default:
+		return nil, fmt.Errorf("unexpected kind %q, expected a Pod, a workload, a ClusterResourceOverride, a ResourceOverride, a LimitRange or a Namespace", kind)
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: *template.ObjectMeta.DeepCopy(),
+		Spec:       *template.Spec.DeepCopy(),
+	}
+	pod.Namespace = meta.Namespace
+	if pod.Name == "" {
+		pod.Name = meta.Name
+	}
+
+	return pod, nil
+}
+
+func unmarshal(document []byte, object interface{}) error {
+	if err := yaml.Unmarshal(document, object); err != nil {
+		return fmt.Errorf("failed to parse %T - %s", object, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package simulate
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// resources are the container resource values a simulation explains, in the
+// order they are reported.
+var resources = []struct {
+	name     string
+	request  bool
+	resource corev1.ResourceName
+}{
+	{name: "limits.cpu", resource: corev1.ResourceCPU},
+	{name: "limits.memory", resource: corev1.ResourceMemory},
+	{name: "requests.cpu", request: true, resource: corev1.ResourceCPU},
+	{name: "requests.memory", request: true, resource: corev1.ResourceMemory},
+}
+
+// tracker records the rule that last set each resource value of the
+// containers of a pod.
+type tracker struct {
+	entries []*entry
+}
+
+type entry struct {
+	container *corev1.Container
+	init      bool
+	original  corev1.ResourceRequirements
+	rules     map[string]string
+}
+
+func newTracker(pod *corev1.Pod) *tracker {
+	t := &tracker{}
+	add := func(container *corev1.Container, init bool) {
+		e := &entry{
+			container: container,
+			init:      init,
+			original:  *container.Resources.DeepCopy(),
+			rules:     map[string]string{},
+		}
+		for name := range container.Resources.Limits {
+			e.rules["limits."+string(name)] = "set in the manifest"
+		}
+		for name := range container.Resources.Requests {
+			e.rules["requests."+string(name)] = "set in the manifest"
+		}
+		t.entries = append(t.entries, e)
+	}
+
+	for i := range pod.Spec.InitContainers {
+		add(&pod.Spec.InitContainers[i], true)
+	}
+	for i := range pod.Spec.Containers {
+		add(&pod.Spec.Containers[i], false)
+	}
+
+	return t
+}
+
+func (e *entry) setLimit(name corev1.ResourceName, q resource.Quantity, rule string) {
+	if e.container.Resources.Limits == nil {
+		e.container.Resources.Limits = corev1.ResourceList{}
+	}
+	e.container.Resources.Limits[name] = q
+	e.rules["limits."+string(name)] = rule
+}
+
+func (e *entry) setRequest(name corev1.ResourceName, q resource.Quantity, rule string) {
+	if e.container.Resources.Requests == nil {
+		e.container.Resources.Requests = corev1.ResourceList{}
+	}
+	e.container.Resources.Requests[name] = q
+	e.rules["requests."+string(name)] = rule
+}
+
+func (t *tracker) containers() []Container {
+	containers := make([]Container, 0, len(t.entries))
+	for _, e := range t.entries {
+		container := Container{
+			Name: e.container.Name,
+			Init: e.init,
+		}
+
+		for _, r := range resources {
+			original, current := e.original.Limits, e.container.Resources.Limits
+			if r.request {
+				original, current = e.original.Requests, e.container.Resources.Requests
+			}
+
+			value := Value{
+				Resource: r.name,
+				Original: quantityString(original, r.resource),
+				Value:    quantityString(current, r.resource),
+				Rule:     e.rules[r.name],
+			}
+			if value.Value == "" {
+				continue
+			}
+			container.Values = append(container.Values, value)
+		}
+
+		containers = append(containers, container)
+	}
+
+	return containers
+}
+
+func quantityString(list corev1.ResourceList, name corev1.ResourceName) string {
+	q, ok := list[name]
+	if !ok {
+		return ""
+	}
+
+	return q.String()
+}
+
+// Write writes a human-readable report of the result to w.
+func (r *Result) Write(w io.Writer) error {
+	fmt.Fprintf(w, "Pod: %s/%s\n", r.Pod.Namespace, r.Pod.Name)
+
+	switch {
+	case r.Effective == nil:
+		fmt.Fprintln(w, "Not overridden")
+	case r.ResourceOverride == nil:
+		fmt.Fprintln(w, "ResourceOverride: none, the ClusterResourceOverride applies")
+	default:
+		fmt.Fprintf(w, "ResourceOverride: %s\n", r.ResourceOverride.Name)
+	}
+
+	for _, note := range r.Notes {
+		fmt.Fprintf(w, "Note: %s\n", note)
+	}
+	fmt.Fprintln(w)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "CONTAINER\tRESOURCE\tORIGINAL\tRESULT\tRULE")
+	for _, container := range r.Containers {
+		name := container.Name
+		if container.Init {
+			name += " (init)"
+		}
+
+		for _, value := range container.Values {
+			original := value.Original
+			if original == "" {
+				original = "-"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", name, value.Resource, original, value.Value, value.Rule)
+		}
+	}
+
+	return tw.Flush()
+}
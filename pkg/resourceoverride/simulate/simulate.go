@@ -0,0 +1,197 @@
+package simulate
+
+import (
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	autoscalingv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/autoscaling/v1"
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/resourceoverride/internal/reconciler"
+)
+
+// Input holds the objects a simulation is run against.
+type Input struct {
+	// Pod is the pod being admitted. A workload is given as the pod its pod
+	// template would create.
+	Pod *corev1.Pod
+
+	ClusterResourceOverride *operatorv1.ClusterResourceOverride
+	ResourceOverrides       []*autoscalingv1.ResourceOverride
+	LimitRanges             []*corev1.LimitRange
+
+	// Namespaces is optional. If the namespace of the pod is among them, the
+	// pod is only overridden if the namespace is opted in.
+	Namespaces []*corev1.Namespace
+}
+
+// Result is the outcome of a simulation.
+type Result struct {
+	// Pod is the pod as admitted.
+	Pod *corev1.Pod
+
+	// ResourceOverride is the ResourceOverride applied to the pod, nil if the
+	// ClusterResourceOverride alone applies.
+	ResourceOverride *autoscalingv1.ResourceOverride
+
+	// Effective is the configuration applied to the pod, nil if the pod is
+	// not overridden.
+	Effective *autoscalingv1.EffectivePodResourceOverride
+
+	Containers []Container
+
+	// Notes explain decisions that apply to the pod as a whole.
+	Notes []string
+}
+
+// Container describes how the resources of a container were set.
+type Container struct {
+	Name   string
+	Init   bool
+	Values []Value
+}
+
+// Value is a resource request or limit of a container.
+type Value struct {
+	// Resource is the name of the value, for example requests.cpu.
+	Resource string
+
+	// Original is the value in the given manifest, empty if unset.
+	Original string
+
+	// Value is the value the pod is admitted with, empty if unset.
+	Value string
+
+	// Rule explains what produced the value.
+	Rule string
+}
+
+// Simulate returns the pod in the input as the admission webhook would admit
+// it, along with an explanation of each container resource value.
+func Simulate(in *Input) (*Result, error) {
+	if in.Pod == nil {
+		return nil, errors.New("no pod or workload given")
+	}
+	if in.ClusterResourceOverride == nil {
+		return nil, errors.New("no ClusterResourceOverride given")
+	}
+
+	cluster := &in.ClusterResourceOverride.Spec.PodResourceOverride.Spec
+	if err := cluster.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid ClusterResourceOverride - %s", err.Error())
+	}
+
+	pod := in.Pod.DeepCopy()
+	if pod.Namespace == "" {
+		pod.Namespace = metav1.NamespaceDefault
+	}
+
+	result := &Result{
+		Pod: pod,
+	}
+
+	values := newTracker(pod)
+
+	// the API server defaults the pod and runs the LimitRanger admission
+	// plugin before the mutating admission webhook is called.
+	applyPodDefaults(values)
+
+	limitRanges := inNamespace(in.LimitRanges, pod.Namespace)
+	applyLimitRangeDefaults(limitRanges, values)
+
+	if ns := findNamespace(in.Namespaces, pod.Namespace); ns != nil && ns.Labels[asset.NamespaceOptInLabelKey] != "true" {
+		result.Notes = append(result.Notes, fmt.Sprintf("namespace %q does not have the %s=true label, the pod is not overridden", pod.Namespace, asset.NamespaceOptInLabelKey))
+		result.Containers = values.containers()
+		return result, nil
+	}
+
+	ro, notes := selectResourceOverride(in, pod)
+	result.Notes = append(result.Notes, notes...)
+	result.ResourceOverride = ro
+
+	spec := &autoscalingv1.PodResourceOverrideSpec{}
+	if ro != nil {
+		spec = &ro.Spec.PodResourceOverride
+	}
+
+	result.Effective = reconciler.Effective(spec, cluster)
+
+	m := &mutator{
+		config:  result.Effective,
+		sources: newSources(in.ClusterResourceOverride, ro),
+		limits:  newLimits(limitRanges),
+		values:  values,
+	}
+	m.mutate()
+
+	if note := forceSelinuxRelabel(pod, result.Effective.ForceSelinuxRelabel.Value); note != "" {
+		result.Notes = append(result.Notes, note)
+	}
+
+	result.Containers = values.containers()
+	return result, nil
+}
+
+// selectResourceOverride returns the ResourceOverride applied to the pod, nil
+// if none applies, along with notes on the ResourceOverride(s) passed over.
+func selectResourceOverride(in *Input, pod *corev1.Pod) (selected *autoscalingv1.ResourceOverride, notes []string) {
+	for _, ro := range inNamespace(in.ResourceOverrides, pod.Namespace) {
+		if ro.DeletionTimestamp != nil {
+			continue
+		}
+
+		matcher, err := reconciler.NewMatcher(&ro.Spec)
+		if err == nil {
+			err = ro.Spec.PodResourceOverride.Validate()
+		}
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("ResourceOverride %q is ignored, it is invalid - %s", ro.Name, err.Error()))
+			continue
+		}
+
+		if !matcher.Matches(pod) {
+			notes = append(notes, fmt.Sprintf("ResourceOverride %q does not select the pod", ro.Name))
+			continue
+		}
+
+		// the admission webhook applies one of them, which one is not
+		// defined. The first one by name is shown.
+		switch {
+		case selected == nil:
+			selected = ro
+		case ro.Name < selected.Name:
+			notes = append(notes, fmt.Sprintf("ResourceOverride %q also selects the pod, the admission webhook may apply it instead of %q", selected.Name, ro.Name))
+			selected = ro
+		default:
+			notes = append(notes, fmt.Sprintf("ResourceOverride %q also selects the pod, the admission webhook may apply it instead of %q", ro.Name, selected.Name))
+		}
+	}
+
+	return
+}
+
+func findNamespace(namespaces []*corev1.Namespace, name string) *corev1.Namespace {
+	for _, ns := range namespaces {
+		if ns.Name == name {
+			return ns
+		}
+	}
+
+	return nil
+}
+
+// inNamespace returns the object(s) in the given namespace. An object without
+// a namespace is taken to be in the namespace of the pod.
+func inNamespace[T metav1.Object](objects []T, namespace string) []T {
+	filtered := make([]T, 0, len(objects))
+	for _, object := range objects {
+		if object.GetNamespace() == "" || object.GetNamespace() == namespace {
+			filtered = append(filtered, object)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,442 @@
+package simulate
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	autoscalingv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/autoscaling/v1"
+	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+)
+
+// The cases below mirror those of test/e2e.
+
+var defaultCluster = operatorv1.PodResourceOverrideSpec{
+	LimitCPUToMemoryPercent:     200,
+	CPURequestToLimitPercent:    25,
+	MemoryRequestToLimitPercent: 50,
+}
+
+func TestSimulate(t *testing.T) {
+	tests := []struct {
+		name              string
+		cluster           operatorv1.PodResourceOverrideSpec
+		resourceOverrides []*autoscalingv1.ResourceOverride
+		limitRanges       []*corev1.LimitRange
+		namespaces        []*corev1.Namespace
+		pod               *corev1.Pod
+		wantRO            string
+		want              map[string]corev1.ResourceRequirements
+	}{
+		{
+			name:    "WithMultipleContainers",
+			cluster: defaultCluster,
+			pod: newPod(nil,
+				newContainer("db", requirements(nil, list("1024Mi", "1000m"))),
+				newContainer("app", requirements(nil, list("512Mi", "500m"))),
+			),
+			want: map[string]corev1.ResourceRequirements{
+				"db":  requirements(list("512Mi", "500m"), list("1024Mi", "2000m")),
+				"app": requirements(list("256Mi", "250m"), list("512Mi", "1000m")),
+			},
+		},
+		{
+			name:    "WithInitContainer",
+			cluster: defaultCluster,
+			pod: func() *corev1.Pod {
+				pod := newPod(nil, newContainer("app", requirements(nil, list("512Mi", "500m"))))
+				pod.Spec.InitContainers = []corev1.Container{newContainer("init", requirements(nil, list("1024Mi", "1000m")))}
+				return pod
+			}(),
+			want: map[string]corev1.ResourceRequirements{
+				"init": requirements(list("512Mi", "500m"), list("1024Mi", "2000m")),
+				"app":  requirements(list("256Mi", "250m"), list("512Mi", "1000m")),
+			},
+		},
+		{
+			name:    "WithLimitRangeWithDefaultLimitForCPUAndMemory",
+			cluster: defaultCluster,
+			limitRanges: []*corev1.LimitRange{
+				newLimitRange(corev1.LimitRangeItem{Type: corev1.LimitTypeContainer, Default: list("512Mi", "2000m")}),
+			},
+			pod: newPod(nil, newContainer("app", corev1.ResourceRequirements{})),
+			want: map[string]corev1.ResourceRequirements{
+				"app": requirements(list("256Mi", "250m"), list("512Mi", "1000m")),
+			},
+		},
+		{
+			name:    "WithLimitRangeWithMaximumForCPU",
+			cluster: defaultCluster,
+			limitRanges: []*corev1.LimitRange{
+				newLimitRange(corev1.LimitRangeItem{Type: corev1.LimitTypeContainer, Max: list("1024Mi", "1000m")}),
+			},
+			pod: newPod(nil, newContainer("app", requirements(nil, list("1024Mi", "1000m")))),
+			want: map[string]corev1.ResourceRequirements{
+				"app": requirements(list("512Mi", "250m"), list("1024Mi", "1000m")),
+			},
+		},
+		{
+			name:    "WithLimitRangeWithMinimumForCPU",
+			cluster: defaultCluster,
+			limitRanges: []*corev1.LimitRange{
+				newLimitRange(corev1.LimitRangeItem{Type: corev1.LimitTypeContainer, Min: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("300m")}}),
+			},
+			pod: newPod(nil, newContainer("app", requirements(nil, list("512Mi", "1000m")))),
+			want: map[string]corev1.ResourceRequirements{
+				"app": requirements(list("256Mi", "300m"), list("512Mi", "1000m")),
+			},
+		},
+		{
+			name:    "WithCPURequestToRequestPercent",
+			cluster: operatorv1.PodResourceOverrideSpec{CPURequestToRequestPercent: 50, MemoryRequestToLimitPercent: 50},
+			pod: newPod(nil, newContainer("app", corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+			})),
+			want: map[string]corev1.ResourceRequirements{
+				"app": {
+					Requests: list("256Mi", "100m"),
+					Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+				},
+			},
+		},
+		{
+			name:       "WithNoOptIn",
+			cluster:    defaultCluster,
+			namespaces: []*corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "test"}}},
+			pod:        newPod(nil, newContainer("app", requirements(nil, list("1024Mi", "1000m")))),
+			want: map[string]corev1.ResourceRequirements{
+				"app": requirements(list("1024Mi", "1000m"), list("1024Mi", "1000m")),
+			},
+		},
+		{
+			name:    "WithOptIn",
+			cluster: defaultCluster,
+			namespaces: []*corev1.Namespace{
+				{ObjectMeta: metav1.ObjectMeta{Name: "test", Labels: map[string]string{asset.NamespaceOptInLabelKey: "true"}}},
+			},
+			pod: newPod(nil, newContainer("app", requirements(nil, list("1024Mi", "1000m")))),
+			want: map[string]corev1.ResourceRequirements{
+				"app": requirements(list("512Mi", "500m"), list("1024Mi", "2000m")),
+			},
+		},
+		{
+			name:    "ResourceOverrideOverridesPod",
+			cluster: defaultCluster,
+			resourceOverrides: []*autoscalingv1.ResourceOverride{
+				newResourceOverride("test-ro", nil, autoscalingv1.PodResourceOverrideSpec{
+					LimitCPUToMemoryPercent:     100,
+					CPURequestToLimitPercent:    50,
+					MemoryRequestToLimitPercent: 75,
+				}),
+			},
+			pod:    newPod(nil, newContainer("test", requirements(nil, list("1024Mi", "1000m")))),
+			wantRO: "test-ro",
+			want: map[string]corev1.ResourceRequirements{
+				"test": requirements(list("768Mi", "500m"), list("1024Mi", "1000m")),
+			},
+		},
+		{
+			name:    "ResourceOverrideWithMatchingPodSelector",
+			cluster: defaultCluster,
+			resourceOverrides: []*autoscalingv1.ResourceOverride{
+				newResourceOverride("test-ro-selector", map[string]string{"override": "custom"}, autoscalingv1.PodResourceOverrideSpec{
+					CPURequestToLimitPercent:    75,
+					MemoryRequestToLimitPercent: 90,
+				}),
+			},
+			pod:    newPod(map[string]string{"override": "custom"}, newContainer("test", requirements(nil, list("512Mi", "1000m")))),
+			wantRO: "test-ro-selector",
+			want: map[string]corev1.ResourceRequirements{
+				"test": requirements(list("460Mi", "750m"), list("512Mi", "1000m")),
+			},
+		},
+		{
+			name:    "ResourceOverrideWithPodSelectorNotMatching",
+			cluster: defaultCluster,
+			resourceOverrides: []*autoscalingv1.ResourceOverride{
+				newResourceOverride("test-ro-selector", map[string]string{"override": "custom"}, autoscalingv1.PodResourceOverrideSpec{
+					CPURequestToLimitPercent:    75,
+					MemoryRequestToLimitPercent: 90,
+				}),
+			},
+			pod: newPod(nil, newContainer("test", requirements(nil, list("512Mi", "1000m")))),
+			want: map[string]corev1.ResourceRequirements{
+				"test": requirements(list("256Mi", "250m"), list("512Mi", "1000m")),
+			},
+		},
+		{
+			name:    "ResourceOverrideWithCPURequestToRequestPercent",
+			cluster: operatorv1.PodResourceOverrideSpec{MemoryRequestToLimitPercent: 50},
+			resourceOverrides: []*autoscalingv1.ResourceOverride{
+				newResourceOverride("test-ro-cpurequest", nil, autoscalingv1.PodResourceOverrideSpec{
+					CPURequestToRequestPercent:  50,
+					MemoryRequestToLimitPercent: 50,
+				}),
+			},
+			pod: newPod(nil, newContainer("app", corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+			})),
+			wantRO: "test-ro-cpurequest",
+			want: map[string]corev1.ResourceRequirements{
+				"app": {
+					Requests: list("256Mi", "100m"),
+					Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+				},
+			},
+		},
+		{
+			name:    "ResourceOverrideFallsBackToClusterPerField",
+			cluster: defaultCluster,
+			resourceOverrides: []*autoscalingv1.ResourceOverride{
+				newResourceOverride("test-ro", nil, autoscalingv1.PodResourceOverrideSpec{
+					MemoryRequestToLimitPercent: 75,
+				}),
+			},
+			pod:    newPod(nil, newContainer("test", requirements(nil, list("1024Mi", "1000m")))),
+			wantRO: "test-ro",
+			want: map[string]corev1.ResourceRequirements{
+				"test": requirements(list("768Mi", "500m"), list("1024Mi", "2000m")),
+			},
+		},
+		{
+			name:    "OverlappingResourceOverridesFirstByNameApplies",
+			cluster: defaultCluster,
+			resourceOverrides: []*autoscalingv1.ResourceOverride{
+				newResourceOverride("a", nil, autoscalingv1.PodResourceOverrideSpec{MemoryRequestToLimitPercent: 75}),
+				newResourceOverride("b", nil, autoscalingv1.PodResourceOverrideSpec{MemoryRequestToLimitPercent: 25}),
+			},
+			pod:    newPod(nil, newContainer("test", requirements(nil, list("1024Mi", "1000m")))),
+			wantRO: "a",
+			want: map[string]corev1.ResourceRequirements{
+				"test": requirements(list("768Mi", "500m"), list("1024Mi", "2000m")),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			input := &Input{
+				Pod: test.pod,
+				ClusterResourceOverride: &operatorv1.ClusterResourceOverride{
+					ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+					Spec: operatorv1.ClusterResourceOverrideSpec{
+						PodResourceOverride: operatorv1.PodResourceOverride{Spec: test.cluster},
+					},
+				},
+				ResourceOverrides: test.resourceOverrides,
+				LimitRanges:       test.limitRanges,
+				Namespaces:        test.namespaces,
+			}
+
+			result, err := Simulate(input)
+			require.NoError(t, err)
+
+			if test.wantRO == "" {
+				require.Nil(t, result.ResourceOverride)
+			} else {
+				require.NotNil(t, result.ResourceOverride)
+				require.Equal(t, test.wantRO, result.ResourceOverride.Name)
+			}
+
+			containers := append(append([]corev1.Container{}, result.Pod.Spec.InitContainers...), result.Pod.Spec.Containers...)
+			require.Len(t, containers, len(test.want))
+			for _, container := range containers {
+				want, ok := test.want[container.Name]
+				require.True(t, ok, "unexpected container %s", container.Name)
+				requireResourceList(t, want.Limits, container.Resources.Limits)
+				requireResourceList(t, want.Requests, container.Resources.Requests)
+			}
+
+			// the input is left untouched.
+			require.NotEqual(t, result.Pod, input.Pod)
+		})
+	}
+}
+
+func TestSimulateExplainsValues(t *testing.T) {
+	input := &Input{
+		Pod: newPod(nil, newContainer("app", requirements(nil, list("1024Mi", "1000m")))),
+		ClusterResourceOverride: &operatorv1.ClusterResourceOverride{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Spec: operatorv1.ClusterResourceOverrideSpec{
+				PodResourceOverride: operatorv1.PodResourceOverride{Spec: defaultCluster},
+			},
+		},
+		ResourceOverrides: []*autoscalingv1.ResourceOverride{
+			newResourceOverride("test-ro", nil, autoscalingv1.PodResourceOverrideSpec{MemoryRequestToLimitPercent: 75}),
+		},
+		LimitRanges: []*corev1.LimitRange{
+			newLimitRange(corev1.LimitRangeItem{Type: corev1.LimitTypeContainer, Max: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1500m")}}),
+		},
+	}
+
+	result, err := Simulate(input)
+	require.NoError(t, err)
+	require.Len(t, result.Containers, 1)
+
+	rules := map[string]Value{}
+	for _, value := range result.Containers[0].Values {
+		rules[value.Resource] = value
+	}
+
+	require.Equal(t, Value{Resource: "limits.memory", Original: "1Gi", Value: "1Gi", Rule: "set in the manifest"}, rules["limits.memory"])
+	require.Equal(t, `limitCPUToMemoryPercent=200 from ClusterResourceOverride "cluster" of limits.memory 1Gi, lowered to the LimitRange "limits" maximum 1500m`, rules["limits.cpu"].Rule)
+	require.Equal(t, "1500m", rules["limits.cpu"].Value)
+	require.Equal(t, `memoryRequestToLimitPercent=75 from ResourceOverride "test-ro" of limits.memory 1Gi`, rules["requests.memory"].Rule)
+	require.Equal(t, "", rules["requests.memory"].Original)
+	require.Equal(t, `cpuRequestToLimitPercent=25 from ClusterResourceOverride "cluster" of limits.cpu 1500m`, rules["requests.cpu"].Rule)
+
+	buffer := &bytes.Buffer{}
+	require.NoError(t, result.Write(buffer))
+	require.Contains(t, buffer.String(), "ResourceOverride: test-ro\n")
+	require.Contains(t, buffer.String(), "requests.cpu")
+}
+
+func TestSimulateForceSelinuxRelabel(t *testing.T) {
+	pod := newPod(nil, newContainer("app", corev1.ResourceRequirements{}))
+	pod.Spec.Volumes = []corev1.Volume{
+		{Name: "data", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}}},
+	}
+
+	result, err := Simulate(&Input{
+		Pod: pod,
+		ClusterResourceOverride: &operatorv1.ClusterResourceOverride{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Spec: operatorv1.ClusterResourceOverrideSpec{
+				PodResourceOverride: operatorv1.PodResourceOverride{Spec: operatorv1.PodResourceOverrideSpec{ForceSelinuxRelabel: true}},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, spcType, result.Pod.Spec.SecurityContext.SELinuxOptions.Type)
+	require.Len(t, result.Notes, 1)
+}
+
+func TestSimulateRequiresPodAndClusterResourceOverride(t *testing.T) {
+	_, err := Simulate(&Input{ClusterResourceOverride: &operatorv1.ClusterResourceOverride{}})
+	require.EqualError(t, err, "no pod or workload given")
+
+	_, err = Simulate(&Input{Pod: newPod(nil)})
+	require.EqualError(t, err, "no ClusterResourceOverride given")
+}
+
+func TestRead(t *testing.T) {
+	data := []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: test
+spec:
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: app
+        resources:
+          limits:
+            memory: 1Gi
+---
+apiVersion: operator.autoscaling.openshift.io/v1
+kind: ClusterResourceOverride
+metadata:
+  name: cluster
+spec:
+  podResourceOverride:
+    spec:
+      memoryRequestToLimitPercent: 50
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: autoscaling.openshift.io/v1
+  kind: ResourceOverride
+  metadata:
+    name: test-ro
+    namespace: test
+  spec:
+    podResourceOverride:
+      memoryRequestToLimitPercent: 75
+- apiVersion: v1
+  kind: LimitRange
+  metadata:
+    name: limits
+    namespace: test
+`)
+
+	input := &Input{}
+	require.NoError(t, input.Read(data))
+
+	require.NotNil(t, input.Pod)
+	require.Equal(t, "web", input.Pod.Name)
+	require.Equal(t, "test", input.Pod.Namespace)
+	require.Equal(t, map[string]string{"app": "web"}, input.Pod.Labels)
+	require.NotNil(t, input.ClusterResourceOverride)
+	require.Equal(t, int64(50), input.ClusterResourceOverride.Spec.PodResourceOverride.Spec.MemoryRequestToLimitPercent)
+	require.Len(t, input.ResourceOverrides, 1)
+	require.Len(t, input.LimitRanges, 1)
+
+	require.EqualError(t, input.Read([]byte("kind: Pod\n")), "more than one pod or workload given, found another Pod")
+	require.EqualError(t, (&Input{}).Read([]byte("kind: Service\n")), `unexpected kind "Service", expected a Pod, a workload, a ClusterResourceOverride, a ResourceOverride, a LimitRange or a Namespace`)
+}
+
+func requireResourceList(t *testing.T, want, got corev1.ResourceList) {
+	require.Len(t, got, len(want), "got %v, want %v", got, want)
+	for name, quantity := range want {
+		value, ok := got[name]
+		require.True(t, ok, "missing %s", name)
+		require.Zero(t, quantity.Cmp(value), "%s: got %s, want %s", name, value.String(), quantity.String())
+	}
+}
+
+func newPod(labels map[string]string, containers ...corev1.Container) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test", Labels: labels},
+		Spec:       corev1.PodSpec{Containers: containers},
+	}
+}
+
+func newContainer(name string, resources corev1.ResourceRequirements) corev1.Container {
+	return corev1.Container{Name: name, Image: "busybox", Resources: resources}
+}
+
+func newLimitRange(items ...corev1.LimitRangeItem) *corev1.LimitRange {
+	return &corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: "limits", Namespace: "test"},
+		Spec:       corev1.LimitRangeSpec{Limits: items},
+	}
+}
+
+func newResourceOverride(name string, matchLabels map[string]string, spec autoscalingv1.PodResourceOverrideSpec) *autoscalingv1.ResourceOverride {
+	ro := &autoscalingv1.ResourceOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+		Spec: autoscalingv1.ResourceOverrideSpec{
+			PodResourceOverride: spec,
+		},
+	}
+	if matchLabels != nil {
+		ro.Spec.PodSelector = &metav1.LabelSelector{MatchLabels: matchLabels}
+	}
+
+	return ro
+}
+
+func requirements(requests, limits corev1.ResourceList) corev1.ResourceRequirements {
+	return corev1.ResourceRequirements{Requests: requests, Limits: limits}
+}
+
+// list returns a ResourceList with the given memory and cpu.
+func list(memory, cpu string) corev1.ResourceList {
+	return corev1.ResourceList{
+		corev1.ResourceMemory: resource.MustParse(memory),
+		corev1.ResourceCPU:    resource.MustParse(cpu),
+	}
+}
@@ -8,6 +8,7 @@ import (
 	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/rest"
 	apiregistrationclientset "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset"
 )
@@ -19,6 +20,7 @@ type Client struct {
 	APIExtension    apiextensionsclientset.Interface
 	Dynamic         dynamicclient.Ensurer
 	RawDynamic      dynamic.Interface
+	Metadata        metadata.Interface
 }
 
 func NewClient(config *rest.Config) (clients *Client, err error) {
@@ -40,6 +42,12 @@ func NewClient(config *rest.Config) (clients *Client, err error) {
 		return
 	}
 
+	metadataclient, buildErr := metadata.NewForConfig(config)
+	if buildErr != nil {
+		err = fmt.Errorf("failed to construct metadata client - %s", buildErr.Error())
+		return
+	}
+
 	apiregistration, buildErr := apiregistrationclientset.NewForConfig(config)
 	if buildErr != nil {
 		err = fmt.Errorf("failed to construct apiregistration client - %s", buildErr.Error())
@@ -59,6 +67,7 @@ func NewClient(config *rest.Config) (clients *Client, err error) {
 		APIExtension:    apiextension,
 		Dynamic:         dynamicclient.NewEnsurer(rawDynamic),
 		RawDynamic:      rawDynamic,
+		Metadata:        metadataclient,
 	}
 
 	return
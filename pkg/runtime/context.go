@@ -14,6 +14,20 @@ func NewOperandContext(name, namespace, resource, image, version string) Operand
 	}
 }
 
+// NewHostedOperandContext returns the context of an operand that runs in the
+// given control plane namespace of a management cluster, and reaches the
+// hosted cluster it serves with the kubeconfig in the given Secret.
+func NewHostedOperandContext(name, namespace, resource, image, version, hostedKubeconfigSecret string) OperandContext {
+	return &context{
+		name:                   name,
+		namespace:              namespace,
+		resource:               resource,
+		image:                  image,
+		version:                version,
+		hostedKubeconfigSecret: hostedKubeconfigSecret,
+	}
+}
+
 type OperandContext interface {
 	// Name is the name of the ClusterResourceOverride admission webhook server.
 	// This name will be used to create kube resources.
@@ -32,14 +46,20 @@ type OperandContext interface {
 
 	// ResourceName is the name of the CustomResource that will manage this operand.
 	ResourceName() string
+
+	// HostedKubeconfigSecret is the name of the Secret in WebhookNamespace
+	// with the kubeconfig of the hosted cluster the operand serves. It is
+	// empty if the operand runs in the cluster it serves.
+	HostedKubeconfigSecret() string
 }
 
 type context struct {
-	name      string
-	namespace string
-	resource  string
-	image     string
-	version   string
+	name                   string
+	namespace              string
+	resource               string
+	image                  string
+	version                string
+	hostedKubeconfigSecret string
 }
 
 func (c *context) WebhookName() string {
@@ -61,3 +81,7 @@ func (c *context) OperandVersion() string {
 func (c *context) ResourceName() string {
 	return c.resource
 }
+
+func (c *context) HostedKubeconfigSecret() string {
+	return c.hostedKubeconfigSecret
+}
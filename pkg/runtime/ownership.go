@@ -40,6 +40,22 @@ func SetController(owned metav1.Object, owner Object) {
 	owned.SetOwnerReferences(refs)
 }
 
+// SetOwnerAnnotation returns a SetControllerFunc that records the name of the
+// owner in the given annotation. It is used for an object in another cluster
+// than its owner, where an owner reference would have the object garbage
+// collected.
+func SetOwnerAnnotation(key string) SetControllerFunc {
+	return func(owned metav1.Object, owner Object) {
+		annotations := owned.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+
+		annotations[key] = owner.GetName()
+		owned.SetAnnotations(annotations)
+	}
+}
+
 func IsOwner(owned metav1.Object, owner metav1.OwnerReference) bool {
 	for _, ref := range owned.GetOwnerReferences() {
 		if ref.Kind == owner.Kind {
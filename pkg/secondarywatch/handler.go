@@ -1,6 +1,8 @@
 package secondarywatch
 
 import (
+	"reflect"
+
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
@@ -99,10 +101,53 @@ func (d *directEnqueueHandler) OnUpdate(oldObj, newObj interface{}) {
 	if oldAcc.GetResourceVersion() == newAcc.GetResourceVersion() {
 		return
 	}
-	klog.V(4).Infof("[secondarywatch] cluster APIServer config changed, enqueueing primary CR %q", d.primaryCRName)
+	klog.V(4).Infof("[secondarywatch] %T/%s changed, enqueueing primary CR %q", newObj, newAcc.GetName(), d.primaryCRName)
 	if err := d.directEnqueuer.EnqueueByName(d.primaryCRName); err != nil {
 		klog.V(3).Infof("[secondarywatch] directEnqueue OnUpdate: failed to enqueue primary - %s", err.Error())
 	}
 }
 
 func (d *directEnqueueHandler) OnDelete(_ interface{}) {}
+
+// newNodeEventHandler enqueues the primary resource when a node is added or
+// removed, or when the labels of a node change. Only the metadata of the nodes
+// is watched, a cordon or a change of the taints is picked up by the next
+// reconcile.
+func newNodeEventHandler(de runtime.DirectEnqueuer, primaryCRName string) cache.ResourceEventHandler {
+	enqueue := func(reason string, obj interface{}) {
+		name := ""
+		if acc, err := meta.Accessor(obj); err == nil {
+			name = acc.GetName()
+		}
+
+		klog.V(4).Infof("[secondarywatch] node %s %s, enqueueing primary CR %q", name, reason, primaryCRName)
+		if err := de.EnqueueByName(primaryCRName); err != nil {
+			klog.V(3).Infof("[secondarywatch] node %s: failed to enqueue primary - %s", reason, err.Error())
+		}
+	}
+
+	return cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			if !isInInitialList {
+				enqueue("added", obj)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldNode, err := meta.Accessor(oldObj)
+			if err != nil {
+				return
+			}
+			newNode, err := meta.Accessor(newObj)
+			if err != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(oldNode.GetLabels(), newNode.GetLabels()) {
+				enqueue("labels changed", newObj)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			enqueue("removed", obj)
+		},
+	}
+}
@@ -4,6 +4,9 @@ import (
 	admissionregistrationv1 "k8s.io/client-go/listers/admissionregistration/v1"
 	listersappsv1 "k8s.io/client-go/listers/apps/v1"
 	listerscorev1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/metadata/metadatalister"
+	"k8s.io/client-go/tools/cache"
+	listersapiregistrationv1 "k8s.io/kube-aggregator/pkg/client/listers/apiregistration/v1"
 )
 
 // Lister is a set of Lister(s) for secondary resource(s)
@@ -18,6 +21,21 @@ type Lister struct {
 	webhook                admissionregistrationv1.MutatingWebhookConfigurationLister
 	admissionpolicy        admissionregistrationv1.ValidatingAdmissionPolicyLister
 	admissionpolicybinding admissionregistrationv1.ValidatingAdmissionPolicyBindingLister
+	apiservice             listersapiregistrationv1.APIServiceLister
+	node                   metadatalister.Lister
+	infrastructure         cache.GenericLister
+}
+
+// ConfigV1InfrastructureLister returns the lister of the Infrastructure
+// object, nil if config.openshift.io is not watched.
+func (l *Lister) ConfigV1InfrastructureLister() cache.GenericLister {
+	return l.infrastructure
+}
+
+// NodeMetadataLister returns the lister of the metadata of the nodes, nil if
+// the nodes are not watched.
+func (l *Lister) NodeMetadataLister() metadatalister.Lister {
+	return l.node
 }
 
 func (l *Lister) CoreV1ConfigMapLister() listerscorev1.ConfigMapLister {
@@ -51,3 +69,9 @@ func (l *Lister) AdmissionRegistrationV1ValidatingAdmissionPolicyLister() admiss
 func (l *Lister) AdmissionRegistrationV1ValidatingAdmissionPolicyBindingLister() admissionregistrationv1.ValidatingAdmissionPolicyBindingLister {
 	return l.admissionpolicybinding
 }
+
+// APIRegistrationV1APIServiceLister returns the lister of the APIService of
+// the admission webhook, the only one that is watched.
+func (l *Lister) APIRegistrationV1APIServiceLister() listersapiregistrationv1.APIServiceLister {
+	return l.apiservice
+}
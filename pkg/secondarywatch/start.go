@@ -4,13 +4,23 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/infrastructure"
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/runtime"
 	"github.com/openshift/cluster-resource-override-admission-operator/pkg/tlsprofile"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/informers"
+	"k8s.io/client-go/metadata/metadatainformer"
+	"k8s.io/client-go/metadata/metadatalister"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
+	apiregistrationinformers "k8s.io/kube-aggregator/pkg/client/informers/externalversions"
 )
 
 type Options struct {
@@ -18,6 +28,24 @@ type Options struct {
 	ResyncPeriod        time.Duration
 	Namespace           string
 	PrimaryResourceName string
+
+	// APIServiceName is the name of the APIService of the admission webhook,
+	// the only APIService that is watched.
+	APIServiceName string
+
+	// OpenShiftConfig watches the config.openshift.io APIServer and
+	// Infrastructure objects, the cluster serves them.
+	OpenShiftConfig bool
+
+	// Nodes watches the metadata of the nodes of the cluster, the replicas of
+	// the admission webhook follow the schedulable nodes. The nodes of a
+	// management cluster are not watched.
+	Nodes bool
+
+	// OperandClient watches the object(s) of the admission webhook in
+	// Namespace of the management cluster it runs in. If nil, they are
+	// watched with Client.
+	OperandClient *runtime.Client
 }
 
 // StarterFunc refers to a function that can be called to start watch on secondary resources.
@@ -31,22 +59,57 @@ func (s StarterFunc) Start(enqueuer runtime.Enqueuer, shutdown context.Context)
 // The function returns lister(s) that can be used to query secondary resources
 // and a StarterFunc that can be called to start the watch.
 func New(options *Options) (lister *Lister, startFunc StarterFunc) {
+	operandClient := options.OperandClient
+	if operandClient == nil {
+		operandClient = options.Client
+	}
+
+	// the object(s) of the admission webhook are watched in the cluster it
+	// runs in, the cluster-scoped ones in the cluster it serves.
 	option := informers.WithNamespace(options.Namespace)
+	operandFactory := informers.NewSharedInformerFactoryWithOptions(operandClient.Kubernetes, options.ResyncPeriod, option)
 	factory := informers.NewSharedInformerFactoryWithOptions(options.Client.Kubernetes, options.ResyncPeriod, option)
 
-	deployment := factory.Apps().V1().Deployments()
-	daemonset := factory.Apps().V1().DaemonSets()
-	pod := factory.Core().V1().Pods()
-	configmap := factory.Core().V1().ConfigMaps()
-	service := factory.Core().V1().Services()
-	secret := factory.Core().V1().Secrets()
-	serviceaccount := factory.Core().V1().ServiceAccounts()
+	deployment := operandFactory.Apps().V1().Deployments()
+	daemonset := operandFactory.Apps().V1().DaemonSets()
+	pod := operandFactory.Core().V1().Pods()
+	configmap := operandFactory.Core().V1().ConfigMaps()
+	service := operandFactory.Core().V1().Services()
+	secret := operandFactory.Core().V1().Secrets()
+	serviceaccount := operandFactory.Core().V1().ServiceAccounts()
 	webhook := factory.Admissionregistration().V1().MutatingWebhookConfigurations()
 	admissionpolicy := factory.Admissionregistration().V1().ValidatingAdmissionPolicies()
 	admissionpolicybinding := factory.Admissionregistration().V1().ValidatingAdmissionPolicyBindings()
 
-	apiServerConfigFactory := dynamicinformer.NewDynamicSharedInformerFactory(options.Client.RawDynamic, options.ResyncPeriod)
-	apiServerConfigInformer := apiServerConfigFactory.ForResource(tlsprofile.APIServerGVR).Informer()
+	apiserviceFactory := apiregistrationinformers.NewSharedInformerFactoryWithOptions(options.Client.APIRegistration, options.ResyncPeriod,
+		apiregistrationinformers.WithTweakListOptions(func(list *metav1.ListOptions) {
+			list.FieldSelector = fields.OneTermEqualSelector("metadata.name", options.APIServiceName).String()
+		}))
+	apiservice := apiserviceFactory.Apiregistration().V1().APIServices()
+
+	// only the metadata of the nodes is cached, the taints and the cordon
+	// are read from the nodes matching the node selector when reconciling.
+	var node informers.GenericInformer
+	var nodeLister metadatalister.Lister
+	metadataFactory := metadatainformer.NewSharedInformerFactory(options.Client.Metadata, options.ResyncPeriod)
+	if options.Nodes {
+		node = metadataFactory.ForResource(corev1.SchemeGroupVersion.WithResource("nodes"))
+		nodeLister = metadatalister.New(node.Informer().GetIndexer(), corev1.SchemeGroupVersion.WithResource("nodes"))
+	}
+
+	dynamicFactory := dynamicinformer.NewDynamicSharedInformerFactory(options.Client.RawDynamic, options.ResyncPeriod)
+
+	// the cluster TLS profile and the topology of the cluster.
+	var configInformers []cache.SharedIndexInformer
+	var infrastructureLister cache.GenericLister
+	if options.OpenShiftConfig {
+		infrastructureInformer := dynamicFactory.ForResource(infrastructure.InfrastructureGVR)
+		infrastructureLister = infrastructureInformer.Lister()
+		configInformers = append(configInformers,
+			dynamicFactory.ForResource(tlsprofile.APIServerGVR).Informer(),
+			infrastructureInformer.Informer(),
+		)
+	}
 
 	startFunc = func(enqueuer runtime.Enqueuer, shutdown context.Context) error {
 		handler := newResourceEventHandler(enqueuer)
@@ -61,26 +124,57 @@ func New(options *Options) (lister *Lister, startFunc StarterFunc) {
 		webhook.Informer().AddEventHandler(handler)
 		admissionpolicy.Informer().AddEventHandler(handler)
 		admissionpolicybinding.Informer().AddEventHandler(handler)
+		apiservice.Informer().AddEventHandler(handler)
 
 		if directEnqueuer, ok := enqueuer.(runtime.DirectEnqueuer); ok && options.PrimaryResourceName != "" {
-			apiServerConfigInformer.AddEventHandler(
-				newDirectEnqueueHandler(directEnqueuer, options.PrimaryResourceName),
-			)
+			for _, informer := range configInformers {
+				informer.AddEventHandler(newDirectEnqueueHandler(directEnqueuer, options.PrimaryResourceName))
+			}
+
+			// the replicas of the admission webhook follow the schedulable nodes.
+			if node != nil {
+				node.Informer().AddEventHandler(newNodeEventHandler(directEnqueuer, options.PrimaryResourceName))
+			}
+
+			// the serving Secret is owned by the Service, the service-ca operator
+			// updates it when the serving certificate is rotated.
+			secret.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+				FilterFunc: isServingSecret,
+				Handler:    newDirectEnqueueHandler(directEnqueuer, options.PrimaryResourceName),
+			})
 		} else {
 			klog.Warning("[secondarywatch] enqueuer does not implement DirectEnqueuer or PrimaryResourceName is unset; " +
-				"cluster APIServer TLS profile changes will not trigger reconciliation")
+				"cluster APIServer TLS profile, Infrastructure topology, node changes and serving certificate rotations will not trigger reconciliation")
 		}
 
-		factory.Start(shutdown.Done())
-		status := factory.WaitForCacheSync(shutdown.Done())
-		if names := check(status); len(names) > 0 {
+		for _, f := range []informers.SharedInformerFactory{operandFactory, factory} {
+			f.Start(shutdown.Done())
+			status := f.WaitForCacheSync(shutdown.Done())
+			if names := check(status); len(names) > 0 {
+				return fmt.Errorf("WaitForCacheSync did not successfully complete resources=%s", names)
+			}
+		}
+
+		apiserviceFactory.Start(shutdown.Done())
+		if names := check(apiserviceFactory.WaitForCacheSync(shutdown.Done())); len(names) > 0 {
 			return fmt.Errorf("WaitForCacheSync did not successfully complete resources=%s", names)
 		}
 
-		apiServerConfigFactory.Start(shutdown.Done())
-		if synced := apiServerConfigFactory.WaitForCacheSync(shutdown.Done()); !synced[tlsprofile.APIServerGVR] {
+		metadataFactory.Start(shutdown.Done())
+		for resource, synced := range metadataFactory.WaitForCacheSync(shutdown.Done()) {
+			if !synced {
+				return fmt.Errorf("WaitForCacheSync did not successfully complete resources=%s", resource.Resource)
+			}
+		}
+
+		dynamicFactory.Start(shutdown.Done())
+		synced := dynamicFactory.WaitForCacheSync(shutdown.Done())
+		if options.OpenShiftConfig && !synced[tlsprofile.APIServerGVR] {
 			klog.Warning("[secondarywatch] APIServer config informer cache did not sync; TLS profile watch may be delayed")
 		}
+		if options.OpenShiftConfig && !synced[infrastructure.InfrastructureGVR] {
+			klog.Warning("[secondarywatch] Infrastructure informer cache did not sync; platform changes may be detected late")
+		}
 
 		return nil
 	}
@@ -96,11 +190,23 @@ func New(options *Options) (lister *Lister, startFunc StarterFunc) {
 		webhook:                webhook.Lister(),
 		admissionpolicy:        admissionpolicy.Lister(),
 		admissionpolicybinding: admissionpolicybinding.Lister(),
+		apiservice:             apiservice.Lister(),
+		node:                   nodeLister,
+		infrastructure:         infrastructureLister,
 	}
 
 	return
 }
 
+func isServingSecret(obj interface{}) bool {
+	metaObj, err := runtime.GetMetaObject(obj)
+	if err != nil {
+		return false
+	}
+
+	return strings.HasPrefix(metaObj.GetName(), asset.SecretNamePrefix+"-")
+}
+
 func check(status map[reflect.Type]bool) []string {
 	names := make([]string, 0)
 
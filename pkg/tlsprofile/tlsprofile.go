@@ -48,16 +48,29 @@ func Fetch(ctx context.Context, dynClient dynamic.Interface) (Args, error) {
 		return Args{}, nil
 	}
 
-	profile, err := tlspkg.GetTLSProfileSpec(apiServer.Spec.TLSSecurityProfile)
+	args, _, err := FromProfile(apiServer.Spec.TLSSecurityProfile)
+	return args, err
+}
+
+// FromProfile returns the arguments for the given TLS security profile, the
+// Intermediate profile if nil. The cipher(s) that are not supported are left
+// out of the arguments and returned.
+func FromProfile(securityProfile *configv1.TLSSecurityProfile) (Args, []string, error) {
+	profile, err := tlspkg.GetTLSProfileSpec(securityProfile)
 	if err != nil {
-		return Args{}, fmt.Errorf("extracting TLS profile: %w", err)
+		return Args{}, nil, fmt.Errorf("extracting TLS profile: %w", err)
+	}
+
+	// NewTLSConfigFromProfile does not return an invalid version, it panics.
+	if _, err := libgocrypto.TLSVersion(string(profile.MinTLSVersion)); err != nil {
+		return Args{}, nil, fmt.Errorf("invalid minTLSVersion: %w", err)
 	}
 
-	tlsConfigFn, _ := tlspkg.NewTLSConfigFromProfile(profile)
+	tlsConfigFn, unsupported := tlspkg.NewTLSConfigFromProfile(profile)
 	cfg := &tls.Config{}
 	tlsConfigFn(cfg)
 
-	return ArgsFromTLSConfig(cfg), nil
+	return ArgsFromTLSConfig(cfg), unsupported, nil
 }
 
 func ArgsFromTLSConfig(cfg *tls.Config) Args {
@@ -13,11 +13,13 @@ import (
 
 	autoscalingv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/autoscaling/v1"
 	operatorv1 "github.com/openshift/cluster-resource-override-admission-operator/pkg/apis/operator/v1"
+	"github.com/openshift/cluster-resource-override-admission-operator/pkg/asset"
 	"github.com/openshift/cluster-resource-override-admission-operator/test/helper"
 )
 
 const (
-	configMapName = "clusterresourceoverride-configuration"
+	configMapName  = "clusterresourceoverride-configuration"
+	probeNamespace = "clusterresourceoverride-admission-probe"
 )
 
 // operatorNamespace is set from the --namespace flag (or OPERATOR_NAMESPACE env var as
@@ -498,6 +500,39 @@ func TestClusterResourceOverrideAdmissionWithNoOptIn(t *testing.T) {
 	helper.MustMatchMemoryAndCPU(t, resourceWant, &podGot.Spec)
 }
 
+func TestClusterResourceOverrideAdmissionProbe(t *testing.T) {
+	client := helper.NewClient(t, options.config)
+
+	f := &helper.PreCondition{Client: client.Kubernetes}
+	f.MustHaveAdmissionRegistrationV1(t)
+
+	override := operatorv1.PodResourceOverride{
+		Spec: operatorv1.PodResourceOverrideSpec{
+			LimitCPUToMemoryPercent:     200,
+			CPURequestToLimitPercent:    50,
+			MemoryRequestToLimitPercent: 50,
+		},
+	}
+
+	current, changed := helper.EnsureAdmissionWebhook(t, client.Operator, "cluster", override, nil)
+	defer helper.RemoveAdmissionWebhook(t, client.Operator, current.GetName())
+
+	current = helper.Wait(t, client.Operator, "cluster", helper.GetAvailableConditionFunc(current, changed))
+
+	// the probe namespace must not be exempt from the admission webhook,
+	// otherwise the probe pod is never mutated.
+	ns, err := client.Kubernetes.CoreV1().Namespaces().Get(context.TODO(), probeNamespace, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.False(t, asset.IsExemptNamespace(ns.GetName()))
+	require.Equal(t, "true", ns.GetLabels()[asset.NamespaceOptInLabelKey])
+
+	current = helper.Wait(t, client.Operator, "cluster", func(current *operatorv1.ClusterResourceOverride) bool {
+		probe := current.Status.AdmissionProbe
+		return probe != nil && probe.Result == operatorv1.AdmissionProbeSucceeded
+	})
+	require.Empty(t, current.Status.AdmissionProbe.Reason, current.Status.AdmissionProbe.Message)
+}
+
 func TestClusterResourceOverrideDeploymentOverrides(t *testing.T) {
 	client := helper.NewClient(t, options.config)
 